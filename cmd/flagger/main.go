@@ -24,14 +24,24 @@ import (
 	"os"
 	"strings"
 	"time"
+	// embedded time zone database used by the release policies
+	_ "time/tzdata"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/leaderelection"
@@ -40,6 +50,7 @@ import (
 	_ "k8s.io/code-generator/cmd/client-gen/generators"
 	"k8s.io/klog/v2"
 
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 	"github.com/fluxcd/flagger/pkg/canary"
 	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
 	informers "github.com/fluxcd/flagger/pkg/client/informers/externalversions"
@@ -74,6 +85,8 @@ var (
 	slackChannel             string
 	eventWebhook             string
 	threadiness              int
+	schedulerWorkers         int
+	schedulerJitter          float64
 	zapReplaceGlobals        bool
 	zapEncoding              string
 	namespace                string
@@ -88,6 +101,10 @@ var (
 	kubeconfigServiceMesh    string
 	clusterName              string
 	noCrossNamespaceRefs     bool
+	shardKey                 string
+	releasePolicy            string
+	maxConcurrentCanaries    int
+	maxConcurrentCanariesNs  int
 )
 
 func init() {
@@ -109,6 +126,8 @@ func init() {
 	flag.StringVar(&msteamsProxyURL, "msteams-proxy-url", "", "MS Teams proxy URL.")
 	flag.StringVar(&includeLabelPrefix, "include-label-prefix", "", "List of prefixes of labels that are copied when creating primary deployments or daemonsets. Use * to include all.")
 	flag.IntVar(&threadiness, "threadiness", 2, "Worker concurrency.")
+	flag.IntVar(&schedulerWorkers, "scheduler-workers", 10, "Number of canaries analysed concurrently.")
+	flag.Float64Var(&schedulerJitter, "scheduler-jitter", 0.1, "Maximum fraction of the analysis interval added at random to each canary run.")
 	flag.BoolVar(&zapReplaceGlobals, "zap-replace-globals", false, "Whether to change the logging level of the global zap logger.")
 	flag.StringVar(&zapEncoding, "zap-encoding", "json", "Zap logger encoding.")
 	flag.StringVar(&namespace, "namespace", "", "Namespace that flagger would watch canary object.")
@@ -123,6 +142,10 @@ func init() {
 	flag.StringVar(&kubeconfigServiceMesh, "kubeconfig-service-mesh", "", "Path to a kubeconfig for the service mesh control plane cluster.")
 	flag.StringVar(&clusterName, "cluster-name", "", "Cluster name to be included in alert msgs.")
 	flag.BoolVar(&noCrossNamespaceRefs, "no-cross-namespace-refs", false, "When set to true, Flagger can only refer to resources in the same namespace.")
+	flag.StringVar(&shardKey, "shard-key", "", "Process only the canaries labeled with flagger.app/shard set to this value. When empty, the canaries without the shard label are processed.")
+	flag.StringVar(&releasePolicy, "release-policy", "", "Config map holding the release windows and freezes, in the namespace/name format.")
+	flag.IntVar(&maxConcurrentCanaries, "max-concurrent-canaries", 0, "Maximum number of canaries running at once, the new revisions wait in FIFO order for a free slot. When zero, the number of running canaries is not limited.")
+	flag.IntVar(&maxConcurrentCanariesNs, "max-concurrent-canaries-per-namespace", 0, "Maximum number of canaries running at once in each namespace. When zero, the number of running canaries is not limited.")
 }
 
 func main() {
@@ -173,6 +196,18 @@ func main() {
 		logger.Fatalf("Error building knative clientset: %s", err.Error())
 	}
 
+	metadataClient, err := metadata.NewForConfig(cfg)
+	if err != nil {
+		logger.Fatalf("Error building metadata client: %v", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		logger.Fatalf("Error building dynamic client: %v", err)
+	}
+	// the custom resource targets are mapped to their API resources with the discovery client
+	restMapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClient.Discovery()))
+
 	// use a remote cluster for routing if a service mesh kubeconfig is specified
 	if kubeconfigServiceMesh == "" {
 		kubeconfigServiceMesh = kubeconfig
@@ -190,15 +225,39 @@ func main() {
 		logger.Fatalf("Error building mesh clientset: %v", err)
 	}
 
+	// the shard key is used as label value and in the leader election lease name
+	if errs := validation.IsDNS1123Label(shardKey); shardKey != "" && len(errs) > 0 {
+		logger.Fatalf("Invalid shard key %s: %s", shardKey, strings.Join(errs, ", "))
+	}
+	if shardKey != "" {
+		logger.Infof("Processing the canaries of shard %s", shardKey)
+	}
+
+	if releasePolicy != "" {
+		if ns, _, err := cache.SplitMetaNamespaceKey(releasePolicy); err != nil || ns == "" {
+			logger.Fatalf("Invalid release policy %s, the format is namespace/name", releasePolicy)
+		}
+		logger.Infof("Holding new canary runs according to the release policy %s", releasePolicy)
+	}
+
 	verifyCRDs(flaggerClient, logger)
 	verifyKubernetesVersion(kubeClient, logger)
 	infos := startInformers(flaggerClient, logger, stopCh)
+	startTargetInformers(&infos, kubeClient, metadataClient, logger, stopCh)
 
 	labels := strings.Split(selectorLabels, ",")
 	if len(labels) < 1 {
 		logger.Fatalf("At least one selector label is required")
 	}
 
+	if schedulerWorkers < 1 {
+		logger.Fatalf("At least one scheduler worker is required")
+	}
+
+	if maxConcurrentCanaries < 0 || maxConcurrentCanariesNs < 0 {
+		logger.Fatalf("The maximum number of concurrent canaries can't be negative")
+	}
+
 	if namespace != "" {
 		logger.Infof("Watching namespace %s", namespace)
 	}
@@ -236,6 +295,8 @@ func main() {
 			Logger:        logger,
 			KubeClient:    kubeClient,
 			FlaggerClient: flaggerClient,
+			DynamicClient: dynamicClient,
+			RESTMapper:    restMapper,
 		}
 	} else {
 		configTracker = &canary.NopTracker{}
@@ -243,7 +304,7 @@ func main() {
 
 	includeLabelPrefixArray := strings.Split(includeLabelPrefix, ",")
 
-	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, knativeClient, configTracker, labels, includeLabelPrefixArray, logger)
+	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, knativeClient, dynamicClient, restMapper, configTracker, labels, includeLabelPrefixArray, logger)
 
 	c := controller.NewController(
 		kubeClient,
@@ -262,6 +323,12 @@ func main() {
 		clusterName,
 		noCrossNamespaceRefs,
 		cfg,
+		schedulerWorkers,
+		schedulerJitter,
+		shardKey,
+		releasePolicy,
+		maxConcurrentCanaries,
+		maxConcurrentCanariesNs,
 	)
 
 	// leader election context
@@ -299,8 +366,15 @@ func main() {
 func startInformers(flaggerClient clientset.Interface, logger *zap.SugaredLogger, stopCh <-chan struct{}) controller.Informers {
 	flaggerInformerFactory := informers.NewSharedInformerFactoryWithOptions(flaggerClient, time.Second*30, informers.WithNamespace(namespace))
 
+	// watch only the canaries of this shard
+	canaryInformerFactory := informers.NewSharedInformerFactoryWithOptions(flaggerClient, time.Second*30,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = shardSelector(shardKey)
+		}))
+
 	logger.Info("Waiting for canary informer cache to sync")
-	canaryInformer := flaggerInformerFactory.Flagger().V1beta1().Canaries()
+	canaryInformer := canaryInformerFactory.Flagger().V1beta1().Canaries()
 	go canaryInformer.Informer().Run(stopCh)
 	if ok := cache.WaitForNamedCacheSync("flagger", stopCh, canaryInformer.Informer().HasSynced); !ok {
 		logger.Fatalf("failed to wait for cache to sync")
@@ -327,8 +401,47 @@ func startInformers(flaggerClient clientset.Interface, logger *zap.SugaredLogger
 	}
 }
 
+// startTargetInformers watches the workloads and, when config tracking is enabled,
+// the metadata of config maps and secrets to react to target changes
+func startTargetInformers(infos *controller.Informers, kubeClient kubernetes.Interface, metadataClient metadata.Interface,
+	logger *zap.SugaredLogger, stopCh <-chan struct{}) {
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	infos.DeploymentInformer = kubeInformerFactory.Apps().V1().Deployments().Informer()
+	infos.DaemonSetInformer = kubeInformerFactory.Apps().V1().DaemonSets().Informer()
+	infos.StatefulSetInformer = kubeInformerFactory.Apps().V1().StatefulSets().Informer()
+	synced := []cache.InformerSynced{infos.DeploymentInformer.HasSynced, infos.DaemonSetInformer.HasSynced,
+		infos.StatefulSetInformer.HasSynced}
+
+	if enableConfigTracking {
+		metadataInformerFactory := metadatainformer.NewFilteredSharedInformerFactory(metadataClient, 0, namespace, nil)
+		infos.ConfigMapInformer = metadataInformerFactory.ForResource(corev1.SchemeGroupVersion.WithResource("configmaps")).Informer()
+		infos.SecretInformer = metadataInformerFactory.ForResource(corev1.SchemeGroupVersion.WithResource("secrets")).Informer()
+		synced = append(synced, infos.ConfigMapInformer.HasSynced, infos.SecretInformer.HasSynced)
+		metadataInformerFactory.Start(stopCh)
+	}
+	kubeInformerFactory.Start(stopCh)
+
+	logger.Info("Waiting for target informers cache to sync")
+	if ok := cache.WaitForNamedCacheSync("flagger", stopCh, synced...); !ok {
+		logger.Fatalf("failed to wait for cache to sync")
+	}
+}
+
+// shardSelector returns the label selector matching the canaries of a shard,
+// the canaries without a shard label belong to the instances started without a shard key
+func shardSelector(key string) string {
+	if key == "" {
+		return "!" + flaggerv1.ShardLabel
+	}
+	return fmt.Sprintf("%s=%s", flaggerv1.ShardLabel, key)
+}
+
 func startLeaderElection(ctx context.Context, run func(), ns string, kubeClient kubernetes.Interface, logger *zap.SugaredLogger) {
+	// each shard elects its own leader
 	configMapName := "flagger-leader-election"
+	if shardKey != "" {
+		configMapName = fmt.Sprintf("%s-%s", configMapName, shardKey)
+	}
 	id, err := os.Hostname()
 	if err != nil {
 		logger.Fatalf("Error running controller: %v", err)
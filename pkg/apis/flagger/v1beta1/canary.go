@@ -23,6 +23,7 @@ import (
 	"github.com/fluxcd/flagger/pkg/apis/gatewayapi/v1beta1"
 	istiov1beta1 "github.com/fluxcd/flagger/pkg/apis/istio/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -33,6 +34,32 @@ const (
 	PrimaryReadyThreshold   = 100
 	CanaryReadyThreshold    = 100
 	MetricInterval          = "1m"
+	ScoringPass             = 95
+	ScoringMarginal         = 75
+	ScoringConfidenceLevel  = 0.95
+	ScoringStep             = 10 * time.Second
+	RevisionHistoryLimit    = 3
+
+	// ResumeAnnotation resumes the advancement of a canary paused at a step or with the pause annotation
+	ResumeAnnotation = "flagger.app/resume"
+
+	// PauseAnnotation freezes the canary weight until the resume annotation is set
+	PauseAnnotation = "flagger.app/pause"
+
+	// PromoteAnnotation promotes the canary skipping the remaining analysis
+	PromoteAnnotation = "flagger.app/promote"
+
+	// AbortAnnotation rolls back the canary
+	AbortAnnotation = "flagger.app/abort"
+
+	// RollbackToAnnotation restores the primary to the promoted revision set as value
+	RollbackToAnnotation = "flagger.app/rollback-to"
+
+	// RejectedRevisionAnnotation records on the target the hash of the spec that failed the analysis
+	RejectedRevisionAnnotation = "flagger.app/rejected-revision"
+
+	// ShardLabel assigns the canary to the Flagger instance started with the same shard key
+	ShardLabel = "flagger.app/shard"
 )
 
 // +genclient
@@ -72,6 +99,16 @@ type CanarySpec struct {
 	// TargetRef references a target resource
 	TargetRef LocalObjectReference `json:"targetRef"`
 
+	// AdditionalTargetRefs references workloads rolled out together with the target,
+	// they are analysed, promoted and rolled back with the target but receive no traffic shifting
+	// +optional
+	AdditionalTargetRefs []LocalObjectReference `json:"additionalTargetRefs,omitempty"`
+
+	// TargetPaths locates the pod template, replicas, selector and rollout status
+	// of a custom resource target such as an OpenKruise CloneSet
+	// +optional
+	TargetPaths *TargetPaths `json:"targetPaths,omitempty"`
+
 	// AutoscalerRef references an autoscaling resource
 	// +optional
 	AutoscalerRef *AutoscalerRefernce `json:"autoscalerRef,omitempty"`
@@ -107,6 +144,27 @@ type CanarySpec struct {
 	// +optional
 	SkipAnalysis bool `json:"skipAnalysis,omitempty"`
 
+	// RevisionHistoryLimit is the number of promoted revisions kept for rollback (default 3)
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// RevertOnFailure restores the target pod template to the last promoted spec after a failed analysis
+	// +optional
+	RevertOnFailure bool `json:"revertOnFailure,omitempty"`
+
+	// IgnoreChanges lists the target pod template changes that don't start a canary analysis
+	// +optional
+	IgnoreChanges *CanaryIgnoreChanges `json:"ignoreChanges,omitempty"`
+
+	// DependsOn lists the canaries that must finish their promotion
+	// before a new revision of this canary is analysed
+	// +optional
+	DependsOn []CrossNamespaceObjectReference `json:"dependsOn,omitempty"`
+
+	// DependencyFailurePolicy blocks or rolls back this canary when a dependency fails (default Block)
+	// +optional
+	DependencyFailurePolicy DependencyFailurePolicy `json:"dependencyFailurePolicy,omitempty"`
+
 	// revert canary mutation on deletion of canary resource
 	// +optional
 	RevertOnDeletion bool `json:"revertOnDeletion,omitempty"`
@@ -118,6 +176,134 @@ type CanarySpec struct {
 	Suspend bool `json:"suspend,omitempty"`
 }
 
+// DependencyFailurePolicy defines what happens to a canary when one of its dependencies fails
+type DependencyFailurePolicy string
+
+const (
+	// DependencyFailureBlock keeps the canary waiting until the dependency succeeds
+	DependencyFailureBlock DependencyFailurePolicy = "Block"
+	// DependencyFailureRollback fails the canary and routes all traffic back to the primary
+	DependencyFailureRollback DependencyFailurePolicy = "Rollback"
+)
+
+// CanaryFastTrack maps the classes of the target changes to analysis profiles,
+// the classes that aren't set use the Full profile
+type CanaryFastTrack struct {
+	// Profile of the changes to the tracked ConfigMaps and Secrets only
+	// +optional
+	ConfigOnly AnalysisProfile `json:"configOnly,omitempty"`
+
+	// Profile of the container image changes within the same major.minor version
+	// +optional
+	ImageTagPatch AnalysisProfile `json:"imageTagPatch,omitempty"`
+
+	// Profile of the container resources changes only
+	// +optional
+	ResourcesOnly AnalysisProfile `json:"resourcesOnly,omitempty"`
+
+	// Profile of all the other changes
+	// +optional
+	Other AnalysisProfile `json:"other,omitempty"`
+
+	// Schedule of the Short profile, overrides the analysis settings that are set
+	// +optional
+	Short *CanaryShortSchedule `json:"short,omitempty"`
+}
+
+// CanaryShortSchedule holds the analysis settings of the Short profile
+type CanaryShortSchedule struct {
+	// Schedule interval of the analysis
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Number of checks to run for A/B Testing and Blue/Green
+	// +optional
+	Iterations int `json:"iterations,omitempty"`
+
+	// Max traffic weight routed to canary
+	// +optional
+	MaxWeight int `json:"maxWeight,omitempty"`
+
+	// Incremental traffic weight step
+	// +optional
+	StepWeight int `json:"stepWeight,omitempty"`
+
+	// Incremental traffic weight steps
+	// +optional
+	StepWeights []int `json:"stepWeights,omitempty"`
+}
+
+// ChangeClass is the class of a target change
+type ChangeClass string
+
+const (
+	// ChangeConfigOnly means only the tracked ConfigMaps and Secrets changed
+	ChangeConfigOnly ChangeClass = "ConfigOnly"
+	// ChangeImageTagPatch means only the container images changed,
+	// to a different patch version of the same repository
+	ChangeImageTagPatch ChangeClass = "ImageTagPatch"
+	// ChangeResourcesOnly means only the container resources changed
+	ChangeResourcesOnly ChangeClass = "ResourcesOnly"
+	// ChangeOther means any other change
+	ChangeOther ChangeClass = "Other"
+)
+
+// AnalysisProfile is the analysis schedule applied to a class of changes
+type AnalysisProfile string
+
+const (
+	// AnalysisProfileSkip promotes the change without an analysis
+	AnalysisProfileSkip AnalysisProfile = "Skip"
+	// AnalysisProfileShort runs the analysis with the fast-track short schedule
+	AnalysisProfileShort AnalysisProfile = "Short"
+	// AnalysisProfileFull runs the analysis with the full schedule
+	AnalysisProfileFull AnalysisProfile = "Full"
+)
+
+// CanaryIgnoreChanges defines the pod template fields excluded from the change detection,
+// the target changes that touch only these fields are applied to the primary without an analysis
+type CanaryIgnoreChanges struct {
+	// Paths of the pod template fields in the `.spec.terminationGracePeriodSeconds` form
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+
+	// Annotations keys of the pod template
+	// +optional
+	Annotations []string `json:"annotations,omitempty"`
+
+	// Labels keys of the pod template
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Containers names of the pod template, the volumes mounted only by these containers are ignored too
+	// +optional
+	Containers []string `json:"containers,omitempty"`
+}
+
+// TargetPaths defines the JSONPaths of the pod template, replicas, selector and rollout status fields
+// of a custom resource target, the paths are in the `.spec.template` form and can't index arrays
+type TargetPaths struct {
+	// Template is the path of the pod template (default .spec.template)
+	// +optional
+	Template string `json:"template,omitempty"`
+
+	// Replicas is the path of the desired replicas (default .spec.replicas)
+	// +optional
+	Replicas string `json:"replicas,omitempty"`
+
+	// Selector is the path of the pod label selector (default .spec.selector)
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// ReadyReplicas is the path of the ready replicas count (default .status.readyReplicas)
+	// +optional
+	ReadyReplicas string `json:"readyReplicas,omitempty"`
+
+	// UpdatedReplicas is the path of the updated replicas count (default .status.updatedReplicas)
+	// +optional
+	UpdatedReplicas string `json:"updatedReplicas,omitempty"`
+}
+
 // CanaryService defines how ClusterIP services, service mesh or ingress routing objects are generated
 type CanaryService struct {
 	// Name of the Kubernetes service generated by Flagger
@@ -254,6 +440,11 @@ type CanaryAnalysis struct {
 	// +optional
 	StepWeights []int `json:"stepWeights,omitempty"`
 
+	// Traffic weight steps for analysis phase, each step can hold its weight
+	// for a minimum duration, a number of analysis runs or until it's resumed
+	// +optional
+	Steps []CanaryStep `json:"steps,omitempty"`
+
 	// Incremental traffic weight step for promotion phase
 	// +optional
 	StepWeightPromotion int `json:"stepWeightPromotion,omitempty"`
@@ -261,6 +452,10 @@ type CanaryAnalysis struct {
 	// Max number of failed checks before the canary is terminated
 	Threshold int `json:"threshold"`
 
+	// Max number of inconclusive checks, due to too few metric samples, before the canary is terminated
+	// +optional
+	MaxInconclusive int `json:"maxInconclusive,omitempty"`
+
 	// Percentage of pods that need to be available to consider primary as ready
 	PrimaryReadyThreshold *int `json:"primaryReadyThreshold,omitempty"`
 
@@ -274,6 +469,41 @@ type CanaryAnalysis struct {
 	// +optional
 	Metrics []CanaryMetric `json:"metrics,omitempty"`
 
+	// Create a baseline workload from the primary spec, scaled as the canary and
+	// receiving the same traffic weight, to compare the canary metrics against
+	// +optional
+	Baseline bool `json:"baseline,omitempty"`
+
+	// Statistical scoring of the metrics, when set the primary and canary
+	// time series are compared instead of checking the metrics thresholds
+	// +optional
+	Scoring *CanaryScoring `json:"scoring,omitempty"`
+
+	// Size the canary replicas proportionally to the traffic weight instead of
+	// scaling the canary to the target replicas
+	// +optional
+	ReplicaScaling *CanaryReplicaScaling `json:"replicaScaling,omitempty"`
+
+	// Rolling update settings and progress deadline of the primary during the promotion,
+	// the primary is restored to the previous revision if its rollout stalls
+	// +optional
+	PrimaryRollout *CanaryPrimaryRollout `json:"primaryRollout,omitempty"`
+
+	// Verification of the primary after the promotion, the primary is restored
+	// to the previously promoted revision if the metrics checks fail
+	// +optional
+	PostPromotion *CanaryPostPromotion `json:"postPromotion,omitempty"`
+
+	// Warm-up of the canary before the first metric check, the metrics
+	// are collected but not judged during the warm-up
+	// +optional
+	Warmup *CanaryWarmup `json:"warmup,omitempty"`
+
+	// Analysis profiles of the target change classes, the low-risk changes
+	// can skip the analysis or run it on a shorter schedule
+	// +optional
+	FastTrack *CanaryFastTrack `json:"fastTrack,omitempty"`
+
 	// Webhook list for this canary  analysis
 	// +optional
 	Webhooks []CanaryWebhook `json:"webhooks,omitempty"`
@@ -287,6 +517,95 @@ type CanaryAnalysis struct {
 	SessionAffinity *SessionAffinity `json:"sessionAffinity,omitempty"`
 }
 
+// CanaryScoring holds the settings for the statistical analysis of the metrics
+type CanaryScoring struct {
+	// Minimum score in the range of [0, 100] for the analysis to pass (default 95)
+	// +optional
+	Pass *float64 `json:"pass,omitempty"`
+
+	// Minimum score in the range of [0, 100] for the analysis to be marginal (default 75),
+	// a marginal score halts the advancement without counting as a failed check
+	// +optional
+	Marginal *float64 `json:"marginal,omitempty"`
+
+	// Confidence level of the Mann-Whitney U test (default 0.95)
+	// +optional
+	ConfidenceLevel *float64 `json:"confidenceLevel,omitempty"`
+
+	// Resolution of the time series samples (default 10s)
+	// +optional
+	Step string `json:"step,omitempty"`
+}
+
+// CanaryReplicaScaling sizes the canary replicas as
+// ceil(primaryReplicas * weight/100 * headroom) with a minimum
+type CanaryReplicaScaling struct {
+	// Multiplier of the replicas proportional to the weight (default 1)
+	// +optional
+	Headroom *float64 `json:"headroom,omitempty"`
+
+	// Minimum number of canary replicas (default 1)
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+}
+
+// CanaryPrimaryRollout holds the settings of the primary rollout during the promotion
+type CanaryPrimaryRollout struct {
+	// Max number of primary pods created above the desired replicas during the promotion
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// Max number of primary pods unavailable during the promotion
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Max number of seconds the primary rollout can make no progress
+	// before the promotion is aborted (defaults to the canary progressDeadlineSeconds)
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// CanaryStep holds the traffic weight and the advancement conditions of an analysis step
+type CanaryStep struct {
+	// Traffic weight routed to the canary in the range of [1, 100]
+	Weight int `json:"weight"`
+
+	// Minimum duration to hold the weight before advancing, e.g. 30m
+	// +optional
+	Duration string `json:"duration,omitempty"`
+
+	// Minimum number of successful analysis runs at this weight (default 1)
+	// +optional
+	Iterations int `json:"iterations,omitempty"`
+
+	// Pause the advancement at this weight until the canary
+	// is annotated with flagger.app/resume
+	// +optional
+	Pause bool `json:"pause,omitempty"`
+}
+
+// CanaryPostPromotion holds the settings of the post-promotion verification
+type CanaryPostPromotion struct {
+	// Duration of the verification window, e.g. 30m
+	Window string `json:"window"`
+}
+
+// CanaryWarmup holds the warm-up settings of the canary, the warm-up
+// lasts for the duration or the number of iterations
+type CanaryWarmup struct {
+	// Duration of the warm-up, e.g. 5m
+	// +optional
+	Duration string `json:"duration,omitempty"`
+
+	// Number of analysis intervals of the warm-up
+	// +optional
+	Iterations int `json:"iterations,omitempty"`
+
+	// Traffic weight routed to the canary during the warm-up (default 0)
+	// +optional
+	Weight int `json:"weight,omitempty"`
+}
+
 type SessionAffinity struct {
 	// CookieName is the key that will be used for the session affinity cookie.
 	CookieName string `json:"cookieName,omitempty"`
@@ -326,8 +645,45 @@ type CanaryMetric struct {
 	// TemplateVariables provides a map of key/value pairs that can be used to inject variables into a metric query.
 	// +optional
 	TemplateVariables map[string]string `json:"templateVariables,omitempty"`
+
+	// Comparison judges the canary value relative to the primary value
+	// instead of the absolute threshold
+	// +optional
+	Comparison *CanaryMetricComparison `json:"comparison,omitempty"`
+
+	// MinSampleQuery returns the number of samples of the metric, it's run with
+	// the metric provider and template variables before the check
+	// +optional
+	MinSampleQuery string `json:"minSampleQuery,omitempty"`
+
+	// MinSamples is the number of samples below which the check is inconclusive
+	// +optional
+	MinSamples int `json:"minSamples,omitempty"`
+
+	// FailurePolicy of the metric, can be warn, failFast or threshold
+	// Defaults to counting the failed checks towards the analysis threshold
+	// +optional
+	FailurePolicy MetricFailurePolicy `json:"failurePolicy,omitempty"`
+
+	// FailureThreshold is the number of failed checks of the metric before
+	// rolling back, used with the threshold failure policy
+	// +optional
+	FailureThreshold int `json:"failureThreshold,omitempty"`
 }
 
+// MetricFailurePolicy defines how a failed metric check is handled
+type MetricFailurePolicy string
+
+const (
+	// MetricFailureWarn records the failed check without halting the advancement
+	MetricFailureWarn MetricFailurePolicy = "warn"
+	// MetricFailureFailFast rolls back the canary on the first failed check
+	MetricFailureFailFast MetricFailurePolicy = "failFast"
+	// MetricFailureThreshold counts the failed checks of the metric separately
+	// and rolls back the canary when they reach the metric failure threshold
+	MetricFailureThreshold MetricFailurePolicy = "threshold"
+)
+
 // CanaryThresholdRange defines the range used for metrics validation
 type CanaryThresholdRange struct {
 	// Minimum value
@@ -339,6 +695,32 @@ type CanaryThresholdRange struct {
 	Max *float64 `json:"max,omitempty"`
 }
 
+// ComparisonDirection defines which change of a metric value is considered a regression
+type ComparisonDirection string
+
+const (
+	// ComparisonIncrease fails the canary when its value is higher than the primary one
+	ComparisonIncrease ComparisonDirection = "increase"
+	// ComparisonDecrease fails the canary when its value is lower than the primary one
+	ComparisonDecrease ComparisonDirection = "decrease"
+)
+
+// CanaryMetricComparison defines how the canary metric value is judged against the primary
+type CanaryMetricComparison struct {
+	// Direction of change considered a regression, can be increase or decrease
+	// Defaults to decrease for request-success-rate and to increase for all other metrics
+	// +optional
+	Direction ComparisonDirection `json:"direction,omitempty"`
+
+	// MaxRatio is the max accepted canary/primary ratio in the direction of regression
+	// +optional
+	MaxRatio *float64 `json:"maxRatio,omitempty"`
+
+	// MaxDelta is the max accepted absolute difference in the direction of regression
+	// +optional
+	MaxDelta *float64 `json:"maxDelta,omitempty"`
+}
+
 // AlertSeverity defines alert filtering based on severity levels
 type AlertSeverity string
 
@@ -474,6 +856,18 @@ func (l *LocalObjectReference) IsKnativeService() bool {
 	return false
 }
 
+// IsGenericWorkload returns true if the referent is a custom resource embedding a pod template,
+// the kinds of the apps and core API groups are handled by their own controllers
+func (l *LocalObjectReference) IsGenericWorkload() bool {
+	switch l.Kind {
+	case "", "Deployment", "DaemonSet", "StatefulSet", "Service":
+		return false
+	}
+
+	gv, err := schema.ParseGroupVersion(l.APIVersion)
+	return err == nil && gv.Group != "" && gv.Group != "apps"
+}
+
 type AutoscalerRefernce struct {
 	// API version of the scaler
 	// +required
@@ -567,6 +961,38 @@ func (c *Canary) GetServiceNames() (apexName, primaryName, canaryName string) {
 	return
 }
 
+// GetBaselineServiceName returns the name of the ClusterIP service selecting the baseline pods
+func (c *Canary) GetBaselineServiceName() string {
+	apexName, _, _ := c.GetServiceNames()
+	return fmt.Sprintf("%s-baseline", apexName)
+}
+
+// HasBaseline returns true if the analysis runs a baseline workload
+func (c *Canary) HasBaseline() bool {
+	return c.GetAnalysis() != nil && c.GetAnalysis().Baseline
+}
+
+// HasPostPromotion returns true if the primary is verified after the promotion
+func (c *Canary) HasPostPromotion() bool {
+	return c.GetAnalysis() != nil && c.GetAnalysis().PostPromotion != nil
+}
+
+// GetRevisionHistoryLimit returns the number of promoted revisions kept for rollback (default 3),
+// at least two revisions are kept when the primary is verified after the promotion
+func (c *Canary) GetRevisionHistoryLimit() int {
+	limit := RevisionHistoryLimit
+	if c.Spec.RevisionHistoryLimit != nil {
+		limit = int(*c.Spec.RevisionHistoryLimit)
+	}
+	if c.HasPostPromotion() && limit < 2 {
+		return 2
+	}
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
 // GetProgressDeadlineSeconds returns the progress deadline (default 600s)
 func (c *Canary) GetProgressDeadlineSeconds() int {
 	if c.Spec.ProgressDeadlineSeconds != nil {
@@ -587,11 +1013,16 @@ func (c *Canary) GetAnalysis() *CanaryAnalysis {
 
 // GetAnalysisInterval returns the canary analysis interval (default 60s)
 func (c *Canary) GetAnalysisInterval() time.Duration {
-	if c.GetAnalysis().Interval == "" {
+	value := c.GetAnalysis().Interval
+	if short := c.getFastTrackShort(); short != nil && short.Interval != "" {
+		value = short.Interval
+	}
+
+	if value == "" {
 		return AnalysisInterval
 	}
 
-	interval, err := time.ParseDuration(c.GetAnalysis().Interval)
+	interval, err := time.ParseDuration(value)
 	if err != nil {
 		return AnalysisInterval
 	}
@@ -603,6 +1034,70 @@ func (c *Canary) GetAnalysisInterval() time.Duration {
 	return interval
 }
 
+// ApplyFastTrack overrides the analysis schedule with the fast-track short schedule
+// when the Short profile has been chosen for the current revision, the overrides
+// are meant for a copy of the canary as the status updates ignore the spec
+func (c *Canary) ApplyFastTrack() {
+	short := c.getFastTrackShort()
+	if short == nil {
+		return
+	}
+
+	analysis := c.GetAnalysis()
+	if short.Interval != "" {
+		analysis.Interval = short.Interval
+	}
+	if short.Iterations > 0 {
+		analysis.Iterations = short.Iterations
+	}
+	if short.MaxWeight > 0 {
+		analysis.MaxWeight = short.MaxWeight
+	}
+	if short.StepWeight > 0 || len(short.StepWeights) > 0 {
+		analysis.StepWeight = short.StepWeight
+		analysis.StepWeights = short.StepWeights
+		analysis.Steps = nil
+	}
+}
+
+// GetFastTrackProfile returns the analysis profile of a change class (default Full)
+func (c *Canary) GetFastTrackProfile(class ChangeClass) AnalysisProfile {
+	var profile AnalysisProfile
+	if analysis := c.GetAnalysis(); analysis != nil && analysis.FastTrack != nil {
+		switch class {
+		case ChangeConfigOnly:
+			profile = analysis.FastTrack.ConfigOnly
+		case ChangeImageTagPatch:
+			profile = analysis.FastTrack.ImageTagPatch
+		case ChangeResourcesOnly:
+			profile = analysis.FastTrack.ResourcesOnly
+		default:
+			profile = analysis.FastTrack.Other
+		}
+	}
+
+	if profile == "" {
+		return AnalysisProfileFull
+	}
+	return profile
+}
+
+// FastTrackSkipsAnalysis returns true if the Skip profile has been chosen for the current revision
+func (c *Canary) FastTrackSkipsAnalysis() bool {
+	analysis := c.GetAnalysis()
+	return analysis != nil && analysis.FastTrack != nil &&
+		c.Status.FastTrack != nil && c.Status.FastTrack.Profile == AnalysisProfileSkip
+}
+
+func (c *Canary) getFastTrackShort() *CanaryShortSchedule {
+	analysis := c.GetAnalysis()
+	if analysis == nil || analysis.FastTrack == nil ||
+		c.Status.FastTrack == nil || c.Status.FastTrack.Profile != AnalysisProfileShort {
+		return nil
+	}
+	return analysis.FastTrack.Short
+}
+
 // GetAnalysisThreshold returns the canary threshold (default 1)
 func (c *Canary) GetAnalysisThreshold() int {
 	if c.GetAnalysis().Threshold > 0 {
@@ -632,6 +1127,117 @@ func (c *Canary) GetMetricInterval() string {
 	return MetricInterval
 }
 
+// GetPass returns the minimum score for the analysis to pass (default 95)
+func (s *CanaryScoring) GetPass() float64 {
+	if s.Pass != nil {
+		return *s.Pass
+	}
+	return ScoringPass
+}
+
+// GetMarginal returns the minimum score for the analysis to be marginal (default 75)
+func (s *CanaryScoring) GetMarginal() float64 {
+	if s.Marginal != nil {
+		return *s.Marginal
+	}
+	return ScoringMarginal
+}
+
+// GetConfidenceLevel returns the confidence level of the statistical test (default 0.95)
+func (s *CanaryScoring) GetConfidenceLevel() float64 {
+	if s.ConfidenceLevel != nil && *s.ConfidenceLevel > 0 && *s.ConfidenceLevel < 1 {
+		return *s.ConfidenceLevel
+	}
+	return ScoringConfidenceLevel
+}
+
+// GetStep returns the resolution of the time series samples (default 10s)
+func (s *CanaryScoring) GetStep() time.Duration {
+	if s.Step == "" {
+		return ScoringStep
+	}
+	step, err := time.ParseDuration(s.Step)
+	if err != nil || step <= 0 {
+		return ScoringStep
+	}
+	return step
+}
+
+// GetHeadroom returns the multiplier of the replicas proportional to the weight (default 1)
+func (s *CanaryReplicaScaling) GetHeadroom() float64 {
+	if s.Headroom != nil && *s.Headroom > 0 {
+		return *s.Headroom
+	}
+	return 1
+}
+
+// GetMinReplicas returns the minimum number of canary replicas (default 1)
+func (s *CanaryReplicaScaling) GetMinReplicas() int32 {
+	if s.MinReplicas != nil && *s.MinReplicas > 0 {
+		return *s.MinReplicas
+	}
+	return 1
+}
+
+// GetPromotionProgressDeadlineSeconds returns the progress deadline of the primary rollout during the promotion
+func (c *Canary) GetPromotionProgressDeadlineSeconds() int {
+	if analysis := c.GetAnalysis(); analysis != nil && analysis.PrimaryRollout != nil &&
+		analysis.PrimaryRollout.ProgressDeadlineSeconds != nil {
+		return int(*analysis.PrimaryRollout.ProgressDeadlineSeconds)
+	}
+	return c.GetProgressDeadlineSeconds()
+}
+
+// GetDuration returns the minimum duration of the step (default 0)
+func (s *CanaryStep) GetDuration() time.Duration {
+	if s.Duration == "" {
+		return 0
+	}
+	duration, err := time.ParseDuration(s.Duration)
+	if err != nil || duration < 0 {
+		return 0
+	}
+	return duration
+}
+
+// GetIterations returns the minimum number of analysis runs of the step (default 1)
+func (s *CanaryStep) GetIterations() int {
+	if s.Iterations > 0 {
+		return s.Iterations
+	}
+	return 1
+}
+
+// GetWindow returns the duration of the post-promotion verification window
+func (p *CanaryPostPromotion) GetWindow() time.Duration {
+	window, err := time.ParseDuration(p.Window)
+	if err != nil || window < 0 {
+		return 0
+	}
+	return window
+}
+
+// GetDuration returns the duration of the warm-up
+func (w *CanaryWarmup) GetDuration() time.Duration {
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil || duration < 0 {
+		return 0
+	}
+	return duration
+}
+
+// GetComparisonDirection returns the direction of change considered a regression
+// (default decrease for request-success-rate, increase for all other metrics)
+func (m *CanaryMetric) GetComparisonDirection() ComparisonDirection {
+	if m.Comparison != nil && m.Comparison.Direction != "" {
+		return m.Comparison.Direction
+	}
+	if m.Name == "request-success-rate" {
+		return ComparisonDecrease
+	}
+	return ComparisonIncrease
+}
+
 // SkipAnalysis returns true if the analysis is nil
 // or if spec.SkipAnalysis is true
 func (c *Canary) SkipAnalysis() bool {
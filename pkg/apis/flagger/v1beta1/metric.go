@@ -90,6 +90,8 @@ type MetricTemplateModel struct {
 	Name      string            `json:"name"`
 	Namespace string            `json:"namespace"`
 	Target    string            `json:"target"`
+	Targets   string            `json:"targets"`
+	Baseline  string            `json:"baseline"`
 	Service   string            `json:"service"`
 	Ingress   string            `json:"ingress"`
 	Route     string            `json:"route"`
@@ -103,6 +105,8 @@ func (mtm *MetricTemplateModel) TemplateFunctions() template.FuncMap {
 		"name":      func() string { return mtm.Name },
 		"namespace": func() string { return mtm.Namespace },
 		"target":    func() string { return mtm.Target },
+		"targets":   func() string { return mtm.Targets },
+		"baseline":  func() string { return mtm.Baseline },
 		"service":   func() string { return mtm.Service },
 		"ingress":   func() string { return mtm.Ingress },
 		"route":     func() string { return mtm.Route },
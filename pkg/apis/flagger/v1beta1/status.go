@@ -11,6 +11,8 @@ type CanaryConditionType string
 const (
 	// PromotedType refers to the result of the last canary analysis
 	PromotedType CanaryConditionType = "Promoted"
+	// WarmedUpType refers to the warm-up of the canary before the first metric check
+	WarmedUpType CanaryConditionType = "WarmedUp"
 )
 
 // CanaryCondition is a status condition for a Canary
@@ -51,8 +53,17 @@ const (
 	CanaryPhaseWaitingPromotion CanaryPhase = "WaitingPromotion"
 	// CanaryPhasePromoting means the canary analysis is finished and the primary spec has been updated
 	CanaryPhasePromoting CanaryPhase = "Promoting"
+	// CanaryPhaseRestoring means the primary rollout stalled during the promotion, the traffic
+	// is routed to the canary while the primary is restored to the previously promoted revision
+	CanaryPhaseRestoring CanaryPhase = "Restoring"
 	// CanaryPhaseFinalising means the canary promotion is finished and traffic has been routed back to primary
 	CanaryPhaseFinalising CanaryPhase = "Finalising"
+	// CanaryPhaseVerifying means the canary promotion is finished and the
+	// analysis metrics are checked against the primary for the post-promotion window
+	CanaryPhaseVerifying CanaryPhase = "Verifying"
+	// CanaryPhaseReverted means the post-promotion verification failed
+	// and the primary has been restored to the previously promoted revision
+	CanaryPhaseReverted CanaryPhase = "Reverted"
 	// CanaryPhaseSucceeded means the canary analysis has been successful
 	// and the canary deployment has been promoted
 	CanaryPhaseSucceeded CanaryPhase = "Succeeded"
@@ -67,6 +78,14 @@ const (
 	CanaryPhaseTerminated CanaryPhase = "Terminated"
 )
 
+// CanaryFastTrackStatus records the analysis profile chosen for the current revision
+type CanaryFastTrackStatus struct {
+	Class   ChangeClass     `json:"class"`
+	Profile AnalysisProfile `json:"profile"`
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
 // CanaryStatus is used for state persistence (read-only)
 type CanaryStatus struct {
 	Phase        CanaryPhase `json:"phase"`
@@ -74,6 +93,12 @@ type CanaryStatus struct {
 	CanaryWeight int         `json:"canaryWeight"`
 	Iterations   int         `json:"iterations"`
 	// +optional
+	InconclusiveChecks int `json:"inconclusiveChecks,omitempty"`
+	// +optional
+	WarmupIterations int `json:"warmupIterations,omitempty"`
+	// +optional
+	MetricFailedChecks map[string]int `json:"metricFailedChecks,omitempty"`
+	// +optional
 	PreviousSessionAffinityCookie string `json:"previousSessionAffinityCookie,omitempty"`
 	// +optional
 	SessionAffinityCookie string `json:"sessionAffinityCookie,omitempty"`
@@ -84,7 +109,23 @@ type CanaryStatus struct {
 	// +optional
 	LastPromotedSpec string `json:"lastPromotedSpec,omitempty"`
 	// +optional
+	TargetRevisions map[string]string `json:"targetRevisions,omitempty"`
+	// +optional
 	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
 	// +optional
+	StepIndex int `json:"stepIndex,omitempty"`
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// +optional
+	QueuePosition int `json:"queuePosition,omitempty"`
+	// +optional
+	SelectorSwapped bool `json:"selectorSwapped,omitempty"`
+	// +optional
+	CanaryReplicas *int32 `json:"canaryReplicas,omitempty"`
+	// +optional
+	FastTrack *CanaryFastTrackStatus `json:"fastTrack,omitempty"`
+	// +optional
 	Conditions []CanaryCondition `json:"conditions,omitempty"`
 }
@@ -28,6 +28,7 @@ import (
 	istiov1beta1 "github.com/fluxcd/flagger/pkg/apis/istio/v1beta1"
 	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	intstr "k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -234,6 +235,11 @@ func (in *CanaryAnalysis) DeepCopyInto(out *CanaryAnalysis) {
 		*out = make([]int, len(*in))
 		copy(*out, *in)
 	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStep, len(*in))
+		copy(*out, *in)
+	}
 	if in.PrimaryReadyThreshold != nil {
 		in, out := &in.PrimaryReadyThreshold, &out.PrimaryReadyThreshold
 		*out = new(int)
@@ -256,6 +262,36 @@ func (in *CanaryAnalysis) DeepCopyInto(out *CanaryAnalysis) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Scoring != nil {
+		in, out := &in.Scoring, &out.Scoring
+		*out = new(CanaryScoring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReplicaScaling != nil {
+		in, out := &in.ReplicaScaling, &out.ReplicaScaling
+		*out = new(CanaryReplicaScaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrimaryRollout != nil {
+		in, out := &in.PrimaryRollout, &out.PrimaryRollout
+		*out = new(CanaryPrimaryRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostPromotion != nil {
+		in, out := &in.PostPromotion, &out.PostPromotion
+		*out = new(CanaryPostPromotion)
+		**out = **in
+	}
+	if in.Warmup != nil {
+		in, out := &in.Warmup, &out.Warmup
+		*out = new(CanaryWarmup)
+		**out = **in
+	}
+	if in.FastTrack != nil {
+		in, out := &in.FastTrack, &out.FastTrack
+		*out = new(CanaryFastTrack)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Webhooks != nil {
 		in, out := &in.Webhooks, &out.Webhooks
 		*out = make([]CanaryWebhook, len(*in))
@@ -306,6 +342,79 @@ func (in *CanaryCondition) DeepCopy() *CanaryCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryFastTrack) DeepCopyInto(out *CanaryFastTrack) {
+	*out = *in
+	if in.Short != nil {
+		in, out := &in.Short, &out.Short
+		*out = new(CanaryShortSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryFastTrack.
+func (in *CanaryFastTrack) DeepCopy() *CanaryFastTrack {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryFastTrack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryFastTrackStatus) DeepCopyInto(out *CanaryFastTrackStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryFastTrackStatus.
+func (in *CanaryFastTrackStatus) DeepCopy() *CanaryFastTrackStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryFastTrackStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryIgnoreChanges) DeepCopyInto(out *CanaryIgnoreChanges) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryIgnoreChanges.
+func (in *CanaryIgnoreChanges) DeepCopy() *CanaryIgnoreChanges {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryIgnoreChanges)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryList) DeepCopyInto(out *CanaryList) {
 	*out = *in
@@ -359,6 +468,11 @@ func (in *CanaryMetric) DeepCopyInto(out *CanaryMetric) {
 			(*out)[key] = val
 		}
 	}
+	if in.Comparison != nil {
+		in, out := &in.Comparison, &out.Comparison
+		*out = new(CanaryMetricComparison)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -372,6 +486,136 @@ func (in *CanaryMetric) DeepCopy() *CanaryMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryMetricComparison) DeepCopyInto(out *CanaryMetricComparison) {
+	*out = *in
+	if in.MaxRatio != nil {
+		in, out := &in.MaxRatio, &out.MaxRatio
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxDelta != nil {
+		in, out := &in.MaxDelta, &out.MaxDelta
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryMetricComparison.
+func (in *CanaryMetricComparison) DeepCopy() *CanaryMetricComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryMetricComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryPostPromotion) DeepCopyInto(out *CanaryPostPromotion) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPostPromotion.
+func (in *CanaryPostPromotion) DeepCopy() *CanaryPostPromotion {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryPostPromotion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryPrimaryRollout) DeepCopyInto(out *CanaryPrimaryRollout) {
+	*out = *in
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPrimaryRollout.
+func (in *CanaryPrimaryRollout) DeepCopy() *CanaryPrimaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryPrimaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryReplicaScaling) DeepCopyInto(out *CanaryReplicaScaling) {
+	*out = *in
+	if in.Headroom != nil {
+		in, out := &in.Headroom, &out.Headroom
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryReplicaScaling.
+func (in *CanaryReplicaScaling) DeepCopy() *CanaryReplicaScaling {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryReplicaScaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryScoring) DeepCopyInto(out *CanaryScoring) {
+	*out = *in
+	if in.Pass != nil {
+		in, out := &in.Pass, &out.Pass
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Marginal != nil {
+		in, out := &in.Marginal, &out.Marginal
+		*out = new(float64)
+		**out = **in
+	}
+	if in.ConfidenceLevel != nil {
+		in, out := &in.ConfidenceLevel, &out.ConfidenceLevel
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryScoring.
+func (in *CanaryScoring) DeepCopy() *CanaryScoring {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryScoring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryService) DeepCopyInto(out *CanaryService) {
 	*out = *in
@@ -465,10 +709,41 @@ func (in *CanaryService) DeepCopy() *CanaryService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryShortSchedule) DeepCopyInto(out *CanaryShortSchedule) {
+	*out = *in
+	if in.StepWeights != nil {
+		in, out := &in.StepWeights, &out.StepWeights
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryShortSchedule.
+func (in *CanaryShortSchedule) DeepCopy() *CanaryShortSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryShortSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 	*out = *in
 	out.TargetRef = in.TargetRef
+	if in.AdditionalTargetRefs != nil {
+		in, out := &in.AdditionalTargetRefs, &out.AdditionalTargetRefs
+		*out = make([]LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.TargetPaths != nil {
+		in, out := &in.TargetPaths, &out.TargetPaths
+		*out = new(TargetPaths)
+		**out = **in
+	}
 	if in.AutoscalerRef != nil {
 		in, out := &in.AutoscalerRef, &out.AutoscalerRef
 		*out = new(AutoscalerRefernce)
@@ -505,6 +780,21 @@ func (in *CanarySpec) DeepCopyInto(out *CanarySpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IgnoreChanges != nil {
+		in, out := &in.IgnoreChanges, &out.IgnoreChanges
+		*out = new(CanaryIgnoreChanges)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]CrossNamespaceObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -521,6 +811,13 @@ func (in *CanarySpec) DeepCopy() *CanarySpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
 	*out = *in
+	if in.MetricFailedChecks != nil {
+		in, out := &in.MetricFailedChecks, &out.MetricFailedChecks
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.TrackedConfigs != nil {
 		in, out := &in.TrackedConfigs, &out.TrackedConfigs
 		*out = new(map[string]string)
@@ -532,7 +829,28 @@ func (in *CanaryStatus) DeepCopyInto(out *CanaryStatus) {
 			}
 		}
 	}
+	if in.TargetRevisions != nil {
+		in, out := &in.TargetRevisions, &out.TargetRevisions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CanaryReplicas != nil {
+		in, out := &in.CanaryReplicas, &out.CanaryReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FastTrack != nil {
+		in, out := &in.FastTrack, &out.FastTrack
+		*out = new(CanaryFastTrackStatus)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]CanaryCondition, len(*in))
@@ -553,6 +871,22 @@ func (in *CanaryStatus) DeepCopy() *CanaryStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStep) DeepCopyInto(out *CanaryStep) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStep.
+func (in *CanaryStep) DeepCopy() *CanaryStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryThresholdRange) DeepCopyInto(out *CanaryThresholdRange) {
 	*out = *in
@@ -579,6 +913,22 @@ func (in *CanaryThresholdRange) DeepCopy() *CanaryThresholdRange {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryWarmup) DeepCopyInto(out *CanaryWarmup) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryWarmup.
+func (in *CanaryWarmup) DeepCopy() *CanaryWarmup {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryWarmup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CanaryWebhook) DeepCopyInto(out *CanaryWebhook) {
 	*out = *in
@@ -926,3 +1276,19 @@ func (in *SessionAffinity) DeepCopy() *SessionAffinity {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetPaths) DeepCopyInto(out *TargetPaths) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetPaths.
+func (in *TargetPaths) DeepCopy() *TargetPaths {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetPaths)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// classifyChange returns the class of the target pod template and tracked configs changes since the
+// last promoted revision along with the reason, the templates are compared in the form returned by the spec func
+func classifyChange(kubeClient kubernetes.Interface, tracker Tracker, cd *flaggerv1.Canary, target corev1.PodTemplateSpec,
+	spec func(template corev1.PodTemplateSpec) corev1.PodTemplateSpec) (flaggerv1.ChangeClass, string, error) {
+	revisions, err := getPromotedRevisions(kubeClient, cd)
+	if err != nil {
+		return flaggerv1.ChangeOther, "", err
+	}
+	if len(revisions) == 0 || revisions[len(revisions)-1].TargetTemplate == nil {
+		return flaggerv1.ChangeOther, "no promoted revision to compare with", nil
+	}
+	last := revisions[len(revisions)-1]
+
+	decoded, err := decodeTemplate(target)
+	if err != nil {
+		return flaggerv1.ChangeOther, "", err
+	}
+	current, err := ignoreChanges(cd, spec(decoded))
+	if err != nil {
+		return flaggerv1.ChangeOther, "", err
+	}
+	promoted, err := ignoreChanges(cd, spec(*last.TargetTemplate))
+	if err != nil {
+		return flaggerv1.ChangeOther, "", err
+	}
+
+	configs, err := tracker.GetConfigRefs(cd)
+	if err != nil {
+		return flaggerv1.ChangeOther, "", fmt.Errorf("GetConfigRefs failed: %w", err)
+	}
+	var currentConfigs map[string]string
+	if configs != nil {
+		currentConfigs = *configs
+	}
+	changedConfigs := diffConfigs(last.TrackedConfigs, currentConfigs)
+
+	if ComputeHash(current) == ComputeHash(promoted) {
+		if len(changedConfigs) > 0 {
+			return flaggerv1.ChangeConfigOnly,
+				fmt.Sprintf("only the configs %s changed", strings.Join(changedConfigs, ", ")), nil
+		}
+		return flaggerv1.ChangeOther, "no change since the last promoted revision", nil
+	}
+	if len(changedConfigs) > 0 {
+		return flaggerv1.ChangeOther,
+			fmt.Sprintf("the pod template and the configs %s changed", strings.Join(changedConfigs, ", ")), nil
+	}
+
+	clearImage := func(container *corev1.Container) { container.Image = "" }
+	if ComputeHash(clearContainers(current, clearImage)) == ComputeHash(clearContainers(promoted, clearImage)) {
+		return classifyImageChanges(current, promoted)
+	}
+
+	clearResources := func(container *corev1.Container) { container.Resources = corev1.ResourceRequirements{} }
+	if ComputeHash(clearContainers(current, clearResources)) == ComputeHash(clearContainers(promoted, clearResources)) {
+		return flaggerv1.ChangeResourcesOnly, fmt.Sprintf("only the resources of the containers %s changed",
+			strings.Join(changedContainers(current, promoted, func(c, p corev1.Container) bool {
+				return ComputeHash(c.Resources) != ComputeHash(p.Resources)
+			}), ", ")), nil
+	}
+
+	return flaggerv1.ChangeOther, "the pod template changed", nil
+}
+
+// classifyImageChanges returns ImageTagPatch if every changed image is a patch
+// version of the promoted image, the templates must differ only in the images
+func classifyImageChanges(current, promoted corev1.PodTemplateSpec) (flaggerv1.ChangeClass, string, error) {
+	currentContainers := allContainers(current)
+	promotedContainers := allContainers(promoted)
+
+	var updates []string
+	for i := range currentContainers {
+		from, to := promotedContainers[i].Image, currentContainers[i].Image
+		if from == to {
+			continue
+		}
+		if !isPatchUpdate(from, to) {
+			return flaggerv1.ChangeOther, fmt.Sprintf("the image of the container %s changed from %s to %s",
+				currentContainers[i].Name, from, to), nil
+		}
+		updates = append(updates, fmt.Sprintf("%s %s", currentContainers[i].Name, to))
+	}
+
+	return flaggerv1.ChangeImageTagPatch, fmt.Sprintf("only the image patch versions changed (%s)",
+		strings.Join(updates, ", ")), nil
+}
+
+// isPatchUpdate returns true if both images are from the same repository and
+// the tags are semantic versions with the same major and minor numbers
+func isPatchUpdate(from, to string) bool {
+	fromRepo, fromTag := splitImage(from)
+	toRepo, toTag := splitImage(to)
+	if fromRepo != toRepo || fromTag == "" || toTag == "" {
+		return false
+	}
+
+	fromVersion, err := semver.NewVersion(fromTag)
+	if err != nil || fromVersion.Prerelease() != "" {
+		return false
+	}
+	toVersion, err := semver.NewVersion(toTag)
+	if err != nil || toVersion.Prerelease() != "" {
+		return false
+	}
+
+	return fromVersion.Major() == toVersion.Major() && fromVersion.Minor() == toVersion.Minor()
+}
+
+// splitImage returns the repository and tag of an image, the tag is empty for digests
+func splitImage(image string) (string, string) {
+	if strings.Contains(image, "@") {
+		return image, ""
+	}
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
+
+// clearContainers returns a copy of the template with the containers field cleared by the func
+func clearContainers(template corev1.PodTemplateSpec, clear func(container *corev1.Container)) corev1.PodTemplateSpec {
+	cleared := template.DeepCopy()
+	for i := range cleared.Spec.InitContainers {
+		clear(&cleared.Spec.InitContainers[i])
+	}
+	for i := range cleared.Spec.Containers {
+		clear(&cleared.Spec.Containers[i])
+	}
+	return *cleared
+}
+
+// changedContainers returns the names of the containers that differ according to the changed func,
+// the templates must have the same containers
+func changedContainers(current, promoted corev1.PodTemplateSpec, changed func(c, p corev1.Container) bool) []string {
+	currentContainers := allContainers(current)
+	promotedContainers := allContainers(promoted)
+
+	var names []string
+	for i := range currentContainers {
+		if changed(currentContainers[i], promotedContainers[i]) {
+			names = append(names, currentContainers[i].Name)
+		}
+	}
+	return names
+}
+
+// allContainers returns the init containers followed by the containers of the template
+func allContainers(template corev1.PodTemplateSpec) []corev1.Container {
+	containers := make([]corev1.Container, 0, len(template.Spec.InitContainers)+len(template.Spec.Containers))
+	containers = append(containers, template.Spec.InitContainers...)
+	return append(containers, template.Spec.Containers...)
+}
+
+// diffConfigs returns the sorted names of the configs added, removed or changed
+func diffConfigs(promoted, current map[string]string) []string {
+	var names []string
+	for name, checksum := range current {
+		if promoted[name] != checksum {
+			names = append(names, name)
+		}
+	}
+	for name := range promoted {
+		if _, ok := current[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestDeploymentController_ClassifyChange(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	classify := func(update func(dep *corev1.PodTemplateSpec)) (flaggerv1.ChangeClass, string) {
+		dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		depCopy := dep.DeepCopy()
+		update(&depCopy.Spec.Template)
+		_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), depCopy, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		class, reason, err := mocks.controller.ClassifyChange(mocks.canary)
+		require.NoError(t, err)
+
+		_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		return class, reason
+	}
+
+	class, reason := classify(func(template *corev1.PodTemplateSpec) {})
+	assert.Equal(t, flaggerv1.ChangeOther, class)
+	assert.Equal(t, "no change since the last promoted revision", reason)
+
+	class, reason = classify(func(template *corev1.PodTemplateSpec) {
+		template.Spec.Containers[0].Image = "quay.io/stefanprodan/podinfo:1.2.1"
+	})
+	assert.Equal(t, flaggerv1.ChangeImageTagPatch, class)
+	assert.Equal(t, "only the image patch versions changed (podinfo quay.io/stefanprodan/podinfo:1.2.1)", reason)
+
+	class, _ = classify(func(template *corev1.PodTemplateSpec) {
+		template.Spec.Containers[0].Image = "quay.io/stefanprodan/podinfo:1.3.0"
+	})
+	assert.Equal(t, flaggerv1.ChangeOther, class)
+
+	class, reason = classify(func(template *corev1.PodTemplateSpec) {
+		template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}
+	})
+	assert.Equal(t, flaggerv1.ChangeResourcesOnly, class)
+	assert.Equal(t, "only the resources of the containers podinfo changed", reason)
+
+	class, _ = classify(func(template *corev1.PodTemplateSpec) {
+		template.Spec.Containers[0].Image = "quay.io/stefanprodan/podinfo:1.2.1"
+		template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}
+	})
+	assert.Equal(t, flaggerv1.ChangeOther, class)
+
+	// update the config map data
+	_, err := mocks.kubeClient.CoreV1().ConfigMaps("default").Update(context.TODO(),
+		newDeploymentControllerTestConfigMapV2(), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	class, reason = classify(func(template *corev1.PodTemplateSpec) {})
+	assert.Equal(t, flaggerv1.ChangeConfigOnly, class)
+	assert.Equal(t, "only the configs configmap/podinfo-config-env changed", reason)
+
+	class, _ = classify(func(template *corev1.PodTemplateSpec) {
+		template.Spec.Containers[0].Image = "quay.io/stefanprodan/podinfo:1.2.1"
+	})
+	assert.Equal(t, flaggerv1.ChangeOther, class)
+}
+
+func TestIsPatchUpdate(t *testing.T) {
+	tests := []struct {
+		from, to string
+		patch    bool
+	}{
+		{"podinfo:1.2.0", "podinfo:1.2.1", true},
+		{"ghcr.io/podinfo:v6.0.0", "ghcr.io/podinfo:v6.0.3", true},
+		{"localhost:5000/podinfo:1.2.0", "localhost:5000/podinfo:1.2.1", true},
+		{"podinfo:1.2.0", "podinfo:1.3.0", false},
+		{"podinfo:1.2.0", "podinfo:2.2.0", false},
+		{"podinfo:1.2.0", "other:1.2.1", false},
+		{"podinfo:1.2.0", "podinfo:1.2.1-rc.1", false},
+		{"podinfo:latest", "podinfo:1.2.1", false},
+		{"podinfo", "podinfo:1.2.1", false},
+		{"podinfo@sha256:1234", "podinfo@sha256:5678", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.patch, isPatchUpdate(tt.from, tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
@@ -26,8 +26,10 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -39,6 +41,10 @@ type ConfigTracker struct {
 	KubeClient    kubernetes.Interface
 	FlaggerClient clientset.Interface
 	Logger        *zap.SugaredLogger
+
+	// DynamicClient and RESTMapper are used to read the pod template of custom resource targets
+	DynamicClient dynamic.Interface
+	RESTMapper    meta.RESTMapper
 }
 
 type ConfigRefType string
@@ -123,30 +129,71 @@ func (ct *ConfigTracker) getRefFromSecret(name string, namespace string) (*Confi
 // GetTargetConfigs scans the target deployment for Kubernetes ConfigMaps and Secrets
 // and returns a list of config references
 func (ct *ConfigTracker) GetTargetConfigs(cd *flaggerv1.Canary) (map[string]ConfigRef, error) {
+	template, err := ct.getTargetTemplate(cd)
+	if err != nil {
+		return nil, err
+	}
+	return ct.getTemplateConfigs(cd, template.Spec)
+}
+
+// getTrackedConfigs returns the config references of the target without the configs
+// used only by the containers excluded by the canary ignore rules
+func (ct *ConfigTracker) getTrackedConfigs(cd *flaggerv1.Canary) (map[string]ConfigRef, error) {
+	template, err := ct.getTargetTemplate(cd)
+	if err != nil {
+		return nil, err
+	}
+	template, err = ignoreChanges(cd, template)
+	if err != nil {
+		return nil, err
+	}
+	return ct.getTemplateConfigs(cd, template.Spec)
+}
+
+func (ct *ConfigTracker) getTargetTemplate(cd *flaggerv1.Canary) (corev1.PodTemplateSpec, error) {
 	targetName := cd.Spec.TargetRef.Name
-	var vs []corev1.Volume
-	var cs []corev1.Container
 
 	switch cd.Spec.TargetRef.Kind {
 	case "Deployment":
 		targetDep, err := ct.KubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
 		if err != nil {
-			return nil, fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
+			return corev1.PodTemplateSpec{}, fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
 		}
-		vs = targetDep.Spec.Template.Spec.Volumes
-		cs = targetDep.Spec.Template.Spec.Containers
-		cs = append(cs, targetDep.Spec.Template.Spec.InitContainers...)
+		return targetDep.Spec.Template, nil
 	case "DaemonSet":
 		targetDae, err := ct.KubeClient.AppsV1().DaemonSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
 		if err != nil {
-			return nil, fmt.Errorf("daemonset %s.%s get query error: %w", targetName, cd.Namespace, err)
+			return corev1.PodTemplateSpec{}, fmt.Errorf("daemonset %s.%s get query error: %w", targetName, cd.Namespace, err)
 		}
-		vs = targetDae.Spec.Template.Spec.Volumes
-		cs = targetDae.Spec.Template.Spec.Containers
-		cs = append(cs, targetDae.Spec.Template.Spec.InitContainers...)
+		return targetDae.Spec.Template, nil
+	case "StatefulSet":
+		targetSts, err := ct.KubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+		}
+		return targetSts.Spec.Template, nil
 	default:
-		return nil, fmt.Errorf("TargetRef.Kind invalid: %s", cd.Spec.TargetRef.Kind)
+		if !cd.Spec.TargetRef.IsGenericWorkload() {
+			return corev1.PodTemplateSpec{}, fmt.Errorf("TargetRef.Kind invalid: %s", cd.Spec.TargetRef.Kind)
+		}
+		paths, err := parseTargetPaths(cd.Spec.TargetPaths)
+		if err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+		target, err := getGenericTarget(ct.DynamicClient, ct.RESTMapper, cd.Spec.TargetRef, targetName, cd.Namespace)
+		if err != nil {
+			return corev1.PodTemplateSpec{}, err
+		}
+		return getPodTemplate(target, paths)
 	}
+}
+
+// getTemplateConfigs scans the pod spec for Kubernetes ConfigMaps and Secrets
+// and returns a list of config references
+func (ct *ConfigTracker) getTemplateConfigs(cd *flaggerv1.Canary, spec corev1.PodSpec) (map[string]ConfigRef, error) {
+	vs := spec.Volumes
+	cs := spec.Containers
+	cs = append(cs, spec.InitContainers...)
 
 	secretNames := make(map[string]bool)
 	configMapNames := make(map[string]bool)
@@ -246,7 +293,7 @@ func fieldIsMandatory(p *bool) bool {
 // GetConfigRefs returns a map of configs and their checksum
 func (ct *ConfigTracker) GetConfigRefs(cd *flaggerv1.Canary) (*map[string]string, error) {
 	res := make(map[string]string)
-	configs, err := ct.GetTargetConfigs(cd)
+	configs, err := ct.getTrackedConfigs(cd)
 	if err != nil {
 		return nil, fmt.Errorf("GetTargetConfigs failed: %w", err)
 	}
@@ -261,7 +308,7 @@ func (ct *ConfigTracker) GetConfigRefs(cd *flaggerv1.Canary) (*map[string]string
 // HasConfigChanged checks for changes in ConfigMaps and Secrets by comparing
 // the checksum for each ConfigRef stored in Canary.Status.TrackedConfigs
 func (ct *ConfigTracker) HasConfigChanged(cd *flaggerv1.Canary) (bool, error) {
-	configs, err := ct.GetTargetConfigs(cd)
+	configs, err := ct.getTrackedConfigs(cd)
 	if err != nil {
 		return false, fmt.Errorf("GetTargetConfigs failed: %w", err)
 	}
@@ -37,3 +37,39 @@ type Controller interface {
 	ScaleFromZero(canary *flaggerv1.Canary) error
 	Finalize(canary *flaggerv1.Canary) error
 }
+
+// BaselineController is implemented by the controllers that can run a baseline
+// workload created from the primary spec to compare the canary against
+type BaselineController interface {
+	// SyncBaseline creates the baseline from the primary spec and scales it to the canary replicas
+	SyncBaseline(canary *flaggerv1.Canary) error
+	// IsBaselineReady checks the baseline rollout status
+	IsBaselineReady(canary *flaggerv1.Canary) (bool, error)
+	// DeleteBaseline removes the baseline workload if it exists
+	DeleteBaseline(canary *flaggerv1.Canary) error
+}
+
+// ReplicaController is implemented by the controllers that can size
+// the canary replicas independently of the target replicas
+type ReplicaController interface {
+	// GetReplicas returns the desired replicas of the canary and of the primary
+	GetReplicas(canary *flaggerv1.Canary) (int32, int32, error)
+	// ScaleCanary sets the canary replicas
+	ScaleCanary(canary *flaggerv1.Canary, replicas int32) error
+}
+
+// RevisionController is implemented by the controllers that can restore
+// the primary workload to the revision promoted before the current one
+type RevisionController interface {
+	// RevertPrimary restores the primary pod template replaced by the last promotion
+	RevertPrimary(canary *flaggerv1.Canary) error
+	// RollbackPrimary restores the primary pod template of a promoted revision
+	RollbackPrimary(canary *flaggerv1.Canary, revision int) error
+	// RevertTarget restores the target pod template of the last promoted revision
+	RevertTarget(canary *flaggerv1.Canary) error
+	// PromoteIgnoredChanges applies the target pod template to the primary if it differs from
+	// the last promoted revision only in the fields excluded by the canary ignore rules
+	PromoteIgnoredChanges(canary *flaggerv1.Canary) (bool, error)
+	// ClassifyChange returns the class of the target changes since the last promoted revision and the reason
+	ClassifyChange(canary *flaggerv1.Canary) (flaggerv1.ChangeClass, string, error)
+}
@@ -114,6 +114,7 @@ func (c *DaemonSetController) Promote(cd *flaggerv1.Canary) error {
 	targetName := cd.Spec.TargetRef.Name
 	primaryName := fmt.Sprintf("%s-primary", targetName)
 
+	var current, promoted, target corev1.PodTemplateSpec
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		canary, err := c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
 		if err != nil {
@@ -131,6 +132,9 @@ func (c *DaemonSetController) Promote(cd *flaggerv1.Canary) error {
 			return fmt.Errorf("daemonset %s.%s get query error: %w", primaryName, cd.Namespace, err)
 		}
 
+		// keep a copy of the target template, the primary configs are applied in place
+		target = *canary.Spec.Template.DeepCopy()
+
 		// promote secrets and config maps
 		configRefs, err := c.configTracker.GetTargetConfigs(cd)
 		if err != nil {
@@ -174,6 +178,7 @@ func (c *DaemonSetController) Promote(cd *flaggerv1.Canary) error {
 
 		// apply update
 		_, err = c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Update(context.TODO(), primaryCopy, metav1.UpdateOptions{})
+		current, promoted = primary.Spec.Template, primaryCopy.Spec.Template
 		return err
 	})
 	if err != nil {
@@ -181,6 +186,16 @@ func (c *DaemonSetController) Promote(cd *flaggerv1.Canary) error {
 			primaryName, cd.Namespace, err)
 	}
 
+	// keep the promoted template for rollback
+	if err := recordPromotedRevision(c.kubeClient, cd, current, promotedRevision{
+		Spec:           cd.Status.LastAppliedSpec,
+		TrackedConfigs: trackedConfigsOf(cd),
+		Template:       promoted,
+		TargetTemplate: &target,
+	}); err != nil {
+		return fmt.Errorf("recordPromotedRevision failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -192,17 +207,51 @@ func (c *DaemonSetController) HasTargetChanged(cd *flaggerv1.Canary) (bool, erro
 		return false, fmt.Errorf("daemonset %s.%s get query error: %w", targetName, cd.Namespace, err)
 	}
 
+	return hasTemplateChanged(cd, daemonSetSpecTemplate(canary.Spec.Template))
+}
+
+// PromoteIgnoredChanges copies the pod spec, secrets and config maps from canary to primary
+// if the canary pod spec differs from the last promoted one only in the ignored fields
+func (c *DaemonSetController) PromoteIgnoredChanges(cd *flaggerv1.Canary) (bool, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("daemonset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	ok, err := hasIgnoredChanges(c.kubeClient, cd, canary.Spec.Template, daemonSetSpecTemplate)
+	if err != nil || !ok {
+		return false, err
+	}
+	return true, c.Promote(cd)
+}
+
+// ClassifyChange returns the class of the daemonset changes since the last promoted revision
+func (c *DaemonSetController) ClassifyChange(cd *flaggerv1.Canary) (flaggerv1.ChangeClass, string, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return flaggerv1.ChangeOther, "", fmt.Errorf("daemonset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	return classifyChange(c.kubeClient, c.configTracker, cd, canary.Spec.Template, daemonSetSpecTemplate)
+}
+
+// daemonSetSpecTemplate returns a copy of the pod template without the node selector
+// used to scale the daemonset to zero, the copy is hashed to identify the daemonset revision
+func daemonSetSpecTemplate(template corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	spec := *template.DeepCopy()
+
 	// ignore `daemonSetScaleDownNodeSelector` node selector
 	for key := range daemonSetScaleDownNodeSelector {
-		delete(canary.Spec.Template.Spec.NodeSelector, key)
+		delete(spec.Spec.NodeSelector, key)
 	}
 
 	// since nil and capacity zero map would have different hash, we have to initialize here
-	if canary.Spec.Template.Spec.NodeSelector == nil {
-		canary.Spec.Template.Spec.NodeSelector = map[string]string{}
+	if spec.Spec.NodeSelector == nil {
+		spec.Spec.NodeSelector = map[string]string{}
 	}
-
-	return hasSpecChanged(cd, canary.Spec.Template)
+	return spec
 }
 
 // GetMetadata returns the pod label selector and svc ports
@@ -260,6 +309,9 @@ func (c *DaemonSetController) createPrimaryDaemonSet(cd *flaggerv1.Canary, inclu
 		if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
 			return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
 		}
+		// keep a copy of the target template, the primary configs are applied in place
+		target := canaryDae.Spec.Template.DeepCopy()
+
 		annotations, err := makeAnnotations(canaryDae.Spec.Template.Annotations)
 		if err != nil {
 			return fmt.Errorf("makeAnnotations failed: %w", err)
@@ -300,11 +352,16 @@ func (c *DaemonSetController) createPrimaryDaemonSet(cd *flaggerv1.Canary, inclu
 			},
 		}
 
-		_, err = c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Create(context.TODO(), primaryDae, metav1.CreateOptions{})
+		created, err := c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Create(context.TODO(), primaryDae, metav1.CreateOptions{})
 		if err != nil {
 			return fmt.Errorf("creating daemonset %s.%s failed: %w", primaryDae.Name, cd.Namespace, err)
 		}
 
+		// keep the initial templates for rollback
+		if err := recordInitialRevision(c.kubeClient, c.configTracker, cd, created.Spec.Template, daemonSetSpecTemplate(*target)); err != nil {
+			return fmt.Errorf("recordInitialRevision failed: %w", err)
+		}
+
 		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Infof("DaemonSet %s.%s created", primaryDae.GetName(), cd.Namespace)
 	}
 	return nil
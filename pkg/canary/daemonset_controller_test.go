@@ -254,6 +254,39 @@ func TestDaemonSetController_Scale(t *testing.T) {
 	})
 }
 
+func TestDaemonSetController_RevertTarget(t *testing.T) {
+	dc := daemonsetConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDaemonSetFixture(dc)
+	_, err := mocks.controller.Initialize(mocks.canary)
+	require.NoError(t, err)
+
+	dae2 := newDaemonSetControllerTestPodInfoV2()
+	_, err = mocks.kubeClient.AppsV1().DaemonSets("default").Update(context.TODO(), dae2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	err = mocks.controller.ScaleToZero(mocks.canary)
+	require.NoError(t, err)
+
+	mocks.canary.Status.LastAppliedSpec = ComputeHash(daemonSetSpecTemplate(dae2.Spec.Template))
+	err = mocks.controller.RevertTarget(mocks.canary)
+	require.NoError(t, err)
+
+	// the reverted daemonset stays scaled to zero
+	dae, err := mocks.kubeClient.AppsV1().DaemonSets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.0", dae.Spec.Template.Spec.Containers[0].Image)
+	for k, v := range daemonSetScaleDownNodeSelector {
+		assert.Equal(t, v, dae.Spec.Template.Spec.NodeSelector[k])
+	}
+
+	// the reverted spec isn't detected as a change
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	cd.Status.LastAppliedSpec = mocks.canary.Status.LastAppliedSpec
+	isNew, err := mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+}
+
 func TestDaemonSetController_Finalize(t *testing.T) {
 	dc := daemonsetConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
 	mocks := newDaemonSetFixture(dc)
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// RevertPrimary restores the primary daemonset pod template replaced by the last promotion
+func (c *DaemonSetController) RevertPrimary(cd *flaggerv1.Canary) error {
+	return rollbackPrimary(c.kubeClient, c.flaggerClient, cd, 0, c.applyPrimaryTemplate(cd))
+}
+
+// RollbackPrimary restores the primary daemonset pod template of a promoted revision
+func (c *DaemonSetController) RollbackPrimary(cd *flaggerv1.Canary, revision int) error {
+	return rollbackPrimary(c.kubeClient, c.flaggerClient, cd, revision, c.applyPrimaryTemplate(cd))
+}
+
+func (c *DaemonSetController) applyPrimaryTemplate(cd *flaggerv1.Canary) func(template corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+	return func(template corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+		primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+		var current corev1.PodTemplateSpec
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			primary, err := c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("daemonset %s.%s get query error: %w", primaryName, cd.Namespace, err)
+			}
+
+			primaryCopy := primary.DeepCopy()
+			primaryCopy.Spec.Template = template
+
+			_, err = c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Update(context.TODO(), primaryCopy, metav1.UpdateOptions{})
+			current = primary.Spec.Template
+			return err
+		})
+		if err != nil {
+			return current, fmt.Errorf("rolling back daemonset %s.%s template spec failed: %w", primaryName, cd.Namespace, err)
+		}
+		return current, nil
+	}
+}
+
+// RevertTarget restores the target daemonset pod template of the last promoted revision
+// and records the hash of the rejected spec in the target annotations
+func (c *DaemonSetController) RevertTarget(cd *flaggerv1.Canary) error {
+	return revertTarget(c.kubeClient, c.flaggerClient, c.configTracker, cd, func(template corev1.PodTemplateSpec, rejected string) (corev1.PodTemplateSpec, error) {
+		targetName := cd.Spec.TargetRef.Name
+		var reverted corev1.PodTemplateSpec
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			target, err := c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("daemonset %s.%s get query error: %w", targetName, cd.Namespace, err)
+			}
+
+			targetCopy := target.DeepCopy()
+			targetCopy.Spec.Template = *template.DeepCopy()
+			// keep the daemonset scaled to zero
+			for k, v := range target.Spec.Template.Spec.NodeSelector {
+				if _, ok := daemonSetScaleDownNodeSelector[k]; ok {
+					if targetCopy.Spec.Template.Spec.NodeSelector == nil {
+						targetCopy.Spec.Template.Spec.NodeSelector = make(map[string]string)
+					}
+					targetCopy.Spec.Template.Spec.NodeSelector[k] = v
+				}
+			}
+			if targetCopy.Annotations == nil {
+				targetCopy.Annotations = make(map[string]string)
+			}
+			targetCopy.Annotations[flaggerv1.RejectedRevisionAnnotation] = rejected
+
+			updated, err := c.kubeClient.AppsV1().DaemonSets(cd.Namespace).Update(context.TODO(), targetCopy, metav1.UpdateOptions{})
+			if err != nil {
+				return err
+			}
+			reverted = daemonSetSpecTemplate(updated.Spec.Template)
+			return nil
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("reverting daemonset %s.%s template spec failed: %w", targetName, cd.Namespace, err)
+		}
+		return reverted, nil
+	})
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// SyncBaseline creates the baseline deployment from the primary template if it does not exist
+// and keeps its replicas in sync with the canary deployment
+func (c *DeploymentController) SyncBaseline(cd *flaggerv1.Canary) error {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	baselineName := fmt.Sprintf("%s-baseline", targetName)
+
+	canaryDep, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+	replicas := int32Default(canaryDep.Spec.Replicas)
+
+	baselineDep, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), baselineName, metav1.GetOptions{})
+	if err == nil {
+		if int32Default(baselineDep.Spec.Replicas) != replicas {
+			patch := []byte(fmt.Sprintf(`{"spec":{"replicas": %d}}`, replicas))
+			_, err = c.kubeClient.AppsV1().Deployments(cd.Namespace).Patch(context.TODO(), baselineName, types.MergePatchType, patch, metav1.PatchOptions{})
+			if err != nil {
+				return fmt.Errorf("scaling %s.%s to %d failed: %w", baselineName, cd.Namespace, replicas, err)
+			}
+		}
+		return nil
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("deployment %s.%s get query error: %w", baselineName, cd.Namespace, err)
+	}
+
+	primaryDep, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("deployment %s.%s get query error: %w", primaryName, cd.Namespace, err)
+	}
+
+	label, labelValue, err := c.getSelectorLabel(canaryDep)
+	if err != nil {
+		return fmt.Errorf("getSelectorLabel failed: %w", err)
+	}
+	baselineLabelValue := fmt.Sprintf("%s-baseline", labelValue)
+
+	// the baseline runs the primary pod spec including the primary secrets and config maps
+	baselineDep = &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        baselineName,
+			Namespace:   cd.Namespace,
+			Labels:      makePrimaryLabels(primaryDep.Labels, baselineLabelValue, label),
+			Annotations: filterMetadata(primaryDep.Annotations),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cd, schema.GroupVersionKind{
+					Group:   flaggerv1.SchemeGroupVersion.Group,
+					Version: flaggerv1.SchemeGroupVersion.Version,
+					Kind:    flaggerv1.CanaryKind,
+				}),
+			},
+		},
+		Spec: appsv1.DeploymentSpec{
+			ProgressDeadlineSeconds: primaryDep.Spec.ProgressDeadlineSeconds,
+			MinReadySeconds:         primaryDep.Spec.MinReadySeconds,
+			RevisionHistoryLimit:    primaryDep.Spec.RevisionHistoryLimit,
+			Replicas:                int32p(replicas),
+			Strategy:                primaryDep.Spec.Strategy,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					label: baselineLabelValue,
+				},
+			},
+			Template: *primaryDep.Spec.Template.DeepCopy(),
+		},
+	}
+	baselineDep.Spec.Template.Labels = makePrimaryLabels(primaryDep.Spec.Template.Labels, baselineLabelValue, label)
+
+	_, err = c.kubeClient.AppsV1().Deployments(cd.Namespace).Create(context.TODO(), baselineDep, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("creating deployment %s.%s failed: %w", baselineName, cd.Namespace, err)
+	}
+
+	c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+		Infof("Deployment %s.%s created", baselineName, cd.Namespace)
+	return nil
+}
+
+// IsBaselineReady checks the baseline deployment status and returns an error if
+// the deployment is in the middle of a rolling update or if the pods are unhealthy
+func (c *DeploymentController) IsBaselineReady(cd *flaggerv1.Canary) (bool, error) {
+	baselineName := fmt.Sprintf("%s-baseline", cd.Spec.TargetRef.Name)
+	baseline, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), baselineName, metav1.GetOptions{})
+	if err != nil {
+		return true, fmt.Errorf("deployment %s.%s get query error: %w", baselineName, cd.Namespace, err)
+	}
+
+	retriable, err := c.isDeploymentReady(baseline, cd.GetProgressDeadlineSeconds(), cd.GetAnalysisCanaryReadyThreshold())
+	if err != nil {
+		return retriable, fmt.Errorf("baseline deployment %s.%s not ready: %w", baselineName, cd.Namespace, err)
+	}
+	return true, nil
+}
+
+// DeleteBaseline removes the baseline deployment if it exists
+func (c *DeploymentController) DeleteBaseline(cd *flaggerv1.Canary) error {
+	baselineName := fmt.Sprintf("%s-baseline", cd.Spec.TargetRef.Name)
+	err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Delete(context.TODO(), baselineName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("deleting deployment %s.%s failed: %w", baselineName, cd.Namespace, err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestDeploymentController_SyncBaseline(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	// the baseline is created from the primary template even if the canary has changed
+	dep2 := newDeploymentControllerTestV2()
+	dep2.Spec.Replicas = int32p(2)
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.SyncBaseline(mocks.canary)
+	require.NoError(t, err)
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	baseline, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, primary.Spec.Template.Spec.Containers[0].Image, baseline.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "podinfo-baseline", baseline.Spec.Selector.MatchLabels["name"])
+	assert.Equal(t, "podinfo-baseline", baseline.Spec.Template.Labels["name"])
+	assert.Equal(t, int32(2), *baseline.Spec.Replicas)
+
+	// the baseline follows the canary replicas
+	patch := []byte(`{"spec":{"replicas": 3}}`)
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Patch(context.TODO(), "podinfo", types.MergePatchType, patch, metav1.PatchOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.SyncBaseline(mocks.canary)
+	require.NoError(t, err)
+
+	baseline, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), *baseline.Spec.Replicas)
+}
+
+func TestDeploymentController_DeleteBaseline(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	err := mocks.controller.SyncBaseline(mocks.canary)
+	require.NoError(t, err)
+
+	err = mocks.controller.Finalize(mocks.canary)
+	require.NoError(t, err)
+
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// deleting a missing baseline is a no-op
+	err = mocks.controller.DeleteBaseline(mocks.canary)
+	require.NoError(t, err)
+}
@@ -63,9 +63,42 @@ func (c *DeploymentController) Initialize(cd *flaggerv1.Canary) (bool, error) {
 
 // Promote copies the pod spec, secrets and config maps from canary to primary
 func (c *DeploymentController) Promote(cd *flaggerv1.Canary) error {
+	return c.promote(cd, true)
+}
+
+// PromoteIgnoredChanges copies the pod spec, secrets and config maps from canary to primary
+// if the canary pod spec differs from the last promoted one only in the ignored fields
+func (c *DeploymentController) PromoteIgnoredChanges(cd *flaggerv1.Canary) (bool, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	ok, err := hasIgnoredChanges(c.kubeClient, cd, canary.Spec.Template, podTemplate)
+	if err != nil || !ok {
+		return false, err
+	}
+	// the canary is scaled to zero, keep the primary replicas
+	return true, c.promote(cd, false)
+}
+
+// ClassifyChange returns the class of the deployment changes since the last promoted revision
+func (c *DeploymentController) ClassifyChange(cd *flaggerv1.Canary) (flaggerv1.ChangeClass, string, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return flaggerv1.ChangeOther, "", fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	return classifyChange(c.kubeClient, c.configTracker, cd, canary.Spec.Template, podTemplate)
+}
+
+func (c *DeploymentController) promote(cd *flaggerv1.Canary, copyReplicas bool) error {
 	targetName := cd.Spec.TargetRef.Name
 	primaryName := fmt.Sprintf("%s-primary", targetName)
 
+	var current, promoted, target corev1.PodTemplateSpec
 	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		canary, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
 		if err != nil {
@@ -83,6 +116,9 @@ func (c *DeploymentController) Promote(cd *flaggerv1.Canary) error {
 			return fmt.Errorf("deployment %s.%s get query error: %w", primaryName, cd.Namespace, err)
 		}
 
+		// keep a copy of the target template, the primary configs are applied in place
+		target = *canary.Spec.Template.DeepCopy()
+
 		// promote secrets and config maps
 		configRefs, err := c.configTracker.GetTargetConfigs(cd)
 		if err != nil {
@@ -97,8 +133,9 @@ func (c *DeploymentController) Promote(cd *flaggerv1.Canary) error {
 		primaryCopy.Spec.MinReadySeconds = canary.Spec.MinReadySeconds
 		primaryCopy.Spec.RevisionHistoryLimit = canary.Spec.RevisionHistoryLimit
 		primaryCopy.Spec.Strategy = canary.Spec.Strategy
+		setPrimaryRollout(cd, &primaryCopy.Spec)
 		// update replica if hpa isn't set
-		if cd.Spec.AutoscalerRef == nil {
+		if cd.Spec.AutoscalerRef == nil && copyReplicas {
 			primaryCopy.Spec.Replicas = canary.Spec.Replicas
 		}
 
@@ -126,6 +163,7 @@ func (c *DeploymentController) Promote(cd *flaggerv1.Canary) error {
 
 		// apply update
 		_, err = c.kubeClient.AppsV1().Deployments(cd.Namespace).Update(context.TODO(), primaryCopy, metav1.UpdateOptions{})
+		current, promoted = primary.Spec.Template, primaryCopy.Spec.Template
 		return err
 	})
 	if err != nil {
@@ -133,6 +171,16 @@ func (c *DeploymentController) Promote(cd *flaggerv1.Canary) error {
 			primaryName, cd.Namespace, err)
 	}
 
+	// keep the promoted template for rollback
+	if err := recordPromotedRevision(c.kubeClient, cd, current, promotedRevision{
+		Spec:           cd.Status.LastAppliedSpec,
+		TrackedConfigs: trackedConfigsOf(cd),
+		Template:       promoted,
+		TargetTemplate: &target,
+	}); err != nil {
+		return fmt.Errorf("recordPromotedRevision failed: %w", err)
+	}
+
 	return nil
 }
 
@@ -144,7 +192,7 @@ func (c *DeploymentController) HasTargetChanged(cd *flaggerv1.Canary) (bool, err
 		return false, fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
 	}
 
-	return hasSpecChanged(cd, canary.Spec.Template)
+	return hasTemplateChanged(cd, canary.Spec.Template)
 }
 
 // ScaleToZero Scale sets the canary deployment replicas
@@ -184,30 +232,8 @@ func (c *DeploymentController) ScaleFromZero(cd *flaggerv1.Canary) error {
 		if primary.Spec.Replicas != nil && *primary.Spec.Replicas > 0 {
 			replicas = primary.Spec.Replicas
 		}
-	} else if cd.Spec.AutoscalerRef != nil {
-		if cd.Spec.AutoscalerRef.Kind == "HorizontalPodAutoscaler" {
-			hpa, err := c.kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), cd.Spec.AutoscalerRef.Name, metav1.GetOptions{})
-			if err == nil {
-				if hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas > 1 {
-					replicas = hpa.Spec.MinReplicas
-				}
-			} else {
-				// fallback to v2beta2
-				hpa, err := c.kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), cd.Spec.AutoscalerRef.Name, metav1.GetOptions{})
-				if err == nil {
-					if hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas > 1 {
-						replicas = hpa.Spec.MinReplicas
-					}
-				}
-			}
-		} else if cd.Spec.AutoscalerRef.Kind == "ScaledObject" {
-			so, err := c.flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Get(context.TODO(), cd.Spec.AutoscalerRef.Name, metav1.GetOptions{})
-			if err == nil {
-				if so.Spec.MinReplicaCount != nil && *so.Spec.MinReplicaCount > 1 {
-					replicas = so.Spec.MinReplicaCount
-				}
-			}
-		}
+	} else if minReplicas := getAutoscalerMinReplicas(c.kubeClient, c.flaggerClient, cd); minReplicas != nil {
+		replicas = minReplicas
 	}
 
 	patch := []byte(fmt.Sprintf(`{"spec":{"replicas": %d}}`, *replicas))
@@ -267,6 +293,9 @@ func (c *DeploymentController) createPrimaryDeployment(cd *flaggerv1.Canary, inc
 		if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
 			return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
 		}
+		// keep a copy of the target template, the primary configs are applied in place
+		target := canaryDep.Spec.Template.DeepCopy()
+
 		annotations, err := makeAnnotations(canaryDep.Spec.Template.Annotations)
 		if err != nil {
 			return fmt.Errorf("makeAnnotations failed: %w", err)
@@ -314,11 +343,16 @@ func (c *DeploymentController) createPrimaryDeployment(cd *flaggerv1.Canary, inc
 			},
 		}
 
-		_, err = c.kubeClient.AppsV1().Deployments(cd.Namespace).Create(context.TODO(), primaryDep, metav1.CreateOptions{})
+		created, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Create(context.TODO(), primaryDep, metav1.CreateOptions{})
 		if err != nil {
 			return fmt.Errorf("creating deployment %s.%s failed: %w", primaryDep.Name, cd.Namespace, err)
 		}
 
+		// keep the initial templates for rollback
+		if err := recordInitialRevision(c.kubeClient, c.configTracker, cd, created.Spec.Template, *target); err != nil {
+			return fmt.Errorf("recordInitialRevision failed: %w", err)
+		}
+
 		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
 			Infof("Deployment %s.%s created", primaryDep.GetName(), cd.Namespace)
 	}
@@ -348,6 +382,9 @@ func (c *DeploymentController) HaveDependenciesChanged(cd *flaggerv1.Canary) (bo
 // during a delete to attempt to revert the deployment back to the original state.  Error is returned if unable
 // update the reference deployment replicas to the primary replicas
 func (c *DeploymentController) Finalize(cd *flaggerv1.Canary) error {
+	if err := c.DeleteBaseline(cd); err != nil {
+		return fmt.Errorf("DeleteBaseline failed: %w", err)
+	}
 
 	// get ref deployment
 	refDep, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), cd.Spec.TargetRef.Name, metav1.GetOptions{})
@@ -378,6 +415,28 @@ func (c *DeploymentController) Finalize(cd *flaggerv1.Canary) error {
 	return nil
 }
 
+// GetReplicas returns the desired replicas of the canary and primary deployments
+func (c *DeploymentController) GetReplicas(cd *flaggerv1.Canary) (int32, int32, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("deployment %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	primary, err := c.kubeClient.AppsV1().Deployments(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("deployment %s.%s get query error: %w", primaryName, cd.Namespace, err)
+	}
+
+	return int32Default(canary.Spec.Replicas), int32Default(primary.Spec.Replicas), nil
+}
+
+// ScaleCanary sets the canary deployment replicas
+func (c *DeploymentController) ScaleCanary(cd *flaggerv1.Canary, replicas int32) error {
+	return c.scale(cd, replicas)
+}
+
 // Scale sets the canary deployment replicas
 func (c *DeploymentController) scale(cd *flaggerv1.Canary, replicas int32) error {
 	targetName := cd.Spec.TargetRef.Name
@@ -441,6 +500,38 @@ func (c *DeploymentController) appendPrimarySuffixToValuesIfNeeded(labelSelector
 	}
 }
 
+// setPrimaryRollout overrides the rolling update settings and the progress deadline
+// of the primary deployment with the analysis primary rollout settings
+func setPrimaryRollout(cd *flaggerv1.Canary, spec *appsv1.DeploymentSpec) {
+	if cd.GetAnalysis() == nil || cd.GetAnalysis().PrimaryRollout == nil {
+		return
+	}
+	rollout := cd.GetAnalysis().PrimaryRollout
+
+	if rollout.MaxSurge != nil || rollout.MaxUnavailable != nil {
+		rollingUpdate := &appsv1.RollingUpdateDeployment{}
+		if spec.Strategy.Type != appsv1.RecreateDeploymentStrategyType && spec.Strategy.RollingUpdate != nil {
+			rollingUpdate = spec.Strategy.RollingUpdate.DeepCopy()
+		}
+		if rollout.MaxSurge != nil {
+			maxSurge := *rollout.MaxSurge
+			rollingUpdate.MaxSurge = &maxSurge
+		}
+		if rollout.MaxUnavailable != nil {
+			maxUnavailable := *rollout.MaxUnavailable
+			rollingUpdate.MaxUnavailable = &maxUnavailable
+		}
+		spec.Strategy = appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: rollingUpdate,
+		}
+	}
+
+	if rollout.ProgressDeadlineSeconds != nil {
+		spec.ProgressDeadlineSeconds = int32p(*rollout.ProgressDeadlineSeconds)
+	}
+}
+
 func contains(slice []string, val string) bool {
 	for _, item := range slice {
 		if item == val {
@@ -24,10 +24,12 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 )
@@ -112,6 +114,32 @@ func TestDeploymentController_Promote(t *testing.T) {
 	assert.Equal(t, "podinfo-primary", value)
 }
 
+func TestDeploymentController_PromotePrimaryRollout(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	maxSurge := intstr.FromString("25%")
+	maxUnavailable := intstr.FromInt(0)
+	mocks.canary.Spec.Analysis.PrimaryRollout = &flaggerv1.CanaryPrimaryRollout{
+		MaxSurge:                &maxSurge,
+		MaxUnavailable:          &maxUnavailable,
+		ProgressDeadlineSeconds: int32p(120),
+	}
+
+	err := mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	depPrimary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, appsv1.RollingUpdateDeploymentStrategyType, depPrimary.Spec.Strategy.Type)
+	require.NotNil(t, depPrimary.Spec.Strategy.RollingUpdate)
+	assert.Equal(t, maxSurge, *depPrimary.Spec.Strategy.RollingUpdate.MaxSurge)
+	assert.Equal(t, maxUnavailable, *depPrimary.Spec.Strategy.RollingUpdate.MaxUnavailable)
+	assert.Equal(t, int32(120), *depPrimary.Spec.ProgressDeadlineSeconds)
+}
+
 func TestDeploymentController_ScaleToZero(t *testing.T) {
 	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
 	mocks := newDeploymentFixture(dc)
@@ -37,7 +37,12 @@ func (c *DeploymentController) IsPrimaryReady(cd *flaggerv1.Canary) (bool, error
 		return true, fmt.Errorf("deployment %s.%s get query error: %w", primaryName, cd.Namespace, err)
 	}
 
-	retriable, err := c.isDeploymentReady(primary, cd.GetProgressDeadlineSeconds(), cd.GetAnalysisPrimaryReadyThreshold())
+	deadline := cd.GetProgressDeadlineSeconds()
+	if cd.Status.Phase == flaggerv1.CanaryPhasePromoting {
+		deadline = cd.GetPromotionProgressDeadlineSeconds()
+	}
+
+	retriable, err := c.isDeploymentReady(primary, deadline, cd.GetAnalysisPrimaryReadyThreshold())
 	if err != nil {
 		return retriable, fmt.Errorf("%s.%s not ready: %w", primaryName, cd.Namespace, err)
 	}
@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestDeploymentController_RollbackPrimary(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	dep2 := newDeploymentControllerTestV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	// the first promotion records the initial primary and the promoted template
+	revisions, err := getPromotedRevisions(mocks.kubeClient, mocks.canary)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 1, revisions[0].Revision)
+	assert.Equal(t, 2, revisions[1].Revision)
+	assert.Equal(t, dep2.Spec.Template.Spec.Containers[0].Image, revisions[1].Template.Spec.Containers[0].Image)
+
+	err = mocks.controller.RollbackPrimary(mocks.canary, 1)
+	require.NoError(t, err)
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, revisions[0].Template.Spec.Containers[0].Image, primary.Spec.Template.Spec.Containers[0].Image)
+
+	// the configs of the revision are tracked
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, cd.Status.TrackedConfigs)
+	assert.Equal(t, revisions[0].TrackedConfigs, *cd.Status.TrackedConfigs)
+
+	// the rollback is recorded as a new revision
+	revisions, err = getPromotedRevisions(mocks.kubeClient, mocks.canary)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, 3, revisions[2].Revision)
+
+	err = mocks.controller.RollbackPrimary(mocks.canary, 10)
+	assert.True(t, errors.Is(err, ErrRevisionNotFound))
+}
+
+func TestDeploymentController_RollbackPrimaryConfigChanged(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	dep2 := newDeploymentControllerTestV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	mocks.canary.Status.TrackedConfigs, err = mocks.controller.configTracker.GetConfigRefs(mocks.canary)
+	require.NoError(t, err)
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	// the configs are changed by a config only promotion
+	_, err = mocks.kubeClient.CoreV1().ConfigMaps("default").Update(context.TODO(),
+		newDeploymentControllerTestConfigMapV2(), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	mocks.canary.Status.TrackedConfigs, err = mocks.controller.configTracker.GetConfigRefs(mocks.canary)
+	require.NoError(t, err)
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	revisions, err := getPromotedRevisions(mocks.kubeClient, mocks.canary)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+
+	for _, revision := range []int{1, 2} {
+		err = mocks.controller.RollbackPrimary(mocks.canary, revision)
+		assert.True(t, errors.Is(err, ErrConfigChanged))
+	}
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, dep2.Spec.Template.Spec.Containers[0].Image, primary.Spec.Template.Spec.Containers[0].Image)
+
+	// the rollback to a revision with the same configs is applied
+	err = mocks.controller.RollbackPrimary(mocks.canary, 3)
+	require.NoError(t, err)
+}
+
+func TestDeploymentController_RevertPrimaryConfigChanged(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	dep2 := newDeploymentControllerTestV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = mocks.kubeClient.CoreV1().ConfigMaps("default").Update(context.TODO(),
+		newDeploymentControllerTestConfigMapV2(), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	// the primary config map data is promoted with the template
+	configPrimary, err := mocks.kubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), "podinfo-config-env-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, newDeploymentControllerTestConfigMapV2().Data, configPrimary.Data)
+
+	// the previous template can't run with the promoted configs
+	err = mocks.controller.RevertPrimary(mocks.canary)
+	assert.True(t, errors.Is(err, ErrConfigChanged))
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, dep2.Spec.Template.Spec.Containers[0].Image, primary.Spec.Template.Spec.Containers[0].Image)
+
+	revisions, err := getPromotedRevisions(mocks.kubeClient, mocks.canary)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 2)
+}
+
+func TestDeploymentController_RevisionHistoryLimit(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	limit := int32(2)
+	mocks.canary.Spec.RevisionHistoryLimit = &limit
+
+	for i := 0; i < 3; i++ {
+		err := mocks.controller.Promote(mocks.canary)
+		require.NoError(t, err)
+	}
+
+	revisions, err := getPromotedRevisions(mocks.kubeClient, mocks.canary)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	assert.Equal(t, 3, revisions[0].Revision)
+	assert.Equal(t, 4, revisions[1].Revision)
+}
+
+func TestDeploymentController_RevertTarget(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	dep2 := newDeploymentControllerTestV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.canary.Status.LastAppliedSpec = ComputeHash(dep2.Spec.Template)
+	err = mocks.controller.RevertTarget(mocks.canary)
+	require.NoError(t, err)
+
+	// the target is restored to the template recorded on initialization
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.0", dep.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, ComputeHash(dep2.Spec.Template), dep.Annotations[flaggerv1.RejectedRevisionAnnotation])
+	for _, v := range dep.Spec.Template.Spec.Volumes {
+		if v.ConfigMap != nil {
+			assert.False(t, strings.HasSuffix(v.ConfigMap.Name, "-primary"))
+		}
+	}
+
+	// the reverted spec is marked as promoted
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, ComputeHash(dep.Spec.Template), cd.Status.LastPromotedSpec)
+}
+
+func TestDeploymentController_RevertTargetConfigChanged(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	revisions, err := getPromotedRevisions(mocks.kubeClient, mocks.canary)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	promoted := revisions[0].TrackedConfigs
+	require.NotEmpty(t, promoted)
+
+	dep2 := newDeploymentControllerTestV2()
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = mocks.kubeClient.CoreV1().ConfigMaps("default").Update(context.TODO(),
+		newDeploymentControllerTestConfigMapV2(), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	live, err := mocks.controller.configTracker.GetConfigRefs(mocks.canary)
+	require.NoError(t, err)
+	require.NotEqual(t, promoted, *live)
+
+	// the changed configs weren't promoted and stay untracked
+	mocks.canary.Status.LastAppliedSpec = ComputeHash(dep2.Spec.Template)
+	mocks.canary.Status.TrackedConfigs = live
+	err = mocks.controller.RevertTarget(mocks.canary)
+	require.NoError(t, err)
+
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, cd.Status.TrackedConfigs)
+	assert.Equal(t, promoted, *cd.Status.TrackedConfigs)
+
+	// the configs analysed with the promoted template are tracked
+	cd.Status.LastAppliedSpec = cd.Status.LastPromotedSpec
+	err = mocks.controller.RevertTarget(cd)
+	require.NoError(t, err)
+
+	live, err = mocks.controller.configTracker.GetConfigRefs(cd)
+	require.NoError(t, err)
+	require.NotEqual(t, promoted, *live)
+
+	cd, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, cd.Status.TrackedConfigs)
+	assert.Equal(t, *live, *cd.Status.TrackedConfigs)
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -76,3 +77,58 @@ func TestDeploymentController_SetState(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, flaggerv1.CanaryPhaseProgressing, res.Status.Phase)
 }
+
+func TestDeploymentController_SetMetricFailedChecks(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	err := SetStatusMetricFailedChecks(mocks.flaggerClient, mocks.canary, "error-rate", 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mocks.canary.Status.MetricFailedChecks["error-rate"])
+
+	res, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"error-rate": 2}, res.Status.MetricFailedChecks)
+}
+
+func TestDeploymentController_SetWaiting(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	err := SetStatusWaiting(mocks.flaggerClient, mocks.canary, "Concurrency limit reached.", 2)
+	require.NoError(t, err)
+
+	res, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseWaiting, res.Status.Phase)
+	assert.Equal(t, 2, res.Status.QueuePosition)
+	require.Len(t, res.Status.Conditions, 1)
+	assert.Equal(t, "Concurrency limit reached.", res.Status.Conditions[0].Message)
+}
+
+func TestDeploymentController_SetWarmup(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.initializeCanary(t)
+
+	err := SetStatusWarmup(mocks.flaggerClient, mocks.canary, corev1.ConditionFalse, "WarmingUp", "Warming up.", 1)
+	require.NoError(t, err)
+	err = SetStatusWarmup(mocks.flaggerClient, mocks.canary, corev1.ConditionTrue, "WarmupCompleted", "Warm-up completed.", 2)
+	require.NoError(t, err)
+
+	res, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Status.WarmupIterations)
+
+	var warmup []flaggerv1.CanaryCondition
+	for _, condition := range res.Status.Conditions {
+		if condition.Type == flaggerv1.WarmedUpType {
+			warmup = append(warmup, condition)
+		}
+	}
+	require.Len(t, warmup, 1)
+	assert.Equal(t, corev1.ConditionTrue, warmup[0].Status)
+	assert.Equal(t, "WarmupCompleted", warmup[0].Reason)
+}
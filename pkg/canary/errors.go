@@ -0,0 +1,27 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import "errors"
+
+var (
+	// ErrRevisionNotFound is returned when the rollback revision is not in the history
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	// ErrConfigChanged is returned when the primary configs differ from the configs of the rollback revision
+	ErrConfigChanged = errors.New("primary configs changed")
+)
@@ -18,6 +18,8 @@ package canary
 
 import (
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -29,6 +31,8 @@ type Factory struct {
 	kubeClient         kubernetes.Interface
 	flaggerClient      clientset.Interface
 	knativeClient      knative.Interface
+	dynamicClient      dynamic.Interface
+	mapper             meta.RESTMapper
 	logger             *zap.SugaredLogger
 	configTracker      Tracker
 	labels             []string
@@ -38,6 +42,8 @@ type Factory struct {
 func NewFactory(kubeClient kubernetes.Interface,
 	flaggerClient clientset.Interface,
 	knativeClient knative.Interface,
+	dynamicClient dynamic.Interface,
+	mapper meta.RESTMapper,
 	configTracker Tracker,
 	labels []string,
 	includeLabelPrefix []string,
@@ -46,6 +52,8 @@ func NewFactory(kubeClient kubernetes.Interface,
 		kubeClient:         kubeClient,
 		flaggerClient:      flaggerClient,
 		knativeClient:      knativeClient,
+		dynamicClient:      dynamicClient,
+		mapper:             mapper,
 		logger:             logger,
 		configTracker:      configTracker,
 		labels:             labels,
@@ -70,12 +78,30 @@ func (factory *Factory) Controller(obj v1beta1.LocalObjectReference) Controller
 		configTracker:      factory.configTracker,
 		includeLabelPrefix: factory.includeLabelPrefix,
 	}
+	statefulSetCtrl := &StatefulSetController{
+		logger:             factory.logger,
+		kubeClient:         factory.kubeClient,
+		flaggerClient:      factory.flaggerClient,
+		labels:             factory.labels,
+		configTracker:      factory.configTracker,
+		includeLabelPrefix: factory.includeLabelPrefix,
+	}
 	serviceCtrl := &ServiceController{
 		logger:             factory.logger,
 		kubeClient:         factory.kubeClient,
 		flaggerClient:      factory.flaggerClient,
 		includeLabelPrefix: factory.includeLabelPrefix,
 	}
+	genericCtrl := &GenericController{
+		logger:             factory.logger,
+		kubeClient:         factory.kubeClient,
+		dynamicClient:      factory.dynamicClient,
+		mapper:             factory.mapper,
+		flaggerClient:      factory.flaggerClient,
+		labels:             factory.labels,
+		configTracker:      factory.configTracker,
+		includeLabelPrefix: factory.includeLabelPrefix,
+	}
 	knativeCtrl := &KnativeController{
 		flaggerClient: factory.flaggerClient,
 		knativeClient: factory.knativeClient,
@@ -86,6 +112,8 @@ func (factory *Factory) Controller(obj v1beta1.LocalObjectReference) Controller
 		return daemonSetCtrl
 	case "Deployment":
 		return deploymentCtrl
+	case "StatefulSet":
+		return statefulSetCtrl
 	case "Service":
 		if obj.IsKnativeService() {
 			return knativeCtrl
@@ -93,10 +121,28 @@ func (factory *Factory) Controller(obj v1beta1.LocalObjectReference) Controller
 			return serviceCtrl
 		}
 	default:
+		if obj.IsGenericWorkload() {
+			return genericCtrl
+		}
 		return deploymentCtrl
 	}
 }
 
+// CanaryController returns the controller of the canary target, wrapped to
+// roll out the additional targets together with the target when there are any
+func (factory *Factory) CanaryController(cd *v1beta1.Canary) Controller {
+	target := factory.Controller(cd.Spec.TargetRef)
+	if len(cd.Spec.AdditionalTargetRefs) == 0 {
+		return target
+	}
+	return &MultiTargetController{
+		kubeClient:    factory.kubeClient,
+		configTracker: factory.configTracker,
+		target:        target,
+		factory:       factory,
+	}
+}
+
 func (factory *Factory) ScalerReconciler(kind string) ScalerReconciler {
 	hpaReconciler := &HPAReconciler{
 		logger:             factory.logger,
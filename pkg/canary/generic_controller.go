@@ -0,0 +1,445 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+// GenericController is managing the operations for the custom resources embedding a pod template,
+// the fields are located with the canary target paths and accessed through the dynamic client
+type GenericController struct {
+	kubeClient         kubernetes.Interface
+	dynamicClient      dynamic.Interface
+	mapper             meta.RESTMapper
+	flaggerClient      clientset.Interface
+	logger             *zap.SugaredLogger
+	configTracker      Tracker
+	labels             []string
+	includeLabelPrefix []string
+}
+
+// Initialize creates the primary resource if it does not exist.
+func (c *GenericController) Initialize(cd *flaggerv1.Canary) (bool, error) {
+	if err := c.createPrimary(cd, c.includeLabelPrefix); err != nil {
+		return true, fmt.Errorf("createPrimary failed: %w", err)
+	}
+
+	if cd.Status.Phase == "" || cd.Status.Phase == flaggerv1.CanaryPhaseInitializing {
+		if !cd.SkipAnalysis() {
+			if retriable, err := c.IsPrimaryReady(cd); err != nil {
+				return retriable, fmt.Errorf("%w", err)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Promote copies the spec, secrets and config maps from canary to primary,
+// the primary selector is kept and so are the primary replicas if an autoscaler is set
+func (c *GenericController) Promote(cd *flaggerv1.Canary) error {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+
+	paths, err := parseTargetPaths(cd.Spec.TargetPaths)
+	if err != nil {
+		return err
+	}
+	resource, err := c.resource(cd)
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		canary, err := resource.Get(context.TODO(), targetName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("%s %s.%s get query error: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+		}
+
+		label, labelValue, err := c.getSelectorLabel(canary, paths)
+		primaryLabelValue := fmt.Sprintf("%s-primary", labelValue)
+		if err != nil {
+			return fmt.Errorf("getSelectorLabel failed: %w", err)
+		}
+
+		primary, err := resource.Get(context.TODO(), primaryName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("%s %s.%s get query error: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+		}
+
+		// promote secrets and config maps
+		configRefs, err := c.configTracker.GetTargetConfigs(cd)
+		if err != nil {
+			return fmt.Errorf("GetTargetConfigs failed: %w", err)
+		}
+		if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
+			return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
+		}
+
+		spec, _, err := unstructured.NestedMap(canary.Object, "spec")
+		if err != nil {
+			return fmt.Errorf("%s %s.%s spec decoding failed: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+		}
+
+		primaryCopy := primary.DeepCopy()
+		primaryCopy.Object["spec"] = spec
+
+		// keep the primary selector, it is usually immutable
+		selector, found, _ := unstructured.NestedFieldCopy(primary.Object, paths.selector...)
+		if found {
+			if err := unstructured.SetNestedField(primaryCopy.Object, selector, paths.selector...); err != nil {
+				return fmt.Errorf("setting selector failed: %w", err)
+			}
+		}
+		// keep the primary replicas if hpa is set
+		if cd.Spec.AutoscalerRef != nil {
+			if replicas, found, _ := unstructured.NestedFieldCopy(primary.Object, paths.replicas...); found {
+				if err := unstructured.SetNestedField(primaryCopy.Object, replicas, paths.replicas...); err != nil {
+					return fmt.Errorf("setting replicas failed: %w", err)
+				}
+			}
+		}
+
+		// update template with primary secrets and config maps and pod annotations to ensure a rolling update
+		if err := c.setPrimaryTemplate(primaryCopy, canary, paths, label, primaryLabelValue, configRefs); err != nil {
+			return err
+		}
+
+		// update annotations and labels
+		primaryCopy.SetAnnotations(includeLabelsByPrefix(canary.GetAnnotations(), c.includeLabelPrefix))
+		filteredLabels := includeLabelsByPrefix(canary.GetLabels(), c.includeLabelPrefix)
+		primaryCopy.SetLabels(makePrimaryLabels(filteredLabels, primaryLabelValue, label))
+
+		// apply update
+		_, err = resource.Update(context.TODO(), primaryCopy, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("updating %s %s.%s template spec failed: %w",
+			cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+	}
+
+	return nil
+}
+
+// HasTargetChanged returns true if the canary pod template has changed
+func (c *GenericController) HasTargetChanged(cd *flaggerv1.Canary) (bool, error) {
+	canary, paths, err := c.getTarget(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return false, err
+	}
+
+	template, err := getPodTemplate(canary, paths)
+	if err != nil {
+		return false, err
+	}
+	return hasSpecChanged(cd, template)
+}
+
+// ScaleToZero sets the canary replicas to zero
+func (c *GenericController) ScaleToZero(cd *flaggerv1.Canary) error {
+	return c.scale(cd, 0)
+}
+
+// ScaleFromZero sets the canary replicas to the primary or autoscaler replicas
+func (c *GenericController) ScaleFromZero(cd *flaggerv1.Canary) error {
+	targetName := cd.Spec.TargetRef.Name
+	canary, paths, err := c.getTarget(cd, targetName)
+	if err != nil {
+		return err
+	}
+
+	replicas := int32p(1)
+	if r := getCount(canary, paths.replicas); r != nil && *r > 0 {
+		replicas = r
+	} else if cd.Spec.AutoscalerRef == nil {
+		// If HPA isn't set and replicas are not specified, it uses the primary replicas when scaling up the canary
+		primary, _, err := c.getTarget(cd, fmt.Sprintf("%s-primary", targetName))
+		if err != nil {
+			return err
+		}
+
+		if r := getCount(primary, paths.replicas); r != nil && *r > 0 {
+			replicas = r
+		}
+	} else if minReplicas := getAutoscalerMinReplicas(c.kubeClient, c.flaggerClient, cd); minReplicas != nil {
+		replicas = minReplicas
+	}
+
+	return c.scale(cd, *replicas)
+}
+
+// GetMetadata returns the pod label selector and svc ports
+func (c *GenericController) GetMetadata(cd *flaggerv1.Canary) (string, string, map[string]int32, error) {
+	canary, paths, err := c.getTarget(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	label, labelValue, err := c.getSelectorLabel(canary, paths)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("getSelectorLabel failed: %w", err)
+	}
+
+	var ports map[string]int32
+	if cd.Spec.Service.PortDiscovery {
+		template, err := getPodTemplate(canary, paths)
+		if err != nil {
+			return "", "", nil, err
+		}
+		ports = getPorts(cd, template.Spec.Containers)
+	}
+
+	return label, labelValue, ports, nil
+}
+
+func (c *GenericController) HaveDependenciesChanged(cd *flaggerv1.Canary) (bool, error) {
+	return c.configTracker.HasConfigChanged(cd)
+}
+
+// Finalize sets the replica count from the primary to the reference resource,
+// if the primary can't be found the reference resource is scaled from zero
+func (c *GenericController) Finalize(cd *flaggerv1.Canary) error {
+	canary, paths, err := c.getTarget(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return err
+	}
+
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	primary, _, err := c.getTarget(cd, primaryName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if err := c.ScaleFromZero(cd); err != nil {
+				return fmt.Errorf("ScaleFromZero failed: %w", err)
+			}
+			return nil
+		}
+		return err
+	}
+
+	primaryReplicas := int32Default(getCount(primary, paths.replicas))
+	if int32Default(getCount(canary, paths.replicas)) != primaryReplicas {
+		if err := c.scale(cd, primaryReplicas); err != nil {
+			return fmt.Errorf("scale failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *GenericController) createPrimary(cd *flaggerv1.Canary, includeLabelPrefix []string) error {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+
+	canary, paths, err := c.getTarget(cd, targetName)
+	if err != nil {
+		return err
+	}
+
+	// Create the labels map but filter unwanted labels
+	labels := includeLabelsByPrefix(canary.GetLabels(), includeLabelPrefix)
+
+	label, labelValue, err := c.getSelectorLabel(canary, paths)
+	primaryLabelValue := fmt.Sprintf("%s-primary", labelValue)
+	if err != nil {
+		return fmt.Errorf("getSelectorLabel failed: %w", err)
+	}
+
+	_, _, err = c.getTarget(cd, primaryName)
+	if !errors.IsNotFound(err) {
+		return nil
+	}
+
+	// create primary secrets and config maps
+	configRefs, err := c.configTracker.GetTargetConfigs(cd)
+	if err != nil {
+		return fmt.Errorf("GetTargetConfigs failed: %w", err)
+	}
+	if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
+		return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
+	}
+
+	spec, _, err := unstructured.NestedMap(canary.Object, "spec")
+	if err != nil {
+		return fmt.Errorf("%s %s.%s spec decoding failed: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+	}
+
+	replicas := int32(1)
+	if r := getCount(canary, paths.replicas); r != nil && *r > 0 {
+		replicas = *r
+	}
+
+	// create primary resource from the canary spec
+	primary := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	primary.SetAPIVersion(canary.GetAPIVersion())
+	primary.SetKind(canary.GetKind())
+	primary.SetName(primaryName)
+	primary.SetNamespace(cd.Namespace)
+	primary.SetLabels(makePrimaryLabels(labels, primaryLabelValue, label))
+	primary.SetAnnotations(filterMetadata(canary.GetAnnotations()))
+	primary.SetOwnerReferences([]metav1.OwnerReference{
+		*metav1.NewControllerRef(cd, schema.GroupVersionKind{
+			Group:   flaggerv1.SchemeGroupVersion.Group,
+			Version: flaggerv1.SchemeGroupVersion.Version,
+			Kind:    flaggerv1.CanaryKind,
+		}),
+	})
+
+	selector := map[string]interface{}{
+		"matchLabels": map[string]interface{}{
+			label: primaryLabelValue,
+		},
+	}
+	if err := unstructured.SetNestedMap(primary.Object, selector, paths.selector...); err != nil {
+		return fmt.Errorf("setting selector failed: %w", err)
+	}
+	if err := unstructured.SetNestedField(primary.Object, int64(replicas), paths.replicas...); err != nil {
+		return fmt.Errorf("setting replicas failed: %w", err)
+	}
+	// update template with the primary secrets and config maps
+	if err := c.setPrimaryTemplate(primary, canary, paths, label, primaryLabelValue, configRefs); err != nil {
+		return err
+	}
+
+	resource, err := c.resource(cd)
+	if err != nil {
+		return err
+	}
+	if _, err := resource.Create(context.TODO(), primary, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("creating %s %s.%s failed: %w", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace, err)
+	}
+
+	c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+		Infof("%s %s.%s created", cd.Spec.TargetRef.Kind, primaryName, cd.Namespace)
+	return nil
+}
+
+// setPrimaryTemplate sets the canary pod template with the primary labels, annotations and configs
+func (c *GenericController) setPrimaryTemplate(primary *unstructured.Unstructured, canary *unstructured.Unstructured,
+	paths genericPaths, label string, primaryLabelValue string, configRefs map[string]ConfigRef) error {
+	template, err := getPodTemplate(canary, paths)
+	if err != nil {
+		return err
+	}
+
+	annotations, err := makeAnnotations(template.Annotations)
+	if err != nil {
+		return fmt.Errorf("makeAnnotations failed: %w", err)
+	}
+
+	template.Annotations = annotations
+	template.Labels = makePrimaryLabels(template.Labels, primaryLabelValue, label)
+	template.Spec = c.configTracker.ApplyPrimaryConfigs(template.Spec, configRefs)
+	return setPodTemplate(primary, paths, template)
+}
+
+// getSelectorLabel returns the selector match label
+func (c *GenericController) getSelectorLabel(obj *unstructured.Unstructured, paths genericPaths) (string, string, error) {
+	selector, err := getSelector(obj, paths)
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, l := range c.labels {
+		if _, ok := selector.MatchLabels[l]; ok {
+			return l, selector.MatchLabels[l], nil
+		}
+	}
+
+	return "", "", fmt.Errorf(
+		"%s %s.%s %s.matchLabels must contain one of %v",
+		obj.GetKind(), obj.GetName(), obj.GetNamespace(), joinFieldPath(paths.selector), c.labels,
+	)
+}
+
+// GetReplicas returns the desired replicas of the canary and primary resources
+func (c *GenericController) GetReplicas(cd *flaggerv1.Canary) (int32, int32, error) {
+	canary, paths, err := c.getTarget(cd, cd.Spec.TargetRef.Name)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	primary, _, err := c.getTarget(cd, fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int32Default(getCount(canary, paths.replicas)), int32Default(getCount(primary, paths.replicas)), nil
+}
+
+// ScaleCanary sets the canary replicas
+func (c *GenericController) ScaleCanary(cd *flaggerv1.Canary, replicas int32) error {
+	return c.scale(cd, replicas)
+}
+
+// scale sets the canary replicas
+func (c *GenericController) scale(cd *flaggerv1.Canary, replicas int32) error {
+	targetName := cd.Spec.TargetRef.Name
+	paths, err := parseTargetPaths(cd.Spec.TargetPaths)
+	if err != nil {
+		return err
+	}
+	resource, err := c.resource(cd)
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := resource.Get(context.TODO(), targetName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(obj.Object, int64(replicas), paths.replicas...); err != nil {
+			return err
+		}
+		_, err = resource.Update(context.TODO(), obj, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("scaling %s %s.%s to %d failed: %w", cd.Spec.TargetRef.Kind, targetName, cd.Namespace, replicas, err)
+	}
+	return nil
+}
+
+// getTarget returns the named resource of the target kind and the parsed target paths
+func (c *GenericController) getTarget(cd *flaggerv1.Canary, name string) (*unstructured.Unstructured, genericPaths, error) {
+	paths, err := parseTargetPaths(cd.Spec.TargetPaths)
+	if err != nil {
+		return nil, paths, err
+	}
+
+	obj, err := getGenericTarget(c.dynamicClient, c.mapper, cd.Spec.TargetRef, name, cd.Namespace)
+	return obj, paths, err
+}
+
+func (c *GenericController) resource(cd *flaggerv1.Canary) (dynamic.ResourceInterface, error) {
+	return genericResource(c.dynamicClient, c.mapper, cd.Spec.TargetRef, cd.Namespace)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestGenericController_Sync(t *testing.T) {
+	mocks := newGenericFixture()
+	_, err := mocks.controller.Initialize(mocks.canary)
+	require.Error(t, err, "primary is not ready before its pods are ready")
+
+	primary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	paths, err := parseTargetPaths(mocks.canary.Spec.TargetPaths)
+	require.NoError(t, err)
+
+	template, err := getPodTemplate(primary, paths)
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.0", template.Spec.Containers[0].Image)
+	assert.Equal(t, "podinfo-primary", template.Labels["app"])
+	assert.Equal(t, "podinfo-config-env-primary", template.Spec.Containers[0].EnvFrom[0].ConfigMapRef.Name)
+
+	selector, err := getSelector(primary, paths)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "podinfo-primary"}, selector.MatchLabels)
+
+	assert.Equal(t, int32(2), *getCount(primary, paths.replicas))
+	assert.Equal(t, "podinfo-primary", primary.GetLabels()["app"])
+	strategy, _, _ := unstructured.NestedString(primary.Object, "spec", "updateStrategy", "type")
+	assert.Equal(t, "InPlaceIfPossible", strategy)
+	require.Len(t, primary.GetOwnerReferences(), 1)
+	assert.Equal(t, "podinfo", primary.GetOwnerReferences()[0].Name)
+
+	label, labelValue, _, err := mocks.controller.GetMetadata(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, "app", label)
+	assert.Equal(t, "podinfo", labelValue)
+}
+
+func TestGenericController_Promote(t *testing.T) {
+	mocks := newGenericFixture()
+	mocks.controller.Initialize(mocks.canary)
+
+	cs2 := newGenericControllerTestCloneSet("quay.io/stefanprodan/podinfo:1.2.1")
+	unstructured.SetNestedField(cs2.Object, int64(3), "spec", "replicas")
+	_, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Update(context.TODO(), cs2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	primary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	paths, _ := parseTargetPaths(mocks.canary.Spec.TargetPaths)
+	template, err := getPodTemplate(primary, paths)
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.1", template.Spec.Containers[0].Image)
+	assert.Equal(t, "podinfo-config-env-primary", template.Spec.Containers[0].EnvFrom[0].ConfigMapRef.Name)
+	assert.Equal(t, int32(3), *getCount(primary, paths.replicas))
+	assert.Equal(t, "test-label-value-1", primary.GetLabels()["app.kubernetes.io/test-label-1"])
+
+	selector, err := getSelector(primary, paths)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "podinfo-primary"}, selector.MatchLabels)
+}
+
+func TestGenericController_HasTargetChanged(t *testing.T) {
+	mocks := newGenericFixture()
+	mocks.controller.Initialize(mocks.canary)
+	err := mocks.controller.SyncStatus(mocks.canary, mocks.canary.Status)
+	require.NoError(t, err)
+
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, cd.Status.TrackedConfigs)
+	assert.Contains(t, *cd.Status.TrackedConfigs, "configmap/podinfo-config-env")
+
+	isNew, err := mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	cs2 := newGenericControllerTestCloneSet("quay.io/stefanprodan/podinfo:1.2.1")
+	_, err = mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Update(context.TODO(), cs2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	isNew, err = mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+}
+
+func TestGenericController_Scale(t *testing.T) {
+	mocks := newGenericFixture()
+	mocks.controller.Initialize(mocks.canary)
+	paths, _ := parseTargetPaths(mocks.canary.Spec.TargetPaths)
+
+	err := mocks.controller.ScaleToZero(mocks.canary)
+	require.NoError(t, err)
+
+	canary, err := mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *getCount(canary, paths.replicas))
+
+	err = mocks.controller.Finalize(mocks.canary)
+	require.NoError(t, err)
+
+	canary, err = mocks.dynamicClient.Resource(cloneSetGVR).Namespace("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *getCount(canary, paths.replicas))
+}
+
+func TestGenericController_isReady(t *testing.T) {
+	mocks := newGenericFixture()
+	cd := &flaggerv1.Canary{}
+	paths, err := parseTargetPaths(nil)
+	require.NoError(t, err)
+
+	newObj := func(status map[string]interface{}) *unstructured.Unstructured {
+		obj := newGenericControllerTestCloneSet("quay.io/stefanprodan/podinfo:1.2.0")
+		obj.SetGeneration(2)
+		obj.Object["status"] = status
+		return obj
+	}
+
+	// observed generation is less than desired generation
+	retryable, err := mocks.controller.isReady(cd, newObj(map[string]interface{}{
+		"observedGeneration": int64(1),
+	}), paths, 100)
+	require.Error(t, err)
+	require.True(t, retryable)
+
+	// succeeded
+	retryable, err = mocks.controller.isReady(cd, newObj(map[string]interface{}{
+		"observedGeneration": int64(2),
+		"updatedReplicas":    int64(2),
+		"readyReplicas":      int64(2),
+	}), paths, 100)
+	require.NoError(t, err)
+	require.True(t, retryable)
+
+	// new replicas not updated
+	cd.Status.LastTransitionTime = metav1.Now()
+	cd.Spec.ProgressDeadlineSeconds = int32p(1e6)
+	_, err = mocks.controller.isReady(cd, newObj(map[string]interface{}{
+		"updatedReplicas": int64(1),
+		"readyReplicas":   int64(2),
+	}), paths, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "new replicas")
+
+	// ready replicas under the threshold
+	obj := newObj(map[string]interface{}{
+		"readyReplicas": int64(1),
+	})
+	_, err = mocks.controller.isReady(cd, obj, paths, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ready")
+
+	_, err = mocks.controller.isReady(cd, obj, paths, 50)
+	require.NoError(t, err)
+
+	// deadline exceeded
+	cd.Spec.ProgressDeadlineSeconds = int32p(-1e6)
+	retryable, err = mocks.controller.isReady(cd, obj, paths, 100)
+	require.Error(t, err)
+	require.False(t, retryable)
+}
+
+func TestVerifyTargetPaths(t *testing.T) {
+	require.NoError(t, VerifyTargetPaths(nil))
+	require.NoError(t, VerifyTargetPaths(&flaggerv1.TargetPaths{Template: "{.spec.podTemplate}", Replicas: ".spec.size"}))
+	require.Error(t, VerifyTargetPaths(&flaggerv1.TargetPaths{Template: "spec.template"}))
+	require.Error(t, VerifyTargetPaths(&flaggerv1.TargetPaths{Replicas: ".spec..replicas"}))
+	require.Error(t, VerifyTargetPaths(&flaggerv1.TargetPaths{Selector: ".spec.selectors[0]"}))
+}
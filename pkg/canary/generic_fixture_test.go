@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+	fakeFlagger "github.com/fluxcd/flagger/pkg/client/clientset/versioned/fake"
+	"github.com/fluxcd/flagger/pkg/logger"
+)
+
+var cloneSetGVR = schema.GroupVersionResource{Group: "apps.kruise.io", Version: "v1alpha1", Resource: "clonesets"}
+
+type genericControllerFixture struct {
+	canary        *flaggerv1.Canary
+	kubeClient    kubernetes.Interface
+	dynamicClient dynamic.Interface
+	flaggerClient clientset.Interface
+	controller    GenericController
+	logger        *zap.SugaredLogger
+}
+
+func newGenericFixture() genericControllerFixture {
+	// init canary
+	canary := newGenericControllerTestCanary()
+	flaggerClient := fakeFlagger.NewSimpleClientset(canary)
+
+	// init kube clientset and register mock objects
+	kubeClient := fake.NewSimpleClientset(
+		newGenericControllerTestConfigMap(),
+	)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{cloneSetGVR: "CloneSetList"},
+		newGenericControllerTestCloneSet("quay.io/stefanprodan/podinfo:1.2.0"),
+	)
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{cloneSetGVR.GroupVersion()})
+	mapper.Add(cloneSetGVR.GroupVersion().WithKind("CloneSet"), meta.RESTScopeNamespace)
+
+	logger, _ := logger.NewLogger("debug")
+
+	ctrl := GenericController{
+		flaggerClient:      flaggerClient,
+		kubeClient:         kubeClient,
+		dynamicClient:      dynamicClient,
+		mapper:             mapper,
+		logger:             logger,
+		labels:             []string{"app", "name"},
+		includeLabelPrefix: []string{"app.kubernetes.io"},
+		configTracker: &ConfigTracker{
+			Logger:        logger,
+			KubeClient:    kubeClient,
+			FlaggerClient: flaggerClient,
+			DynamicClient: dynamicClient,
+			RESTMapper:    mapper,
+		},
+	}
+
+	return genericControllerFixture{
+		canary:        canary,
+		controller:    ctrl,
+		logger:        logger,
+		flaggerClient: flaggerClient,
+		kubeClient:    kubeClient,
+		dynamicClient: dynamicClient,
+	}
+}
+
+func newGenericControllerTestCanary() *flaggerv1.Canary {
+	return &flaggerv1.Canary{
+		TypeMeta: metav1.TypeMeta{APIVersion: flaggerv1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "podinfo",
+		},
+		Spec: flaggerv1.CanarySpec{
+			TargetRef: flaggerv1.LocalObjectReference{
+				Name:       "podinfo",
+				APIVersion: "apps.kruise.io/v1alpha1",
+				Kind:       "CloneSet",
+			},
+			TargetPaths: &flaggerv1.TargetPaths{
+				UpdatedReplicas: "{.status.updatedReadyReplicas}",
+			},
+			Analysis: &flaggerv1.CanaryAnalysis{},
+		},
+	}
+}
+
+func newGenericControllerTestConfigMap() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "podinfo-config-env",
+		},
+		Data: map[string]string{
+			"color": "red",
+		},
+	}
+}
+
+func newGenericControllerTestCloneSet(image string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps.kruise.io/v1alpha1",
+		"kind":       "CloneSet",
+		"metadata": map[string]interface{}{
+			"name":      "podinfo",
+			"namespace": "default",
+			"labels": map[string]interface{}{
+				"app.kubernetes.io/test-label-1": "test-label-value-1",
+			},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					"app": "podinfo",
+				},
+			},
+			"updateStrategy": map[string]interface{}{
+				"type":           "InPlaceIfPossible",
+				"maxUnavailable": int64(1),
+			},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"app": "podinfo",
+					},
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "podinfo",
+							"image": image,
+							"envFrom": []interface{}{
+								map[string]interface{}{
+									"configMapRef": map[string]interface{}{
+										"name": "podinfo-config-env",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// genericPaths holds the parsed field paths of a custom resource target
+type genericPaths struct {
+	template        []string
+	replicas        []string
+	selector        []string
+	readyReplicas   []string
+	updatedReplicas []string
+}
+
+// VerifyTargetPaths returns an error if one of the target paths is invalid
+func VerifyTargetPaths(paths *flaggerv1.TargetPaths) error {
+	_, err := parseTargetPaths(paths)
+	return err
+}
+
+// parseTargetPaths parses the target paths of a canary and fills in the defaults
+func parseTargetPaths(paths *flaggerv1.TargetPaths) (genericPaths, error) {
+	if paths == nil {
+		paths = &flaggerv1.TargetPaths{}
+	}
+
+	var result genericPaths
+	var err error
+	for _, p := range []struct {
+		field  *[]string
+		value  string
+		defVal string
+	}{
+		{&result.template, paths.Template, ".spec.template"},
+		{&result.replicas, paths.Replicas, ".spec.replicas"},
+		{&result.selector, paths.Selector, ".spec.selector"},
+		{&result.readyReplicas, paths.ReadyReplicas, ".status.readyReplicas"},
+		{&result.updatedReplicas, paths.UpdatedReplicas, ".status.updatedReplicas"},
+	} {
+		value := p.value
+		if value == "" {
+			value = p.defVal
+		}
+		if *p.field, err = parseFieldPath(value); err != nil {
+			return genericPaths{}, err
+		}
+	}
+	return result, nil
+}
+
+// parseFieldPath splits a JSONPath such as `.spec.template` or `{.spec.template}` into its fields
+func parseFieldPath(path string) ([]string, error) {
+	p := strings.TrimSpace(path)
+	p = strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}")
+	if !strings.HasPrefix(p, ".") || strings.ContainsAny(p, "[]*") {
+		return nil, fmt.Errorf("path %q is invalid, must be in the .spec.template form", path)
+	}
+
+	fields := strings.Split(strings.TrimPrefix(p, "."), ".")
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("path %q is invalid, must be in the .spec.template form", path)
+		}
+	}
+	return fields, nil
+}
+
+func joinFieldPath(fields []string) string {
+	return "." + strings.Join(fields, ".")
+}
+
+// getGenericTarget returns a custom resource target through the dynamic client
+func getGenericTarget(client dynamic.Interface, mapper meta.RESTMapper, ref flaggerv1.LocalObjectReference,
+	name string, namespace string) (*unstructured.Unstructured, error) {
+	resource, err := genericResource(client, mapper, ref, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := resource.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%s %s.%s get query error: %w", ref.Kind, name, namespace, err)
+	}
+	return obj, nil
+}
+
+// genericResource maps the target kind to its resource
+func genericResource(client dynamic.Interface, mapper meta.RESTMapper, ref flaggerv1.LocalObjectReference,
+	namespace string) (dynamic.ResourceInterface, error) {
+	if client == nil || mapper == nil {
+		return nil, fmt.Errorf("%s targets are not supported, the dynamic client isn't configured", ref.Kind)
+	}
+
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("apiVersion %s is invalid: %w", ref.APIVersion, err)
+	}
+
+	mapping, err := mapper.RESTMapping(gv.WithKind(ref.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		// rediscover the API resources on the next run in case the CRD was installed after the mapper cache
+		if resettable, ok := mapper.(meta.ResettableRESTMapper); ok && meta.IsNoMatchError(err) {
+			resettable.Reset()
+		}
+		return nil, fmt.Errorf("%s.%s resource mapping failed: %w", ref.Kind, ref.APIVersion, err)
+	}
+	return client.Resource(mapping.Resource).Namespace(namespace), nil
+}
+
+// getPodTemplate returns the pod template found at the template path
+func getPodTemplate(obj *unstructured.Unstructured, paths genericPaths) (corev1.PodTemplateSpec, error) {
+	var template corev1.PodTemplateSpec
+	value, found, err := unstructured.NestedMap(obj.Object, paths.template...)
+	if err != nil || !found {
+		return template, fmt.Errorf("%s %s.%s pod template not found at %s",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), joinFieldPath(paths.template))
+	}
+
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(value, &template); err != nil {
+		return template, fmt.Errorf("%s %s.%s pod template decoding failed: %w",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	return template, nil
+}
+
+// setPodTemplate replaces the pod template found at the template path
+func setPodTemplate(obj *unstructured.Unstructured, paths genericPaths, template corev1.PodTemplateSpec) error {
+	value, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&template)
+	if err != nil {
+		return fmt.Errorf("pod template encoding failed: %w", err)
+	}
+	return unstructured.SetNestedMap(obj.Object, value, paths.template...)
+}
+
+// getSelector returns the label selector found at the selector path
+func getSelector(obj *unstructured.Unstructured, paths genericPaths) (*metav1.LabelSelector, error) {
+	value, found, err := unstructured.NestedMap(obj.Object, paths.selector...)
+	if err != nil || !found {
+		return nil, fmt.Errorf("%s %s.%s selector not found at %s",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), joinFieldPath(paths.selector))
+	}
+
+	selector := &metav1.LabelSelector{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(value, selector); err != nil {
+		return nil, fmt.Errorf("%s %s.%s selector decoding failed: %w",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(), err)
+	}
+	return selector, nil
+}
+
+// getCount returns the integer found at the path or nil when the field isn't set
+func getCount(obj *unstructured.Unstructured, path []string) *int32 {
+	value, found, err := unstructured.NestedInt64(obj.Object, path...)
+	if err != nil || !found {
+		return nil
+	}
+	return int32p(int32(value))
+}
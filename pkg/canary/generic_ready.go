@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// IsPrimaryReady checks the primary status and returns an error if
+// the resource is in the middle of a rolling update or if the pods are unhealthy
+// it will return a non retryable error if the rolling update is stuck
+func (c *GenericController) IsPrimaryReady(cd *flaggerv1.Canary) (bool, error) {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	primary, paths, err := c.getTarget(cd, primaryName)
+	if err != nil {
+		return true, err
+	}
+
+	retriable, err := c.isReady(cd, primary, paths, cd.GetAnalysisPrimaryReadyThreshold())
+	if err != nil {
+		return retriable, fmt.Errorf("%s.%s not ready: %w", primaryName, cd.Namespace, err)
+	}
+
+	if r := getCount(primary, paths.replicas); r != nil && *r == 0 {
+		return false, fmt.Errorf("halt %s.%s advancement: primary %s is scaled to zero",
+			cd.Name, cd.Namespace, cd.Spec.TargetRef.Kind)
+	}
+	return true, nil
+}
+
+// IsCanaryReady checks the canary status and returns an error if
+// the resource is in the middle of a rolling update or if the pods are unhealthy
+// it will return a non retriable error if the rolling update is stuck
+func (c *GenericController) IsCanaryReady(cd *flaggerv1.Canary) (bool, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, paths, err := c.getTarget(cd, targetName)
+	if err != nil {
+		return true, err
+	}
+
+	retryable, err := c.isReady(cd, canary, paths, cd.GetAnalysisCanaryReadyThreshold())
+	if err != nil {
+		return retryable, fmt.Errorf("canary %s %s.%s not ready: %w",
+			cd.Spec.TargetRef.Kind, targetName, cd.Namespace, err)
+	}
+	return true, nil
+}
+
+// isReady determines if a resource is ready by checking the observed generation and
+// the replicas counts found at the target paths, the updated replicas are checked only if
+// the resource reports them and the canary last transition time is used to detect a stuck rollout
+func (c *GenericController) isReady(cd *flaggerv1.Canary, obj *unstructured.Unstructured, paths genericPaths, readyThreshold int) (bool, error) {
+	observed, found, _ := unstructured.NestedInt64(obj.Object, "status", "observedGeneration")
+	if found && obj.GetGeneration() > observed {
+		return true, fmt.Errorf("waiting for rollout to finish: observed generation less than desired generation")
+	}
+
+	replicas := int32Default(getCount(obj, paths.replicas))
+	readyThresholdRatio := float32(readyThreshold) / float32(100)
+	readyThresholdReplicas := int32(float32(replicas) * readyThresholdRatio)
+
+	// calculate conditions
+	updated := getCount(obj, paths.updatedReplicas)
+	newCond := updated != nil && *updated < replicas
+	ready := int32(0)
+	if r := getCount(obj, paths.readyReplicas); r != nil {
+		ready = *r
+	}
+	readyCond := ready < readyThresholdReplicas
+	if !newCond && !readyCond {
+		return true, nil
+	}
+
+	// check if deadline exceeded
+	from := cd.Status.LastTransitionTime
+	delta := time.Duration(cd.GetProgressDeadlineSeconds()) * time.Second
+	if from.Add(delta).Before(time.Now()) {
+		return false, fmt.Errorf("exceeded its progressDeadlineSeconds: %d", cd.GetProgressDeadlineSeconds())
+	}
+
+	// retryable
+	if newCond {
+		return true, fmt.Errorf("waiting for rollout to finish: %d out of %d new replicas have been updated",
+			*updated, replicas)
+	}
+	return true, fmt.Errorf("waiting for rollout to finish: %d of %d (readyThreshold %d%%) replicas are ready",
+		ready, readyThresholdReplicas, readyThreshold)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// VerifyIgnoreChanges returns an error if one of the ignored paths is invalid
+func VerifyIgnoreChanges(rules *flaggerv1.CanaryIgnoreChanges) error {
+	for _, path := range rules.Paths {
+		if _, err := parseFieldPath(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ignoreChanges returns a copy of the pod template without the fields excluded
+// from the change detection by the canary ignore rules
+func ignoreChanges(cd *flaggerv1.Canary, template corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+	rules := cd.Spec.IgnoreChanges
+	if rules == nil {
+		return template, nil
+	}
+
+	spec := *template.DeepCopy()
+	spec.Annotations = withoutKeys(spec.Annotations, rules.Annotations)
+	spec.Labels = withoutKeys(spec.Labels, rules.Labels)
+	if len(rules.Containers) > 0 {
+		removeContainers(&spec.Spec, rules.Containers)
+	}
+
+	if len(rules.Paths) == 0 {
+		return spec, nil
+	}
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&spec)
+	if err != nil {
+		return spec, fmt.Errorf("converting the pod template failed: %w", err)
+	}
+	for _, path := range rules.Paths {
+		fields, err := parseFieldPath(path)
+		if err != nil {
+			return spec, err
+		}
+		unstructured.RemoveNestedField(obj, fields...)
+	}
+
+	var result corev1.PodTemplateSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &result); err != nil {
+		return spec, fmt.Errorf("converting the pod template failed: %w", err)
+	}
+	return result, nil
+}
+
+// withoutKeys removes the keys from the map, an empty map is set to nil
+// so that it has the same hash as a missing one
+func withoutKeys(m map[string]string, keys []string) map[string]string {
+	for _, key := range keys {
+		delete(m, key)
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// removeContainers removes the named containers and init containers from the pod spec
+// along with the volumes that are mounted only by the removed containers
+func removeContainers(spec *corev1.PodSpec, names []string) {
+	ignored := make(map[string]bool, len(names))
+	for _, name := range names {
+		ignored[name] = true
+	}
+
+	mounts := make(map[string]bool)
+	removedMounts := make(map[string]bool)
+	filter := func(containers []corev1.Container) []corev1.Container {
+		var result []corev1.Container
+		for _, c := range containers {
+			for _, m := range c.VolumeMounts {
+				if ignored[c.Name] {
+					removedMounts[m.Name] = true
+				} else {
+					mounts[m.Name] = true
+				}
+			}
+			if !ignored[c.Name] {
+				result = append(result, c)
+			}
+		}
+		return result
+	}
+	spec.Containers = filter(spec.Containers)
+	spec.InitContainers = filter(spec.InitContainers)
+
+	var volumes []corev1.Volume
+	for _, v := range spec.Volumes {
+		if removedMounts[v.Name] && !mounts[v.Name] {
+			continue
+		}
+		volumes = append(volumes, v)
+	}
+	spec.Volumes = volumes
+}
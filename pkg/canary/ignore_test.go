@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestIgnoreChanges(t *testing.T) {
+	grace, changedGrace := int64(30), int64(60)
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"app": "podinfo", "injected": "true"},
+			Annotations: map[string]string{"kubectl.kubernetes.io/restartedAt": "2026-10-17T00:00:00Z"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:         "podinfo",
+					Image:        "quay.io/stefanprodan/podinfo:1.2.0",
+					VolumeMounts: []corev1.VolumeMount{{Name: "shared", MountPath: "/shared"}},
+				},
+				{
+					Name:  "sidecar",
+					Image: "sidecar:1.0.0",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "shared", MountPath: "/shared"},
+						{Name: "sidecar-config", MountPath: "/config"},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{Name: "shared"},
+				{Name: "sidecar-config"},
+			},
+			TerminationGracePeriodSeconds: &grace,
+		},
+	}
+
+	cd := &flaggerv1.Canary{
+		Spec: flaggerv1.CanarySpec{
+			IgnoreChanges: &flaggerv1.CanaryIgnoreChanges{
+				Paths:       []string{".spec.terminationGracePeriodSeconds"},
+				Annotations: []string{"kubectl.kubernetes.io/restartedAt"},
+				Labels:      []string{"injected"},
+				Containers:  []string{"sidecar"},
+			},
+		},
+	}
+
+	spec, err := ignoreChanges(cd, template)
+	require.NoError(t, err)
+	assert.Nil(t, spec.Annotations)
+	assert.Equal(t, map[string]string{"app": "podinfo"}, spec.Labels)
+	require.Len(t, spec.Spec.Containers, 1)
+	assert.Equal(t, "podinfo", spec.Spec.Containers[0].Name)
+	require.Len(t, spec.Spec.Volumes, 1)
+	assert.Equal(t, "shared", spec.Spec.Volumes[0].Name)
+	assert.Nil(t, spec.Spec.TerminationGracePeriodSeconds)
+
+	// the template is left untouched
+	assert.Len(t, template.Spec.Containers, 2)
+	assert.Len(t, template.Annotations, 1)
+
+	// changes to the ignored fields have the same hash
+	changed := *template.DeepCopy()
+	changed.Annotations["kubectl.kubernetes.io/restartedAt"] = "2026-10-18T00:00:00Z"
+	changed.Spec.Containers[1].Image = "sidecar:1.1.0"
+	changed.Spec.TerminationGracePeriodSeconds = &changedGrace
+	changedSpec, err := ignoreChanges(cd, changed)
+	require.NoError(t, err)
+	assert.Equal(t, ComputeHash(spec), ComputeHash(changedSpec))
+
+	changed.Spec.Containers[0].Image = "quay.io/stefanprodan/podinfo:1.2.1"
+	changedSpec, err = ignoreChanges(cd, changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, ComputeHash(spec), ComputeHash(changedSpec))
+
+	// without rules the template hash is unchanged
+	spec, err = ignoreChanges(&flaggerv1.Canary{}, template)
+	require.NoError(t, err)
+	assert.Equal(t, ComputeHash(template), ComputeHash(spec))
+}
+
+func TestDeploymentController_PromoteIgnoredChanges(t *testing.T) {
+	dc := deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"}
+	mocks := newDeploymentFixture(dc)
+	mocks.canary.Spec.IgnoreChanges = &flaggerv1.CanaryIgnoreChanges{
+		Annotations: []string{"kubectl.kubernetes.io/restartedAt"},
+	}
+	mocks.initializeCanary(t)
+
+	err := mocks.controller.SyncStatus(mocks.canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseInitialized})
+	require.NoError(t, err)
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	cd.Spec.IgnoreChanges = mocks.canary.Spec.IgnoreChanges
+
+	// scale the target to zero and restart it
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	if dep.Spec.Template.Annotations == nil {
+		dep.Spec.Template.Annotations = make(map[string]string)
+	}
+	dep.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"] = "2026-10-17T00:00:00Z"
+	dep.Spec.Replicas = int32p(0)
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	isNew, err := mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	promoted, err := mocks.controller.PromoteIgnoredChanges(cd)
+	require.NoError(t, err)
+	assert.True(t, promoted)
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10-17T00:00:00Z", primary.Spec.Template.Annotations["kubectl.kubernetes.io/restartedAt"])
+	assert.NotEqual(t, int32(0), *primary.Spec.Replicas)
+
+	// the change is propagated once
+	promoted, err = mocks.controller.PromoteIgnoredChanges(cd)
+	require.NoError(t, err)
+	assert.False(t, promoted)
+
+	// other changes are left to the analysis
+	dep2 := newDeploymentControllerTestV2()
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	promoted, err = mocks.controller.PromoteIgnoredChanges(cd)
+	require.NoError(t, err)
+	assert.False(t, promoted)
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// MultiTargetController rolls out the canary target together with the additional targets,
+// the status and the routing metadata are managed by the target controller
+type MultiTargetController struct {
+	kubeClient    kubernetes.Interface
+	configTracker Tracker
+	target        Controller
+	factory       *Factory
+}
+
+// targetCanary returns a copy of the canary that references the additional target,
+// without autoscaler and revision history as these apply to the routed target only
+func targetCanary(cd *flaggerv1.Canary, ref flaggerv1.LocalObjectReference) *flaggerv1.Canary {
+	cdCopy := cd.DeepCopy()
+	cdCopy.Spec.TargetRef = ref
+	cdCopy.Spec.AdditionalTargetRefs = nil
+	cdCopy.Spec.AutoscalerRef = nil
+	cdCopy.Spec.RevisionHistoryLimit = new(int32)
+	return cdCopy
+}
+
+// targetRevisionKey returns the key of an additional target in the canary status
+func targetRevisionKey(ref flaggerv1.LocalObjectReference) string {
+	return fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+}
+
+// forEachTarget calls fn for the target and then for each additional target
+func (c *MultiTargetController) forEachTarget(cd *flaggerv1.Canary, fn func(ctrl Controller, cd *flaggerv1.Canary) error) error {
+	if err := fn(c.target, cd); err != nil {
+		return err
+	}
+	for _, ref := range cd.Spec.AdditionalTargetRefs {
+		if err := fn(c.factory.Controller(ref), targetCanary(cd, ref)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isReady returns the first readiness error of the target and additional targets
+func (c *MultiTargetController) isReady(cd *flaggerv1.Canary, check func(ctrl Controller, cd *flaggerv1.Canary) (bool, error)) (bool, error) {
+	retriable := true
+	err := c.forEachTarget(cd, func(ctrl Controller, cd *flaggerv1.Canary) error {
+		var err error
+		retriable, err = check(ctrl, cd)
+		return err
+	})
+	return retriable, err
+}
+
+func (c *MultiTargetController) IsPrimaryReady(cd *flaggerv1.Canary) (bool, error) {
+	return c.isReady(cd, func(ctrl Controller, cd *flaggerv1.Canary) (bool, error) {
+		return ctrl.IsPrimaryReady(cd)
+	})
+}
+
+func (c *MultiTargetController) IsCanaryReady(cd *flaggerv1.Canary) (bool, error) {
+	return c.isReady(cd, func(ctrl Controller, cd *flaggerv1.Canary) (bool, error) {
+		return ctrl.IsCanaryReady(cd)
+	})
+}
+
+// GetMetadata returns the routing metadata of the target
+func (c *MultiTargetController) GetMetadata(cd *flaggerv1.Canary) (string, string, map[string]int32, error) {
+	return c.target.GetMetadata(cd)
+}
+
+// SyncStatus records the additional targets revisions along with the target status
+func (c *MultiTargetController) SyncStatus(cd *flaggerv1.Canary, status flaggerv1.CanaryStatus) error {
+	revisions, err := c.getTargetRevisions(cd)
+	if err != nil {
+		return err
+	}
+	status.TargetRevisions = revisions
+	return c.target.SyncStatus(cd, status)
+}
+
+func (c *MultiTargetController) SetStatusFailedChecks(cd *flaggerv1.Canary, val int) error {
+	return c.target.SetStatusFailedChecks(cd, val)
+}
+
+func (c *MultiTargetController) SetStatusWeight(cd *flaggerv1.Canary, val int) error {
+	return c.target.SetStatusWeight(cd, val)
+}
+
+func (c *MultiTargetController) SetStatusIterations(cd *flaggerv1.Canary, val int) error {
+	return c.target.SetStatusIterations(cd, val)
+}
+
+func (c *MultiTargetController) SetStatusPhase(cd *flaggerv1.Canary, phase flaggerv1.CanaryPhase) error {
+	return c.target.SetStatusPhase(cd, phase)
+}
+
+// Initialize creates the primary workloads of the target and additional targets,
+// all the targets are initialized before returning the first error
+func (c *MultiTargetController) Initialize(cd *flaggerv1.Canary) (bool, error) {
+	retriable := true
+	var initErr error
+	c.forEachTarget(cd, func(ctrl Controller, cd *flaggerv1.Canary) error {
+		if ok, err := ctrl.Initialize(cd); err != nil && initErr == nil {
+			retriable, initErr = ok, err
+		}
+		return nil
+	})
+	return retriable, initErr
+}
+
+// Promote copies the target and additional targets specs to their primaries, the additional
+// targets are promoted first so that the routed target is updated last. All the targets must be
+// ready before the promotion starts, and if a target fails to be promoted the primaries
+// promoted before it are restored to their previous pod template.
+func (c *MultiTargetController) Promote(cd *flaggerv1.Canary) error {
+	if _, err := c.IsCanaryReady(cd); err != nil {
+		return fmt.Errorf("promotion halted: %w", err)
+	}
+
+	var promoted []flaggerv1.LocalObjectReference
+	var templates []corev1.PodTemplateSpec
+	revert := func(err error) error {
+		for i, ref := range promoted {
+			if revertErr := c.setTargetTemplate(primaryTargetRef(ref), cd.Namespace, templates[i]); revertErr != nil {
+				return fmt.Errorf("%w, reverting %s %s-primary.%s failed: %v", err, ref.Kind, ref.Name, cd.Namespace, revertErr)
+			}
+		}
+		return err
+	}
+
+	for _, ref := range cd.Spec.AdditionalTargetRefs {
+		template, err := c.getTargetTemplate(primaryTargetRef(ref), cd.Namespace)
+		if err != nil {
+			return revert(err)
+		}
+		if err := c.factory.Controller(ref).Promote(targetCanary(cd, ref)); err != nil {
+			return revert(fmt.Errorf("promoting %s %s.%s failed: %w", ref.Kind, ref.Name, cd.Namespace, err))
+		}
+		promoted = append(promoted, ref)
+		templates = append(templates, template)
+	}
+	if err := c.target.Promote(cd); err != nil {
+		return revert(err)
+	}
+	return nil
+}
+
+// HasTargetChanged returns true if the target or any of the additional targets has changed
+func (c *MultiTargetController) HasTargetChanged(cd *flaggerv1.Canary) (bool, error) {
+	if changed, err := c.target.HasTargetChanged(cd); err != nil || changed {
+		return changed, err
+	}
+
+	revisions, err := c.getTargetRevisions(cd)
+	if err != nil {
+		return false, err
+	}
+	if len(revisions) != len(cd.Status.TargetRevisions) {
+		return true, nil
+	}
+	for key, revision := range revisions {
+		if cd.Status.TargetRevisions[key] != revision {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HaveDependenciesChanged returns true if the target configs have changed,
+// the additional targets configs are part of their revisions
+func (c *MultiTargetController) HaveDependenciesChanged(cd *flaggerv1.Canary) (bool, error) {
+	return c.target.HaveDependenciesChanged(cd)
+}
+
+func (c *MultiTargetController) ScaleToZero(cd *flaggerv1.Canary) error {
+	return c.forEachTarget(cd, func(ctrl Controller, cd *flaggerv1.Canary) error {
+		return ctrl.ScaleToZero(cd)
+	})
+}
+
+func (c *MultiTargetController) ScaleFromZero(cd *flaggerv1.Canary) error {
+	return c.forEachTarget(cd, func(ctrl Controller, cd *flaggerv1.Canary) error {
+		return ctrl.ScaleFromZero(cd)
+	})
+}
+
+func (c *MultiTargetController) Finalize(cd *flaggerv1.Canary) error {
+	return c.forEachTarget(cd, func(ctrl Controller, cd *flaggerv1.Canary) error {
+		return ctrl.Finalize(cd)
+	})
+}
+
+// getTargetRevisions returns the hash of the pod template and configs of each additional target
+func (c *MultiTargetController) getTargetRevisions(cd *flaggerv1.Canary) (map[string]string, error) {
+	revisions := make(map[string]string, len(cd.Spec.AdditionalTargetRefs))
+	for _, ref := range cd.Spec.AdditionalTargetRefs {
+		template, err := c.getTargetTemplate(ref, cd.Namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		configs, err := c.configTracker.GetTargetConfigs(targetCanary(cd, ref))
+		if err != nil {
+			return nil, fmt.Errorf("GetTargetConfigs failed: %w", err)
+		}
+		checksums := make(map[string]string, len(configs))
+		for _, cfg := range configs {
+			checksums[cfg.GetName()] = cfg.Checksum
+		}
+
+		revisions[targetRevisionKey(ref)] = ComputeHash([]interface{}{template, checksums})
+	}
+	return revisions, nil
+}
+
+// primaryTargetRef returns the reference of the primary workload of a target
+func primaryTargetRef(ref flaggerv1.LocalObjectReference) flaggerv1.LocalObjectReference {
+	ref.Name = fmt.Sprintf("%s-primary", ref.Name)
+	return ref
+}
+
+// setTargetTemplate replaces the pod template of the workload
+func (c *MultiTargetController) setTargetTemplate(ref flaggerv1.LocalObjectReference, namespace string, template corev1.PodTemplateSpec) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		switch ref.Kind {
+		case "DaemonSet":
+			ds, err := c.kubeClient.AppsV1().DaemonSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("daemonset %s.%s get query error: %w", ref.Name, namespace, err)
+			}
+			ds.Spec.Template = template
+			_, err = c.kubeClient.AppsV1().DaemonSets(namespace).Update(context.TODO(), ds, metav1.UpdateOptions{})
+			return err
+		case "StatefulSet":
+			sts, err := c.kubeClient.AppsV1().StatefulSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("statefulset %s.%s get query error: %w", ref.Name, namespace, err)
+			}
+			sts.Spec.Template = template
+			_, err = c.kubeClient.AppsV1().StatefulSets(namespace).Update(context.TODO(), sts, metav1.UpdateOptions{})
+			return err
+		default:
+			dep, err := c.kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("deployment %s.%s get query error: %w", ref.Name, namespace, err)
+			}
+			dep.Spec.Template = template
+			_, err = c.kubeClient.AppsV1().Deployments(namespace).Update(context.TODO(), dep, metav1.UpdateOptions{})
+			return err
+		}
+	})
+}
+
+func (c *MultiTargetController) getTargetTemplate(ref flaggerv1.LocalObjectReference, namespace string) (corev1.PodTemplateSpec, error) {
+	switch ref.Kind {
+	case "DaemonSet":
+		ds, err := c.kubeClient.AppsV1().DaemonSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, fmt.Errorf("daemonset %s.%s get query error: %w", ref.Name, namespace, err)
+		}
+		// ignore the node selector used to scale the daemonset to zero
+		for key := range daemonSetScaleDownNodeSelector {
+			delete(ds.Spec.Template.Spec.NodeSelector, key)
+		}
+		return ds.Spec.Template, nil
+	case "StatefulSet":
+		sts, err := c.kubeClient.AppsV1().StatefulSets(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, fmt.Errorf("statefulset %s.%s get query error: %w", ref.Name, namespace, err)
+		}
+		return sts.Spec.Template, nil
+	default:
+		dep, err := c.kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return corev1.PodTemplateSpec{}, fmt.Errorf("deployment %s.%s get query error: %w", ref.Name, namespace, err)
+		}
+		return dep.Spec.Template, nil
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestMultiTargetController(t *testing.T) {
+	mocks, ctrl := newMultiTargetFixture(t)
+	cd := mocks.canary
+
+	changed, err := ctrl.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// a change of the additional target is a new revision
+	worker, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	worker.Spec.Template.Spec.Containers[0].Image = "worker:2.0.0"
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), worker, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	changed, err = ctrl.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// promote all the targets
+	require.NoError(t, ctrl.Promote(cd))
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "worker-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "worker:2.0.0", primary.Spec.Template.Spec.Containers[0].Image)
+
+	// the revision history holds the routed target only
+	revisions, err := getPromotedRevisions(mocks.kubeClient, cd)
+	require.NoError(t, err)
+	assert.Len(t, revisions, 2)
+
+	// scale all the targets to zero
+	require.NoError(t, ctrl.ScaleToZero(cd))
+	for _, name := range []string{"podinfo", "worker"} {
+		dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), *dep.Spec.Replicas)
+	}
+}
+
+func TestMultiTargetController_PromoteReverts(t *testing.T) {
+	mocks, ctrl := newMultiTargetFixture(t)
+	cd := mocks.canary
+
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "worker-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	image := primary.Spec.Template.Spec.Containers[0].Image
+
+	// the promotion doesn't start until all the targets are ready
+	worker, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "worker", metav1.GetOptions{})
+	require.NoError(t, err)
+	worker.Spec.Template.Spec.Containers[0].Image = "worker:2.0.0"
+	worker.Spec.Replicas = int32p(1)
+	worker.Status = appsv1.DeploymentStatus{Replicas: 1}
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), worker, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.Error(t, ctrl.Promote(cd))
+	primary, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "worker-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, image, primary.Spec.Template.Spec.Containers[0].Image)
+
+	// the promoted additional target is reverted when the routed target fails to be promoted
+	worker.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1}
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), worker, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, mocks.kubeClient.AppsV1().Deployments("default").Delete(context.TODO(), "podinfo-primary", metav1.DeleteOptions{}))
+
+	require.Error(t, ctrl.Promote(cd))
+	primary, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "worker-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, image, primary.Spec.Template.Spec.Containers[0].Image)
+}
+
+// newMultiTargetFixture returns an initialized canary with a worker deployment as additional target
+func newMultiTargetFixture(t *testing.T) (deploymentControllerFixture, Controller) {
+	mocks := newDeploymentFixture(deploymentConfigs{name: "podinfo", label: "name", labelValue: "podinfo"})
+	worker := newDeploymentControllerTest(deploymentConfigs{name: "worker", label: "name", labelValue: "worker"})
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Create(context.TODO(), worker, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	mocks.canary.Spec.AdditionalTargetRefs = []flaggerv1.LocalObjectReference{
+		{APIVersion: "apps/v1", Kind: "Deployment", Name: "worker"},
+	}
+	factory := NewFactory(mocks.kubeClient, mocks.flaggerClient, nil, nil, nil, mocks.controller.configTracker,
+		mocks.controller.labels, mocks.controller.includeLabelPrefix, mocks.logger)
+	ctrl := factory.CanaryController(mocks.canary)
+	require.IsType(t, &MultiTargetController{}, ctrl)
+
+	// create the primaries and wait for them to be ready
+	_, err = ctrl.Initialize(mocks.canary)
+	require.Error(t, err)
+	for _, name := range []string{"podinfo-primary", "worker-primary"} {
+		p, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		p.Status = appsv1.DeploymentStatus{Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1, AvailableReplicas: 1}
+		_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), p, metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+	_, err = ctrl.Initialize(mocks.canary)
+	require.NoError(t, err)
+
+	// record the additional target revision
+	require.NoError(t, ctrl.SyncStatus(mocks.canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseInitialized}))
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	cd.Spec.AdditionalTargetRefs = mocks.canary.Spec.AdditionalTargetRefs
+	assert.Contains(t, cd.Status.TargetRevisions, "Deployment/worker")
+	mocks.canary = cd
+
+	return mocks, ctrl
+}
@@ -0,0 +1,396 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+const revisionKeyPrefix = "revision-"
+
+// promotedRevision is a primary pod template applied by a promotion or a rollback
+type promotedRevision struct {
+	// Revision is the sequence number of the promotion
+	Revision int `json:"revision"`
+	// Spec is the hash of the target spec the template was promoted from
+	Spec string `json:"spec"`
+	// TrackedConfigs are the checksums of the target config maps and secrets
+	TrackedConfigs map[string]string `json:"trackedConfigs,omitempty"`
+	// Template is the primary pod template
+	Template corev1.PodTemplateSpec `json:"template"`
+	// TargetTemplate is the target pod template the primary template was promoted from
+	TargetTemplate *corev1.PodTemplateSpec `json:"targetTemplate,omitempty"`
+	// PromotedAt is the time the template was applied to the primary
+	PromotedAt metav1.Time `json:"promotedAt"`
+}
+
+func getRevisionConfigMapName(cd *flaggerv1.Canary) string {
+	return fmt.Sprintf("%s-revisions", cd.Name)
+}
+
+// getPromotedRevisions returns the revisions stored in the config map owned by the canary sorted by number
+func getPromotedRevisions(kubeClient kubernetes.Interface, cd *flaggerv1.Canary) ([]promotedRevision, error) {
+	name := getRevisionConfigMapName(cd)
+	cm, err := kubeClient.CoreV1().ConfigMaps(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("configmap %s.%s get query error: %w", name, cd.Namespace, err)
+	}
+
+	return decodeRevisions(cm)
+}
+
+func decodeRevisions(cm *corev1.ConfigMap) ([]promotedRevision, error) {
+	var revisions []promotedRevision
+	for key, data := range cm.Data {
+		if !strings.HasPrefix(key, revisionKeyPrefix) {
+			continue
+		}
+		var revision promotedRevision
+		if err := json.Unmarshal([]byte(data), &revision); err != nil {
+			return nil, fmt.Errorf("configmap %s.%s %s unmarshal failed: %w", cm.Name, cm.Namespace, key, err)
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+	return revisions, nil
+}
+
+// recordPromotedRevision appends the primary template to the revision history and removes the
+// revisions above the history limit, the current primary template is recorded first if the
+// history is empty so that the primary can be rolled back after the first promotion
+func recordPromotedRevision(kubeClient kubernetes.Interface, cd *flaggerv1.Canary,
+	current corev1.PodTemplateSpec, promoted promotedRevision) error {
+	return updateRevisions(kubeClient, cd, func(revisions []promotedRevision) []promotedRevision {
+		if len(revisions) == 0 {
+			revisions = append(revisions, promotedRevision{
+				Revision:   1,
+				Spec:       cd.Status.LastPromotedSpec,
+				Template:   current,
+				PromotedAt: metav1.Now(),
+			})
+		}
+		promoted.Revision = revisions[len(revisions)-1].Revision + 1
+		promoted.PromotedAt = metav1.Now()
+		return append(revisions, promoted)
+	})
+}
+
+// recordInitialRevision records the primary template created from the target
+// as the first revision, it's a no-op if the history isn't empty
+func recordInitialRevision(kubeClient kubernetes.Interface, tracker Tracker, cd *flaggerv1.Canary,
+	primary corev1.PodTemplateSpec, target corev1.PodTemplateSpec) error {
+	spec, err := ignoreChanges(cd, target)
+	if err != nil {
+		return err
+	}
+	configs, err := tracker.GetConfigRefs(cd)
+	if err != nil {
+		return fmt.Errorf("GetConfigRefs failed: %w", err)
+	}
+	var trackedConfigs map[string]string
+	if configs != nil {
+		trackedConfigs = *configs
+	}
+	return updateRevisions(kubeClient, cd, func(revisions []promotedRevision) []promotedRevision {
+		if len(revisions) > 0 {
+			return revisions
+		}
+		return append(revisions, promotedRevision{
+			Revision:       1,
+			Spec:           ComputeHash(spec),
+			TrackedConfigs: trackedConfigs,
+			Template:       primary,
+			TargetTemplate: &target,
+			PromotedAt:     metav1.Now(),
+		})
+	})
+}
+
+// updateRevisions stores the revisions returned by the update func in the config map
+// owned by the canary and removes the revisions above the history limit
+func updateRevisions(kubeClient kubernetes.Interface, cd *flaggerv1.Canary,
+	update func(revisions []promotedRevision) []promotedRevision) error {
+	limit := cd.GetRevisionHistoryLimit()
+	if limit == 0 {
+		return nil
+	}
+
+	name := getRevisionConfigMapName(cd)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cm, err := kubeClient.CoreV1().ConfigMaps(cd.Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		exists := true
+		if errors.IsNotFound(err) {
+			exists = false
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: cd.Namespace,
+					OwnerReferences: []metav1.OwnerReference{
+						*metav1.NewControllerRef(cd, schema.GroupVersionKind{
+							Group:   flaggerv1.SchemeGroupVersion.Group,
+							Version: flaggerv1.SchemeGroupVersion.Version,
+							Kind:    flaggerv1.CanaryKind,
+						}),
+					},
+				},
+			}
+		} else if err != nil {
+			return fmt.Errorf("configmap %s.%s get query error: %w", name, cd.Namespace, err)
+		}
+
+		revisions, err := decodeRevisions(cm)
+		if err != nil {
+			return err
+		}
+		count := len(revisions)
+		revisions = update(revisions)
+		if exists && len(revisions) == count {
+			return nil
+		}
+		if len(revisions) > limit {
+			revisions = revisions[len(revisions)-limit:]
+		}
+
+		cmCopy := cm.DeepCopy()
+		cmCopy.Data = make(map[string]string, len(revisions))
+		for _, revision := range revisions {
+			data, err := json.Marshal(revision)
+			if err != nil {
+				return fmt.Errorf("revision %d marshal failed: %w", revision.Revision, err)
+			}
+			cmCopy.Data[revisionKeyPrefix+strconv.Itoa(revision.Revision)] = string(data)
+		}
+
+		if !exists {
+			_, err = kubeClient.CoreV1().ConfigMaps(cd.Namespace).Create(context.TODO(), cmCopy, metav1.CreateOptions{})
+			if err != nil {
+				return fmt.Errorf("configmap %s.%s create error: %w", name, cd.Namespace, err)
+			}
+			return nil
+		}
+		_, err = kubeClient.CoreV1().ConfigMaps(cd.Namespace).Update(context.TODO(), cmCopy, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// getRevision returns the stored revision by number or the one
+// before the current promotion if the number is zero
+func getRevision(kubeClient kubernetes.Interface, cd *flaggerv1.Canary, number int) (*promotedRevision, error) {
+	revisions, err := getPromotedRevisions(kubeClient, cd)
+	if err != nil {
+		return nil, err
+	}
+
+	if number == 0 {
+		if len(revisions) < 2 {
+			return nil, fmt.Errorf("canary %s.%s has no previous revision: %w", cd.Name, cd.Namespace, ErrRevisionNotFound)
+		}
+		return &revisions[len(revisions)-2], nil
+	}
+
+	for i := range revisions {
+		if revisions[i].Revision == number {
+			return &revisions[i], nil
+		}
+	}
+	return nil, fmt.Errorf("canary %s.%s revision %d: %w", cd.Name, cd.Namespace, number, ErrRevisionNotFound)
+}
+
+// rollbackPrimary applies the template of the revision to the primary, records it as the latest revision
+// and sets the canary last promoted spec and configs to avoid starting an analysis if the target is rolled back too
+func rollbackPrimary(kubeClient kubernetes.Interface, flaggerClient clientset.Interface, cd *flaggerv1.Canary,
+	number int, apply func(template corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error)) error {
+	revision, err := getRevision(kubeClient, cd, number)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyPrimaryConfigs(kubeClient, cd, revision); err != nil {
+		return err
+	}
+
+	current, err := apply(revision.Template)
+	if err != nil {
+		return err
+	}
+
+	if err := recordPromotedRevision(kubeClient, cd, current, promotedRevision{
+		Spec:           revision.Spec,
+		TrackedConfigs: revision.TrackedConfigs,
+		Template:       revision.Template,
+		TargetTemplate: revision.TargetTemplate,
+	}); err != nil {
+		return fmt.Errorf("recordPromotedRevision failed: %w", err)
+	}
+
+	if revision.TrackedConfigs == nil {
+		return setStatusLastPromotedSpec(flaggerClient, cd, revision.Spec)
+	}
+	return setStatusReverted(flaggerClient, cd, revision.Spec, &revision.TrackedConfigs)
+}
+
+// verifyPrimaryConfigs returns an error if the data of the primary configs differs from the configs
+// recorded with the revision, the primary configs are shared by all the revisions and are not restored
+// with the template, the revision would run with the configs promoted after it
+func verifyPrimaryConfigs(kubeClient kubernetes.Interface, cd *flaggerv1.Canary, revision *promotedRevision) error {
+	for name, recorded := range revision.TrackedConfigs {
+		refType, refName, _ := strings.Cut(name, "/")
+		primaryName := fmt.Sprintf("%s-primary", refName)
+
+		var data interface{}
+		switch ConfigRefType(refType) {
+		case ConfigRefMap:
+			config, err := kubeClient.CoreV1().ConfigMaps(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("configmap %s.%s get query error: %w", primaryName, cd.Namespace, err)
+			}
+			data = config.Data
+		case ConfigRefSecret:
+			secret, err := kubeClient.CoreV1().Secrets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("secret %s.%s get query error: %w", primaryName, cd.Namespace, err)
+			}
+			data = secret.Data
+		default:
+			continue
+		}
+
+		if checksum(data) != recorded {
+			return fmt.Errorf("%s %s.%s differs from revision %d: %w",
+				refType, primaryName, cd.Namespace, revision.Revision, ErrConfigChanged)
+		}
+	}
+	return nil
+}
+
+func trackedConfigsOf(cd *flaggerv1.Canary) map[string]string {
+	if cd.Status.TrackedConfigs == nil {
+		return nil
+	}
+	return *cd.Status.TrackedConfigs
+}
+
+// revertTarget applies the target template of the last promoted revision to the target and marks the
+// reverted template and its configs as promoted to avoid starting an analysis for the revert
+func revertTarget(kubeClient kubernetes.Interface, flaggerClient clientset.Interface, tracker Tracker, cd *flaggerv1.Canary,
+	apply func(template corev1.PodTemplateSpec, rejected string) (corev1.PodTemplateSpec, error)) error {
+	revisions, err := getPromotedRevisions(kubeClient, cd)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 || revisions[len(revisions)-1].TargetTemplate == nil {
+		return fmt.Errorf("canary %s.%s has no promoted target template: %w", cd.Name, cd.Namespace, ErrRevisionNotFound)
+	}
+
+	revision := revisions[len(revisions)-1]
+	reverted, err := apply(*revision.TargetTemplate, cd.Status.LastAppliedSpec)
+	if err != nil {
+		return err
+	}
+
+	spec, err := ignoreChanges(cd, reverted)
+	if err != nil {
+		return err
+	}
+
+	// the target configs aren't reverted, tracking the configs of the promoted revision starts an
+	// analysis of the changed configs with the reverted template, unless the failed analysis
+	// already ran them with the promoted template
+	configs := &revision.TrackedConfigs
+	if revision.TrackedConfigs == nil || cd.Status.LastAppliedSpec == cd.Status.LastPromotedSpec {
+		configs, err = tracker.GetConfigRefs(cd)
+		if err != nil {
+			return fmt.Errorf("GetConfigRefs failed: %w", err)
+		}
+	}
+
+	return setStatusReverted(flaggerClient, cd, ComputeHash(spec), configs)
+}
+
+// hasIgnoredChanges returns true if the target pod template differs from the target template of the
+// last promoted revision only in the fields excluded by the canary ignore rules, the templates are
+// compared in the form returned by the spec func
+func hasIgnoredChanges(kubeClient kubernetes.Interface, cd *flaggerv1.Canary, target corev1.PodTemplateSpec,
+	spec func(template corev1.PodTemplateSpec) corev1.PodTemplateSpec) (bool, error) {
+	if cd.Spec.IgnoreChanges == nil {
+		return false, nil
+	}
+
+	revisions, err := getPromotedRevisions(kubeClient, cd)
+	if err != nil {
+		return false, err
+	}
+	if len(revisions) == 0 || revisions[len(revisions)-1].TargetTemplate == nil {
+		return false, nil
+	}
+
+	decoded, err := decodeTemplate(target)
+	if err != nil {
+		return false, err
+	}
+
+	current, promoted := spec(decoded), spec(*revisions[len(revisions)-1].TargetTemplate)
+	if ComputeHash(current) == ComputeHash(promoted) {
+		return false, nil
+	}
+
+	currentSpec, err := ignoreChanges(cd, current)
+	if err != nil {
+		return false, err
+	}
+	promotedSpec, err := ignoreChanges(cd, promoted)
+	if err != nil {
+		return false, err
+	}
+	return ComputeHash(currentSpec) == ComputeHash(promotedSpec), nil
+}
+
+// decodeTemplate round-trips the pod template through JSON, the revision
+// templates are stored as JSON and the target is decoded the same way to compare the hashes
+func decodeTemplate(template corev1.PodTemplateSpec) (corev1.PodTemplateSpec, error) {
+	var decoded corev1.PodTemplateSpec
+	data, err := json.Marshal(template)
+	if err != nil {
+		return decoded, err
+	}
+	err = json.Unmarshal(data, &decoded)
+	return decoded, err
+}
+
+// podTemplate returns the pod template unchanged
+func podTemplate(template corev1.PodTemplateSpec) corev1.PodTemplateSpec {
+	return template
+}
@@ -21,6 +21,7 @@ import (
 	"hash/fnv"
 
 	"github.com/davecgh/go-spew/spew"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -48,6 +49,16 @@ func hasSpecChanged(cd *flaggerv1.Canary, spec interface{}) (bool, error) {
 	return false, nil
 }
 
+// hasTemplateChanged removes the fields excluded by the canary ignore rules
+// from the pod template and checks if the spec has changed
+func hasTemplateChanged(cd *flaggerv1.Canary, template corev1.PodTemplateSpec) (bool, error) {
+	spec, err := ignoreChanges(cd, template)
+	if err != nil {
+		return false, err
+	}
+	return hasSpecChanged(cd, spec)
+}
+
 // ComputeHash returns a hash value calculated from a spec using the spew library
 // which follows pointers and prints actual values of the nested objects
 // ensuring the hash does not change when a pointer changes.
@@ -0,0 +1,508 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
+)
+
+// StatefulSetController is managing the operations for Kubernetes StatefulSet kind
+type StatefulSetController struct {
+	kubeClient         kubernetes.Interface
+	flaggerClient      clientset.Interface
+	logger             *zap.SugaredLogger
+	configTracker      Tracker
+	labels             []string
+	includeLabelPrefix []string
+}
+
+// Initialize creates the primary statefulset if it does not exist.
+func (c *StatefulSetController) Initialize(cd *flaggerv1.Canary) (bool, error) {
+	if err := c.createPrimaryStatefulSet(cd, c.includeLabelPrefix); err != nil {
+		return true, fmt.Errorf("createPrimaryStatefulSet failed: %w", err)
+	}
+
+	if cd.Status.Phase == "" || cd.Status.Phase == flaggerv1.CanaryPhaseInitializing {
+		if !cd.SkipAnalysis() {
+			if retriable, err := c.IsPrimaryReady(cd); err != nil {
+				return retriable, fmt.Errorf("%w", err)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// Promote copies the pod spec, secrets and config maps from canary to primary,
+// the volume claim templates are immutable and are left untouched
+func (c *StatefulSetController) Promote(cd *flaggerv1.Canary) error {
+	return c.promote(cd, true)
+}
+
+// PromoteIgnoredChanges copies the pod spec, secrets and config maps from canary to primary
+// if the canary pod spec differs from the last promoted one only in the ignored fields
+func (c *StatefulSetController) PromoteIgnoredChanges(cd *flaggerv1.Canary) (bool, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	ok, err := hasIgnoredChanges(c.kubeClient, cd, canary.Spec.Template, podTemplate)
+	if err != nil || !ok {
+		return false, err
+	}
+	// the canary is scaled to zero, keep the primary replicas
+	return true, c.promote(cd, false)
+}
+
+// ClassifyChange returns the class of the statefulset changes since the last promoted revision
+func (c *StatefulSetController) ClassifyChange(cd *flaggerv1.Canary) (flaggerv1.ChangeClass, string, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return flaggerv1.ChangeOther, "", fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	return classifyChange(c.kubeClient, c.configTracker, cd, canary.Spec.Template, podTemplate)
+}
+
+func (c *StatefulSetController) promote(cd *flaggerv1.Canary, copyReplicas bool) error {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+
+	var current, promoted, target corev1.PodTemplateSpec
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		canary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+		}
+
+		label, labelValue, err := c.getSelectorLabel(canary)
+		primaryLabelValue := fmt.Sprintf("%s-primary", labelValue)
+		if err != nil {
+			return fmt.Errorf("getSelectorLabel failed: %w", err)
+		}
+
+		primary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("statefulset %s.%s get query error: %w", primaryName, cd.Namespace, err)
+		}
+
+		if err := checkVolumeClaimTemplates(canary, primary); err != nil {
+			return err
+		}
+
+		// keep a copy of the target template, the primary configs are applied in place
+		target = *canary.Spec.Template.DeepCopy()
+
+		// promote secrets and config maps
+		configRefs, err := c.configTracker.GetTargetConfigs(cd)
+		if err != nil {
+			return fmt.Errorf("GetTargetConfigs failed: %w", err)
+		}
+		if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
+			return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
+		}
+
+		primaryCopy := primary.DeepCopy()
+		primaryCopy.Spec.MinReadySeconds = canary.Spec.MinReadySeconds
+		primaryCopy.Spec.RevisionHistoryLimit = canary.Spec.RevisionHistoryLimit
+		primaryCopy.Spec.UpdateStrategy = primaryUpdateStrategy(canary.Spec.UpdateStrategy)
+		primaryCopy.Spec.PersistentVolumeClaimRetentionPolicy = canary.Spec.PersistentVolumeClaimRetentionPolicy
+		// update replica if hpa isn't set
+		if cd.Spec.AutoscalerRef == nil && copyReplicas {
+			primaryCopy.Spec.Replicas = canary.Spec.Replicas
+		}
+
+		// update spec with primary secrets and config maps
+		primaryCopy.Spec.Template.Spec = c.configTracker.ApplyPrimaryConfigs(canary.Spec.Template.Spec, configRefs)
+
+		// update pod annotations to ensure a rolling update
+		podAnnotations, err := makeAnnotations(canary.Spec.Template.Annotations)
+		if err != nil {
+			return fmt.Errorf("makeAnnotations for podAnnotations failed: %w", err)
+		}
+
+		primaryCopy.Spec.Template.Annotations = podAnnotations
+		primaryCopy.Spec.Template.Labels = makePrimaryLabels(canary.Spec.Template.Labels, primaryLabelValue, label)
+
+		// update sts annotations
+		primaryCopy.ObjectMeta.Annotations = make(map[string]string)
+		filteredAnnotations := includeLabelsByPrefix(canary.ObjectMeta.Annotations, c.includeLabelPrefix)
+		for k, v := range filteredAnnotations {
+			primaryCopy.ObjectMeta.Annotations[k] = v
+		}
+		// update sts labels
+		filteredLabels := includeLabelsByPrefix(canary.ObjectMeta.Labels, c.includeLabelPrefix)
+		primaryCopy.ObjectMeta.Labels = makePrimaryLabels(filteredLabels, primaryLabelValue, label)
+
+		// apply update
+		_, err = c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Update(context.TODO(), primaryCopy, metav1.UpdateOptions{})
+		current, promoted = primary.Spec.Template, primaryCopy.Spec.Template
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("updating statefulset %s.%s template spec failed: %w",
+			primaryName, cd.Namespace, err)
+	}
+
+	// keep the promoted template for rollback
+	if err := recordPromotedRevision(c.kubeClient, cd, current, promotedRevision{
+		Spec:           cd.Status.LastAppliedSpec,
+		TrackedConfigs: trackedConfigsOf(cd),
+		Template:       promoted,
+		TargetTemplate: &target,
+	}); err != nil {
+		return fmt.Errorf("recordPromotedRevision failed: %w", err)
+	}
+
+	return nil
+}
+
+// HasTargetChanged returns true if the canary statefulset pod spec has changed
+func (c *StatefulSetController) HasTargetChanged(cd *flaggerv1.Canary) (bool, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	return hasTemplateChanged(cd, canary.Spec.Template)
+}
+
+// ScaleToZero sets the canary statefulset replicas to zero,
+// the persistent volume claims of the canary pods are kept
+func (c *StatefulSetController) ScaleToZero(cd *flaggerv1.Canary) error {
+	return c.scale(cd, 0)
+}
+
+// ScaleFromZero sets the canary statefulset replicas to the primary or autoscaler replicas
+func (c *StatefulSetController) ScaleFromZero(cd *flaggerv1.Canary) error {
+	targetName := cd.Spec.TargetRef.Name
+	sts, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	replicas := int32p(1)
+	if sts.Spec.Replicas != nil && *sts.Spec.Replicas > 0 {
+		replicas = sts.Spec.Replicas
+	} else if cd.Spec.AutoscalerRef == nil {
+		// If HPA isn't set and replicas are not specified, it uses the primary replicas when scaling up the canary
+		primaryName := fmt.Sprintf("%s-primary", targetName)
+		primary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("statefulset %s.%s get query error: %w", primaryName, cd.Namespace, err)
+		}
+
+		if primary.Spec.Replicas != nil && *primary.Spec.Replicas > 0 {
+			replicas = primary.Spec.Replicas
+		}
+	} else if minReplicas := getAutoscalerMinReplicas(c.kubeClient, c.flaggerClient, cd); minReplicas != nil {
+		replicas = minReplicas
+	}
+
+	return c.scale(cd, *replicas)
+}
+
+// GetMetadata returns the pod label selector and svc ports
+func (c *StatefulSetController) GetMetadata(cd *flaggerv1.Canary) (string, string, map[string]int32, error) {
+	targetName := cd.Spec.TargetRef.Name
+
+	canarySts, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return "", "", nil, fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	label, labelValue, err := c.getSelectorLabel(canarySts)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("getSelectorLabel failed: %w", err)
+	}
+
+	var ports map[string]int32
+	if cd.Spec.Service.PortDiscovery {
+		ports = getPorts(cd, canarySts.Spec.Template.Spec.Containers)
+	}
+
+	return label, labelValue, ports, nil
+}
+
+func (c *StatefulSetController) createPrimaryStatefulSet(cd *flaggerv1.Canary, includeLabelPrefix []string) error {
+	targetName := cd.Spec.TargetRef.Name
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+
+	canarySts, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	if canarySts.Spec.UpdateStrategy.Type != "" &&
+		canarySts.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType {
+		return fmt.Errorf("statefulset %s.%s must have RollingUpdate strategy but have %s",
+			targetName, cd.Namespace, canarySts.Spec.UpdateStrategy.Type)
+	}
+
+	// Create the labels map but filter unwanted labels
+	labels := includeLabelsByPrefix(canarySts.Labels, includeLabelPrefix)
+
+	label, labelValue, err := c.getSelectorLabel(canarySts)
+	primaryLabelValue := fmt.Sprintf("%s-primary", labelValue)
+	if err != nil {
+		return fmt.Errorf("getSelectorLabel failed: %w", err)
+	}
+
+	primarySts, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// create primary secrets and config maps
+		configRefs, err := c.configTracker.GetTargetConfigs(cd)
+		if err != nil {
+			return fmt.Errorf("GetTargetConfigs failed: %w", err)
+		}
+		if err := c.configTracker.CreatePrimaryConfigs(cd, configRefs, c.includeLabelPrefix); err != nil {
+			return fmt.Errorf("CreatePrimaryConfigs failed: %w", err)
+		}
+		// keep a copy of the target template, the primary configs are applied in place
+		target := canarySts.Spec.Template.DeepCopy()
+
+		annotations, err := makeAnnotations(canarySts.Spec.Template.Annotations)
+		if err != nil {
+			return fmt.Errorf("makeAnnotations failed: %w", err)
+		}
+
+		replicas := int32(1)
+		if canarySts.Spec.Replicas != nil && *canarySts.Spec.Replicas > 0 {
+			replicas = *canarySts.Spec.Replicas
+		}
+
+		// the primary pods are governed by their own headless service
+		var serviceName string
+		if canarySts.Spec.ServiceName != "" {
+			serviceName, err = c.createPrimaryService(cd, canarySts.Spec.ServiceName, label, primaryLabelValue)
+			if err != nil {
+				return fmt.Errorf("createPrimaryService failed: %w", err)
+			}
+		}
+
+		// create primary statefulset, the primary pods get their own persistent volume claims
+		// named after the primary statefulset from the canary volume claim templates
+		primarySts = &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        primaryName,
+				Namespace:   cd.Namespace,
+				Labels:      makePrimaryLabels(labels, primaryLabelValue, label),
+				Annotations: filterMetadata(canarySts.Annotations),
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(cd, schema.GroupVersionKind{
+						Group:   flaggerv1.SchemeGroupVersion.Group,
+						Version: flaggerv1.SchemeGroupVersion.Version,
+						Kind:    flaggerv1.CanaryKind,
+					}),
+				},
+			},
+			Spec: appsv1.StatefulSetSpec{
+				ServiceName:                          serviceName,
+				PodManagementPolicy:                  canarySts.Spec.PodManagementPolicy,
+				MinReadySeconds:                      canarySts.Spec.MinReadySeconds,
+				RevisionHistoryLimit:                 canarySts.Spec.RevisionHistoryLimit,
+				Replicas:                             int32p(replicas),
+				UpdateStrategy:                       primaryUpdateStrategy(canarySts.Spec.UpdateStrategy),
+				PersistentVolumeClaimRetentionPolicy: canarySts.Spec.PersistentVolumeClaimRetentionPolicy,
+				VolumeClaimTemplates:                 makeVolumeClaimTemplates(canarySts.Spec.VolumeClaimTemplates),
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						label: primaryLabelValue,
+					},
+				},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels:      makePrimaryLabels(canarySts.Spec.Template.Labels, primaryLabelValue, label),
+						Annotations: annotations,
+					},
+					// update spec with the primary secrets and config maps
+					Spec: c.configTracker.ApplyPrimaryConfigs(canarySts.Spec.Template.Spec, configRefs),
+				},
+			},
+		}
+
+		created, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Create(context.TODO(), primarySts, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("creating statefulset %s.%s failed: %w", primarySts.Name, cd.Namespace, err)
+		}
+
+		// keep the initial templates for rollback
+		if err := recordInitialRevision(c.kubeClient, c.configTracker, cd, created.Spec.Template, *target); err != nil {
+			return fmt.Errorf("recordInitialRevision failed: %w", err)
+		}
+
+		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+			Infof("StatefulSet %s.%s created", primarySts.GetName(), cd.Namespace)
+	}
+
+	return nil
+}
+
+// getSelectorLabel returns the selector match label
+func (c *StatefulSetController) getSelectorLabel(statefulSet *appsv1.StatefulSet) (string, string, error) {
+	for _, l := range c.labels {
+		if _, ok := statefulSet.Spec.Selector.MatchLabels[l]; ok {
+			return l, statefulSet.Spec.Selector.MatchLabels[l], nil
+		}
+	}
+
+	return "", "", fmt.Errorf(
+		"statefulset %s.%s spec.selector.matchLabels must contain one of %v",
+		statefulSet.Name, statefulSet.Namespace, c.labels,
+	)
+}
+
+func (c *StatefulSetController) HaveDependenciesChanged(cd *flaggerv1.Canary) (bool, error) {
+	return c.configTracker.HasConfigChanged(cd)
+}
+
+// Finalize sets the replica count from the primary to the reference statefulset,
+// if the primary can't be found the reference statefulset is scaled from zero
+func (c *StatefulSetController) Finalize(cd *flaggerv1.Canary) error {
+	refSts, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), cd.Spec.TargetRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("statefulset %s.%s get query error: %w", cd.Spec.TargetRef.Name, cd.Namespace, err)
+	}
+
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	primarySts, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			if err := c.ScaleFromZero(cd); err != nil {
+				return fmt.Errorf("ScaleFromZero failed: %w", err)
+			}
+			return nil
+		}
+		return fmt.Errorf("statefulset %s.%s get query error: %w", primaryName, cd.Namespace, err)
+	}
+
+	if int32Default(refSts.Spec.Replicas) != int32Default(primarySts.Spec.Replicas) {
+		if err := c.scale(cd, int32Default(primarySts.Spec.Replicas)); err != nil {
+			return fmt.Errorf("scale failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetReplicas returns the desired replicas of the canary and primary statefulsets
+func (c *StatefulSetController) GetReplicas(cd *flaggerv1.Canary) (int32, int32, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	primary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil {
+		return 0, 0, fmt.Errorf("statefulset %s.%s get query error: %w", primaryName, cd.Namespace, err)
+	}
+
+	return int32Default(canary.Spec.Replicas), int32Default(primary.Spec.Replicas), nil
+}
+
+// ScaleCanary sets the canary statefulset replicas
+func (c *StatefulSetController) ScaleCanary(cd *flaggerv1.Canary, replicas int32) error {
+	return c.scale(cd, replicas)
+}
+
+// scale sets the canary statefulset replicas
+func (c *StatefulSetController) scale(cd *flaggerv1.Canary, replicas int32) error {
+	targetName := cd.Spec.TargetRef.Name
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas": %d}}`, replicas))
+	_, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Patch(context.TODO(), targetName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("scaling statefulset %s.%s to %d failed: %w", targetName, cd.Namespace, replicas, err)
+	}
+	return nil
+}
+
+// primaryUpdateStrategy returns the canary update strategy without the partition,
+// a partitioned primary would never finish the rollout of the promoted template
+func primaryUpdateStrategy(strategy appsv1.StatefulSetUpdateStrategy) appsv1.StatefulSetUpdateStrategy {
+	result := *strategy.DeepCopy()
+	if result.RollingUpdate != nil {
+		result.RollingUpdate.Partition = nil
+	}
+	return result
+}
+
+// makeVolumeClaimTemplates copies the canary volume claim templates without their status
+func makeVolumeClaimTemplates(templates []corev1.PersistentVolumeClaim) []corev1.PersistentVolumeClaim {
+	if len(templates) == 0 {
+		return nil
+	}
+
+	result := make([]corev1.PersistentVolumeClaim, 0, len(templates))
+	for _, template := range templates {
+		result = append(result, corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        template.Name,
+				Labels:      template.Labels,
+				Annotations: template.Annotations,
+			},
+			Spec: *template.Spec.DeepCopy(),
+		})
+	}
+	return result
+}
+
+// checkVolumeClaimTemplates returns an error if the canary volume claim templates differ from the primary ones,
+// the templates of a statefulset are immutable so they can't be promoted
+func checkVolumeClaimTemplates(canary *appsv1.StatefulSet, primary *appsv1.StatefulSet) error {
+	primaryTemplates := make(map[string]corev1.PersistentVolumeClaimSpec, len(primary.Spec.VolumeClaimTemplates))
+	for _, template := range primary.Spec.VolumeClaimTemplates {
+		primaryTemplates[template.Name] = template.Spec
+	}
+
+	if len(canary.Spec.VolumeClaimTemplates) == len(primaryTemplates) {
+		changed := false
+		for _, template := range canary.Spec.VolumeClaimTemplates {
+			spec, ok := primaryTemplates[template.Name]
+			if !ok || !equality.Semantic.DeepEqual(spec, template.Spec) {
+				changed = true
+				break
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("statefulset %s.%s volumeClaimTemplates differ from %s.%s, "+
+		"the templates are immutable and can't be promoted, delete the primary statefulset to recreate it",
+		canary.Name, canary.Namespace, primary.Name, primary.Namespace)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStatefulSetController_Sync(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	_, err := mocks.controller.Initialize(mocks.canary)
+	require.Error(t, err, "primary is not ready before its pods are updated")
+
+	stsPrimary, err := mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	sts := newStatefulSetControllerTestPodInfo()
+	assert.Equal(t, sts.Spec.Template.Spec.Containers[0].Image, stsPrimary.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "podinfo-primary", stsPrimary.Spec.Selector.MatchLabels["app"])
+	assert.Equal(t, "podinfo-headless-primary", stsPrimary.Spec.ServiceName)
+	assert.Equal(t, int32(2), *stsPrimary.Spec.Replicas)
+	assert.Nil(t, stsPrimary.Spec.UpdateStrategy.RollingUpdate.Partition)
+
+	require.Len(t, stsPrimary.Spec.VolumeClaimTemplates, 1)
+	assert.Equal(t, "data", stsPrimary.Spec.VolumeClaimTemplates[0].Name)
+	assert.Empty(t, stsPrimary.Spec.VolumeClaimTemplates[0].Status.Phase)
+
+	assert.Equal(t, "podinfo-config-env-primary",
+		stsPrimary.Spec.Template.Spec.Containers[0].EnvFrom[0].ConfigMapRef.Name)
+	assert.Equal(t, "podinfo-secret-vol-primary",
+		stsPrimary.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+}
+
+func TestStatefulSetController_PrimaryService(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	mocks.controller.Initialize(mocks.canary)
+
+	svc, err := mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-headless-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, corev1.ClusterIPNone, svc.Spec.ClusterIP)
+	assert.Equal(t, map[string]string{"app": "podinfo-primary"}, svc.Spec.Selector)
+	require.Len(t, svc.Spec.Ports, 1)
+	assert.Equal(t, int32(9898), svc.Spec.Ports[0].Port)
+
+	// the canary service is left untouched
+	svc, err = mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-headless", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "podinfo"}, svc.Spec.Selector)
+
+	// the name doesn't collide with the primary service of the router
+	assert.Equal(t, "podinfo-primary-headless", getPrimaryServiceName(mocks.canary, "podinfo"))
+}
+
+func TestStatefulSetController_Promote(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	mocks.controller.Initialize(mocks.canary)
+
+	sts2 := newStatefulSetControllerTestPodInfoV2()
+	_, err := mocks.kubeClient.AppsV1().StatefulSets("default").Update(context.TODO(), sts2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.controller.Promote(mocks.canary)
+	require.NoError(t, err)
+
+	stsPrimary, err := mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, sts2.Spec.Template.Spec.Containers[0].Image, stsPrimary.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, int32(3), *stsPrimary.Spec.Replicas)
+	assert.Equal(t, "test-label-value-1", stsPrimary.Labels["app.kubernetes.io/test-label-1"])
+	assert.Nil(t, stsPrimary.Spec.UpdateStrategy.RollingUpdate.Partition)
+}
+
+func TestStatefulSetController_VolumeClaimTemplates(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	mocks.controller.Initialize(mocks.canary)
+
+	// the canary volume claim templates can't be promoted once they differ from the primary ones
+	sts2 := newStatefulSetControllerTestPodInfoV2()
+	sts2.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests["storage"] = resource.MustParse("2Gi")
+	_, err := mocks.kubeClient.AppsV1().StatefulSets("default").Update(context.TODO(), sts2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	retriable, err := mocks.controller.IsCanaryReady(mocks.canary)
+	require.Error(t, err)
+	assert.False(t, retriable)
+	assert.Contains(t, err.Error(), "volumeClaimTemplates")
+
+	err = mocks.controller.Promote(mocks.canary)
+	require.Error(t, err)
+
+	stsPrimary, err := mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.0", stsPrimary.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "1Gi", stsPrimary.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests.Storage().String())
+}
+
+func TestStatefulSetController_HasTargetChanged(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	mocks.controller.Initialize(mocks.canary)
+	err := mocks.controller.SyncStatus(mocks.canary, mocks.canary.Status)
+	require.NoError(t, err)
+
+	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	isNew, err := mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.False(t, isNew)
+
+	_, err = mocks.kubeClient.AppsV1().StatefulSets("default").Update(context.TODO(), newStatefulSetControllerTestPodInfoV2(), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	isNew, err = mocks.controller.HasTargetChanged(cd)
+	require.NoError(t, err)
+	assert.True(t, isNew)
+}
+
+func TestStatefulSetController_Scale(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	mocks.controller.Initialize(mocks.canary)
+
+	err := mocks.controller.ScaleToZero(mocks.canary)
+	require.NoError(t, err)
+
+	sts, err := mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), *sts.Spec.Replicas)
+	assert.Len(t, sts.Spec.VolumeClaimTemplates, 1)
+
+	err = mocks.controller.ScaleFromZero(mocks.canary)
+	require.NoError(t, err)
+
+	sts, err = mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *sts.Spec.Replicas)
+}
+
+func TestStatefulSetController_Finalize(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	mocks.controller.Initialize(mocks.canary)
+
+	err := mocks.controller.ScaleToZero(mocks.canary)
+	require.NoError(t, err)
+
+	err = mocks.controller.Finalize(mocks.canary)
+	require.NoError(t, err)
+
+	sts, err := mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), *sts.Spec.Replicas)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// IsPrimaryReady checks the primary statefulset status and returns an error if
+// the statefulset is in the middle of a rolling update or if the pods are unhealthy
+// it will return a non retryable error if the rolling update is stuck
+func (c *StatefulSetController) IsPrimaryReady(cd *flaggerv1.Canary) (bool, error) {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	primary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil {
+		return true, fmt.Errorf("statefulset %s.%s get query error: %w", primaryName, cd.Namespace, err)
+	}
+
+	retriable, err := c.isStatefulSetReady(cd, primary, cd.GetAnalysisPrimaryReadyThreshold())
+	if err != nil {
+		return retriable, fmt.Errorf("%s.%s not ready: %w", primaryName, cd.Namespace, err)
+	}
+
+	if int32Default(primary.Spec.Replicas) == 0 {
+		return false, fmt.Errorf("halt %s.%s advancement: primary statefulset is scaled to zero",
+			cd.Name, cd.Namespace)
+	}
+	return true, nil
+}
+
+// IsCanaryReady checks the canary statefulset status and returns an error if
+// the statefulset is in the middle of a rolling update or if the pods are unhealthy
+// it will return a non retriable error if the rolling update is stuck or
+// if the volume claim templates can't be promoted
+func (c *StatefulSetController) IsCanaryReady(cd *flaggerv1.Canary) (bool, error) {
+	targetName := cd.Spec.TargetRef.Name
+	canary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return true, fmt.Errorf("statefulset %s.%s get query error: %w", targetName, cd.Namespace, err)
+	}
+
+	primaryName := fmt.Sprintf("%s-primary", targetName)
+	primary, err := c.kubeClient.AppsV1().StatefulSets(cd.Namespace).Get(context.TODO(), primaryName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return true, fmt.Errorf("statefulset %s.%s get query error: %w", primaryName, cd.Namespace, err)
+	}
+	if err == nil {
+		if err := checkVolumeClaimTemplates(canary, primary); err != nil {
+			return false, err
+		}
+	}
+
+	retryable, err := c.isStatefulSetReady(cd, canary, cd.GetAnalysisCanaryReadyThreshold())
+	if err != nil {
+		return retryable, fmt.Errorf(
+			"canary statefulset %s.%s not ready: %w",
+			targetName, cd.Namespace, err,
+		)
+	}
+	return true, nil
+}
+
+// isStatefulSetReady determines if a statefulset is ready by checking the number of updated and ready replicas,
+// statefulsets have no progress deadline so the canary last transition time is used to detect a stuck rollout
+// reference: https://github.com/kubernetes/kubectl/blob/v0.28.0/pkg/polymorphichelpers/rollout_status.go#L120
+func (c *StatefulSetController) isStatefulSetReady(cd *flaggerv1.Canary, statefulSet *appsv1.StatefulSet, readyThreshold int) (bool, error) {
+	if statefulSet.Generation > statefulSet.Status.ObservedGeneration {
+		return true, fmt.Errorf("waiting for rollout to finish: observed statefulset generation less than desired generation")
+	}
+
+	replicas := int32Default(statefulSet.Spec.Replicas)
+	partition := int32(0)
+	if ru := statefulSet.Spec.UpdateStrategy.RollingUpdate; ru != nil && ru.Partition != nil {
+		partition = *ru.Partition
+	}
+
+	readyThresholdRatio := float32(readyThreshold) / float32(100)
+	readyThresholdReplicas := int32(float32(replicas) * readyThresholdRatio)
+
+	// calculate conditions
+	newCond := statefulSet.Status.UpdatedReplicas < replicas-partition
+	oldCond := partition == 0 && statefulSet.Status.UpdateRevision != statefulSet.Status.CurrentRevision
+	availableCond := statefulSet.Status.AvailableReplicas < readyThresholdReplicas
+	if !newCond && !oldCond && !availableCond {
+		return true, nil
+	}
+
+	// check if deadline exceeded
+	from := cd.Status.LastTransitionTime
+	delta := time.Duration(cd.GetProgressDeadlineSeconds()) * time.Second
+	if from.Add(delta).Before(time.Now()) {
+		return false, fmt.Errorf("exceeded its progressDeadlineSeconds: %d", cd.GetProgressDeadlineSeconds())
+	}
+
+	// retryable
+	switch {
+	case newCond:
+		return true, fmt.Errorf("waiting for rollout to finish: %d out of %d new replicas have been updated",
+			statefulSet.Status.UpdatedReplicas, replicas-partition)
+	case oldCond:
+		return true, fmt.Errorf("waiting for rollout to finish: %d old replicas are pending termination",
+			replicas-statefulSet.Status.UpdatedReplicas)
+	default:
+		return true, fmt.Errorf("waiting for rollout to finish: %d of %d (readyThreshold %d%%) updated replicas are available",
+			statefulSet.Status.AvailableReplicas, readyThresholdReplicas, readyThreshold)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestStatefulSetController_IsReady(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	mocks.controller.Initialize(mocks.canary)
+
+	primary, err := mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary.Status = appsv1.StatefulSetStatus{
+		UpdatedReplicas:   2,
+		AvailableReplicas: 2,
+		CurrentRevision:   "podinfo-primary-1",
+		UpdateRevision:    "podinfo-primary-1",
+	}
+	_, err = mocks.kubeClient.AppsV1().StatefulSets("default").UpdateStatus(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	_, err = mocks.controller.IsPrimaryReady(mocks.canary)
+	require.NoError(t, err)
+
+	// the advancement halts when the primary is scaled to zero
+	primary, err = mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary.Spec.Replicas = int32p(0)
+	primary.Status = appsv1.StatefulSetStatus{}
+	_, err = mocks.kubeClient.AppsV1().StatefulSets("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	retriable, err := mocks.controller.IsPrimaryReady(mocks.canary)
+	require.Error(t, err)
+	assert.False(t, retriable)
+	assert.Contains(t, err.Error(), "scaled to zero")
+
+	// the canary has a partition of one replica
+	canary, err := mocks.kubeClient.AppsV1().StatefulSets("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	canary.Status = appsv1.StatefulSetStatus{
+		UpdatedReplicas:   1,
+		AvailableReplicas: 2,
+		CurrentRevision:   "podinfo-1",
+		UpdateRevision:    "podinfo-2",
+	}
+	_, err = mocks.kubeClient.AppsV1().StatefulSets("default").UpdateStatus(context.TODO(), canary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	_, err = mocks.controller.IsCanaryReady(mocks.canary)
+	require.NoError(t, err)
+}
+
+func TestStatefulSetController_isStatefulSetReady(t *testing.T) {
+	mocks := newStatefulSetFixture()
+	cd := &flaggerv1.Canary{}
+
+	// observed generation is less than desired generation
+	sts := &appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{}}
+	sts.Status.ObservedGeneration--
+	retryable, err := mocks.controller.isStatefulSetReady(cd, sts, 100)
+	require.Error(t, err)
+	require.True(t, retryable)
+
+	// succeeded
+	sts = &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{Replicas: int32p(2)},
+		Status: appsv1.StatefulSetStatus{
+			UpdatedReplicas:   2,
+			AvailableReplicas: 2,
+		},
+	}
+	retryable, err = mocks.controller.isStatefulSetReady(cd, sts, 100)
+	require.NoError(t, err)
+	require.True(t, retryable)
+
+	// deadline exceeded
+	sts.Status.UpdatedReplicas = 1
+	cd.Status.LastTransitionTime = metav1.Now()
+	cd.Spec.ProgressDeadlineSeconds = int32p(-1e6)
+	retryable, err = mocks.controller.isStatefulSetReady(cd, sts, 100)
+	require.Error(t, err)
+	require.False(t, retryable)
+
+	// new replicas not updated
+	cd.Spec.ProgressDeadlineSeconds = int32p(1e6)
+	retryable, err = mocks.controller.isStatefulSetReady(cd, sts, 100)
+	require.Error(t, err)
+	require.True(t, retryable)
+	assert.Contains(t, err.Error(), "new replicas")
+
+	// old revision pending termination
+	sts.Status.UpdatedReplicas = 2
+	sts.Status.CurrentRevision = "podinfo-1"
+	sts.Status.UpdateRevision = "podinfo-2"
+	retryable, err = mocks.controller.isStatefulSetReady(cd, sts, 100)
+	require.Error(t, err)
+	require.True(t, retryable)
+	assert.Contains(t, err.Error(), "old replicas")
+
+	// updated replicas not available, ready with the threshold
+	sts.Status.CurrentRevision = "podinfo-2"
+	sts.Status.AvailableReplicas = 1
+	_, err = mocks.controller.isStatefulSetReady(cd, sts, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available")
+
+	_, err = mocks.controller.isStatefulSetReady(cd, sts, 50)
+	require.NoError(t, err)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// getPrimaryServiceName returns the name of the headless service governing the primary statefulset,
+// the name is suffixed when it collides with the primary ClusterIP service managed by the router
+func getPrimaryServiceName(cd *flaggerv1.Canary, serviceName string) string {
+	name := fmt.Sprintf("%s-primary", serviceName)
+	if _, primaryName, _ := cd.GetServiceNames(); name == primaryName {
+		return fmt.Sprintf("%s-headless", name)
+	}
+	return name
+}
+
+// createPrimaryService creates the headless service governing the primary statefulset from the
+// service of the canary statefulset, the primary pods get their own DNS records
+func (c *StatefulSetController) createPrimaryService(cd *flaggerv1.Canary, serviceName string,
+	label string, primaryLabelValue string) (string, error) {
+	primaryServiceName := getPrimaryServiceName(cd, serviceName)
+
+	_, err := c.kubeClient.CoreV1().Services(cd.Namespace).Get(context.TODO(), primaryServiceName, metav1.GetOptions{})
+	if err == nil {
+		return primaryServiceName, nil
+	}
+	if !errors.IsNotFound(err) {
+		return "", fmt.Errorf("service %s.%s get query error: %w", primaryServiceName, cd.Namespace, err)
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      primaryServiceName,
+			Namespace: cd.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cd, schema.GroupVersionKind{
+					Group:   flaggerv1.SchemeGroupVersion.Group,
+					Version: flaggerv1.SchemeGroupVersion.Version,
+					Kind:    flaggerv1.CanaryKind,
+				}),
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{label: primaryLabelValue},
+		},
+	}
+
+	// the canary service is optional, without it the primary service has no ports
+	src, err := c.kubeClient.CoreV1().Services(cd.Namespace).Get(context.TODO(), serviceName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return "", fmt.Errorf("service %s.%s get query error: %w", serviceName, cd.Namespace, err)
+	}
+	if err == nil {
+		svc.Labels = includeLabelsByPrefix(src.Labels, c.includeLabelPrefix)
+		svc.Spec.PublishNotReadyAddresses = src.Spec.PublishNotReadyAddresses
+		for _, port := range src.Spec.Ports {
+			port.NodePort = 0
+			svc.Spec.Ports = append(svc.Spec.Ports, port)
+		}
+	}
+
+	_, err = c.kubeClient.CoreV1().Services(cd.Namespace).Create(context.TODO(), svc, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("creating service %s.%s failed: %w", primaryServiceName, cd.Namespace, err)
+	}
+
+	c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+		Infof("Service %s.%s created", primaryServiceName, cd.Namespace)
+	return primaryServiceName, nil
+}
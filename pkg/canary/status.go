@@ -34,29 +34,51 @@ func syncCanaryStatus(flaggerClient clientset.Interface, cd *flaggerv1.Canary, s
 
 	firstTry := true
 	name, ns := cd.GetName(), cd.GetNamespace()
+	current := cd
+	var cdCopy *flaggerv1.Canary
 	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
 		if !firstTry {
-			cd, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			current, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
 			if err != nil {
 				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
 			}
 		}
 
-		cdCopy := cd.DeepCopy()
+		cdCopy = current.DeepCopy()
 		cdCopy.Status.Phase = status.Phase
 		cdCopy.Status.CanaryWeight = status.CanaryWeight
 		cdCopy.Status.FailedChecks = status.FailedChecks
+		cdCopy.Status.InconclusiveChecks = status.InconclusiveChecks
+		cdCopy.Status.WarmupIterations = status.WarmupIterations
+		cdCopy.Status.MetricFailedChecks = status.MetricFailedChecks
 		cdCopy.Status.Iterations = status.Iterations
+		cdCopy.Status.StepIndex = status.StepIndex
+		cdCopy.Status.StepStartTime = status.StepStartTime
+		cdCopy.Status.Paused = status.Paused
+		cdCopy.Status.QueuePosition = status.QueuePosition
 		cdCopy.Status.LastAppliedSpec = hash
+		if status.TargetRevisions != nil {
+			cdCopy.Status.TargetRevisions = status.TargetRevisions
+		}
+		if status.FastTrack != nil {
+			cdCopy.Status.FastTrack = status.FastTrack
+		}
+		if status.CanaryReplicas != nil {
+			cdCopy.Status.CanaryReplicas = status.CanaryReplicas
+		}
 		if status.Phase == flaggerv1.CanaryPhaseInitialized {
 			cdCopy.Status.LastPromotedSpec = hash
 		}
 		cdCopy.Status.LastTransitionTime = metav1.Now()
 		setAll(cdCopy)
 
-		if ok, conditions := MakeStatusConditions(cd, status.Phase); ok {
+		if ok, conditions := MakeStatusConditions(current, status.Phase); ok {
 			cdCopy.Status.Conditions = conditions
 		}
+		// a new analysis run starts with a new warm-up
+		if status.Phase == flaggerv1.CanaryPhaseProgressing {
+			cdCopy.Status.Conditions = removeStatusCondition(cdCopy.Status.Conditions, flaggerv1.WarmedUpType)
+		}
 
 		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
 		firstTry = false
@@ -66,6 +88,7 @@ func syncCanaryStatus(flaggerClient clientset.Interface, cd *flaggerv1.Canary, s
 	if err != nil {
 		return fmt.Errorf("failed after retries: %w", err)
 	}
+	cd.Status = cdCopy.Status
 	return nil
 }
 
@@ -107,6 +130,14 @@ func setStatusWeight(flaggerClient clientset.Interface, cd *flaggerv1.Canary, va
 		cdCopy.Status.CanaryWeight = val
 		cdCopy.Status.LastTransitionTime = metav1.Now()
 
+		// start the analysis step matching the new weight
+		if len(cd.GetAnalysis().Steps) > 0 && cd.Status.Phase == flaggerv1.CanaryPhaseProgressing &&
+			val > 0 && val != cd.Status.CanaryWeight {
+			cdCopy.Status.StepIndex = getStepIndex(cd, val)
+			cdCopy.Status.StepStartTime = &cdCopy.Status.LastTransitionTime
+			cdCopy.Status.Iterations = 0
+		}
+
 		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
 		firstTry = false
 		return
@@ -143,6 +174,58 @@ func setStatusIterations(flaggerClient clientset.Interface, cd *flaggerv1.Canary
 	return nil
 }
 
+func setStatusLastPromotedSpec(flaggerClient clientset.Interface, cd *flaggerv1.Canary, spec string) error {
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			cd, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := cd.DeepCopy()
+		cdCopy.Status.LastPromotedSpec = spec
+		cdCopy.Status.LastTransitionTime = metav1.Now()
+
+		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
+		firstTry = false
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	return nil
+}
+
+// setStatusReverted marks the reverted target spec and configs as promoted
+func setStatusReverted(flaggerClient clientset.Interface, cd *flaggerv1.Canary, spec string, configs *map[string]string) error {
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			cd, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := cd.DeepCopy()
+		cdCopy.Status.LastPromotedSpec = spec
+		cdCopy.Status.TrackedConfigs = configs
+		cdCopy.Status.LastTransitionTime = metav1.Now()
+
+		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
+		firstTry = false
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	return nil
+}
+
 func setStatusPhase(flaggerClient clientset.Interface, cd *flaggerv1.Canary, phase flaggerv1.CanaryPhase) error {
 	firstTry := true
 	name, ns := cd.GetName(), cd.GetNamespace()
@@ -157,13 +240,22 @@ func setStatusPhase(flaggerClient clientset.Interface, cd *flaggerv1.Canary, pha
 		cdCopy := cd.DeepCopy()
 		cdCopy.Status.Phase = phase
 		cdCopy.Status.LastTransitionTime = metav1.Now()
+		if phase != flaggerv1.CanaryPhaseWaiting {
+			cdCopy.Status.QueuePosition = 0
+		}
 
 		if phase != flaggerv1.CanaryPhaseProgressing && phase != flaggerv1.CanaryPhaseWaiting {
 			cdCopy.Status.CanaryWeight = 0
 			cdCopy.Status.Iterations = 0
+			cdCopy.Status.StepIndex = 0
+			cdCopy.Status.StepStartTime = nil
+			cdCopy.Status.Paused = false
 			if phase == flaggerv1.CanaryPhaseWaitingPromotion {
 				cdCopy.Status.Iterations = cd.GetAnalysis().Iterations - 1
 			}
+			if phase == flaggerv1.CanaryPhaseVerifying {
+				cdCopy.Status.FailedChecks = 0
+			}
 		}
 
 		// on promotion set primary spec hash
@@ -185,6 +277,230 @@ func setStatusPhase(flaggerClient clientset.Interface, cd *flaggerv1.Canary, pha
 	return nil
 }
 
+// SetStatusInconclusiveChecks records the number of inconclusive checks
+func SetStatusInconclusiveChecks(flaggerClient clientset.Interface, cd *flaggerv1.Canary, val int) error {
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	current := cd
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			current, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := current.DeepCopy()
+		cdCopy.Status.InconclusiveChecks = val
+		cdCopy.Status.LastTransitionTime = metav1.Now()
+
+		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
+		firstTry = false
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	cd.Status.InconclusiveChecks = val
+	return nil
+}
+
+// SetStatusMetricFailedChecks records the number of failed checks of a metric
+func SetStatusMetricFailedChecks(flaggerClient clientset.Interface, cd *flaggerv1.Canary, metricName string, val int) error {
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	current := cd
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			current, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := current.DeepCopy()
+		if cdCopy.Status.MetricFailedChecks == nil {
+			cdCopy.Status.MetricFailedChecks = make(map[string]int)
+		}
+		cdCopy.Status.MetricFailedChecks[metricName] = val
+		cdCopy.Status.LastTransitionTime = metav1.Now()
+
+		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
+		firstTry = false
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	if cd.Status.MetricFailedChecks == nil {
+		cd.Status.MetricFailedChecks = make(map[string]int)
+	}
+	cd.Status.MetricFailedChecks[metricName] = val
+	return nil
+}
+
+// SetStatusPaused freezes or unfreezes the canary advancement
+func SetStatusPaused(flaggerClient clientset.Interface, cd *flaggerv1.Canary, paused bool) error {
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	current := cd
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			current, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := current.DeepCopy()
+		cdCopy.Status.Paused = paused
+		cdCopy.Status.LastTransitionTime = metav1.Now()
+
+		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
+		firstTry = false
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	cd.Status.Paused = paused
+	return nil
+}
+
+// SetStatusWaiting sets the waiting phase with the reason on the promoted condition
+func SetStatusWaiting(flaggerClient clientset.Interface, cd *flaggerv1.Canary, message string, queuePosition int) error {
+	phase := flaggerv1.CanaryPhaseWaiting
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			cd, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+		firstTry = false
+
+		now := metav1.Now()
+		condition := flaggerv1.CanaryCondition{
+			Type:               flaggerv1.PromotedType,
+			Status:             corev1.ConditionUnknown,
+			LastUpdateTime:     now,
+			LastTransitionTime: now,
+			Reason:             string(phase),
+			Message:            message,
+		}
+		for _, current := range cd.Status.Conditions {
+			if current.Type != flaggerv1.PromotedType {
+				continue
+			}
+			if current.Status == condition.Status && current.Reason == condition.Reason && current.Message == condition.Message &&
+				cd.Status.Phase == phase && cd.Status.QueuePosition == queuePosition {
+				return nil
+			}
+			if current.Status == condition.Status {
+				condition.LastTransitionTime = current.LastTransitionTime
+			}
+		}
+
+		cdCopy := cd.DeepCopy()
+		cdCopy.Status.Conditions = []flaggerv1.CanaryCondition{condition}
+		cdCopy.Status.QueuePosition = queuePosition
+		if cdCopy.Status.Phase != phase {
+			cdCopy.Status.Phase = phase
+			cdCopy.Status.LastTransitionTime = now
+		}
+		return updateStatusWithUpgrade(flaggerClient, cdCopy)
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	return nil
+}
+
+// SetStatusSelectorSwapped records if the services select the canary pods instead of the primary pods
+func SetStatusSelectorSwapped(flaggerClient clientset.Interface, cd *flaggerv1.Canary, swapped bool) error {
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	current := cd
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			current, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := current.DeepCopy()
+		cdCopy.Status.SelectorSwapped = swapped
+
+		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
+		firstTry = false
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	cd.Status.SelectorSwapped = swapped
+	return nil
+}
+
+// SetStatusWarmup records the warm-up condition and iterations
+func SetStatusWarmup(flaggerClient clientset.Interface, cd *flaggerv1.Canary, status corev1.ConditionStatus,
+	reason string, message string, iterations int) error {
+	now := metav1.Now()
+	condition := flaggerv1.CanaryCondition{
+		Type:               flaggerv1.WarmedUpType,
+		Status:             status,
+		LastUpdateTime:     now,
+		LastTransitionTime: now,
+		Reason:             reason,
+		Message:            message,
+	}
+	if current := getStatusCondition(cd.Status, flaggerv1.WarmedUpType); current != nil && current.Status == status {
+		condition.LastTransitionTime = current.LastTransitionTime
+	}
+
+	firstTry := true
+	name, ns := cd.GetName(), cd.GetNamespace()
+	current := cd
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() (err error) {
+		if !firstTry {
+			current, err = flaggerClient.FlaggerV1beta1().Canaries(ns).Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("canary %s.%s get query failed: %w", name, ns, err)
+			}
+		}
+
+		cdCopy := current.DeepCopy()
+		cdCopy.Status.Conditions = setStatusCondition(cdCopy.Status.Conditions, condition)
+		cdCopy.Status.WarmupIterations = iterations
+		cdCopy.Status.LastTransitionTime = now
+
+		err = updateStatusWithUpgrade(flaggerClient, cdCopy)
+		firstTry = false
+		return
+	})
+	if err != nil {
+		return fmt.Errorf("failed after retries: %w", err)
+	}
+	cd.Status.Conditions = setStatusCondition(cd.Status.Conditions, condition)
+	cd.Status.WarmupIterations = iterations
+	return nil
+}
+
+// getStepIndex returns the index of the first analysis step that routes at least the given weight
+func getStepIndex(cd *flaggerv1.Canary, weight int) int {
+	steps := cd.GetAnalysis().Steps
+	for i, step := range steps {
+		if step.Weight >= weight {
+			return i
+		}
+	}
+	return len(steps) - 1
+}
+
 // getStatusCondition returns a condition based on type
 func getStatusCondition(status flaggerv1.CanaryStatus, conditionType flaggerv1.CanaryConditionType) *flaggerv1.CanaryCondition {
 	for i := range status.Conditions {
@@ -222,9 +538,18 @@ func MakeStatusConditions(cd *flaggerv1.Canary,
 	case flaggerv1.CanaryPhasePromoting:
 		status = corev1.ConditionUnknown
 		message = "Canary analysis completed, starting primary rolling update."
+	case flaggerv1.CanaryPhaseRestoring:
+		status = corev1.ConditionFalse
+		message = "Primary rolling update stalled, restoring the primary to the previous revision."
 	case flaggerv1.CanaryPhaseFinalising:
 		status = corev1.ConditionUnknown
 		message = "Canary analysis completed, routing all traffic to primary."
+	case flaggerv1.CanaryPhaseVerifying:
+		status = corev1.ConditionUnknown
+		message = "Canary promotion finished, verifying the primary."
+	case flaggerv1.CanaryPhaseReverted:
+		status = corev1.ConditionFalse
+		message = "Post-promotion verification failed, primary restored to the previous revision."
 	case flaggerv1.CanaryPhaseSucceeded:
 		status = corev1.ConditionTrue
 		message = "Canary analysis completed successfully, promotion finished."
@@ -252,7 +577,38 @@ func MakeStatusConditions(cd *flaggerv1.Canary,
 		newCondition.LastTransitionTime = currentCondition.LastTransitionTime
 	}
 
-	return true, []flaggerv1.CanaryCondition{*newCondition}
+	// keep the other conditions
+	conditions := []flaggerv1.CanaryCondition{*newCondition}
+	for _, condition := range cd.Status.Conditions {
+		if condition.Type != flaggerv1.PromotedType {
+			conditions = append(conditions, condition)
+		}
+	}
+	return true, conditions
+}
+
+// removeStatusCondition returns the conditions without the ones of the type
+func removeStatusCondition(conditions []flaggerv1.CanaryCondition,
+	conditionType flaggerv1.CanaryConditionType) []flaggerv1.CanaryCondition {
+	var result []flaggerv1.CanaryCondition
+	for _, condition := range conditions {
+		if condition.Type != conditionType {
+			result = append(result, condition)
+		}
+	}
+	return result
+}
+
+// setStatusCondition returns the conditions with the condition of the same type replaced or appended
+func setStatusCondition(conditions []flaggerv1.CanaryCondition,
+	condition flaggerv1.CanaryCondition) []flaggerv1.CanaryCondition {
+	result := make([]flaggerv1.CanaryCondition, 0, len(conditions)+1)
+	for _, c := range conditions {
+		if c.Type != condition.Type {
+			result = append(result, c)
+		}
+	}
+	return append(result, condition)
 }
 
 // updateStatusWithUpgrade tries to update the status sub-resource
@@ -17,15 +17,19 @@ limitations under the License.
 package canary
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"io"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	clientset "github.com/fluxcd/flagger/pkg/client/clientset/versioned"
 )
 
 var sidecars = map[string]bool{
@@ -151,3 +155,33 @@ func int32Default(i *int32) int32 {
 
 	return *i
 }
+
+// getAutoscalerMinReplicas returns the min replicas of the canary autoscaler
+// or nil if the autoscaler can't be found or doesn't require more than one replica
+func getAutoscalerMinReplicas(kubeClient kubernetes.Interface, flaggerClient clientset.Interface, cd *flaggerv1.Canary) *int32 {
+	if cd.Spec.AutoscalerRef == nil {
+		return nil
+	}
+
+	switch cd.Spec.AutoscalerRef.Kind {
+	case "HorizontalPodAutoscaler":
+		hpa, err := kubeClient.AutoscalingV2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), cd.Spec.AutoscalerRef.Name, metav1.GetOptions{})
+		if err == nil {
+			if hpa.Spec.MinReplicas != nil && *hpa.Spec.MinReplicas > 1 {
+				return hpa.Spec.MinReplicas
+			}
+			return nil
+		}
+		// fallback to v2beta2
+		hpaBeta, err := kubeClient.AutoscalingV2beta2().HorizontalPodAutoscalers(cd.Namespace).Get(context.TODO(), cd.Spec.AutoscalerRef.Name, metav1.GetOptions{})
+		if err == nil && hpaBeta.Spec.MinReplicas != nil && *hpaBeta.Spec.MinReplicas > 1 {
+			return hpaBeta.Spec.MinReplicas
+		}
+	case "ScaledObject":
+		so, err := flaggerClient.KedaV1alpha1().ScaledObjects(cd.Namespace).Get(context.TODO(), cd.Spec.AutoscalerRef.Name, metav1.GetOptions{})
+		if err == nil && so.Spec.MinReplicaCount != nil && *so.Spec.MinReplicaCount > 1 {
+			return so.Spec.MinReplicaCount
+		}
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -27,6 +28,7 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
@@ -63,22 +65,36 @@ type Controller struct {
 	eventRecorder        record.EventRecorder
 	logger               *zap.SugaredLogger
 	canaries             *sync.Map
-	jobs                 map[string]CanaryJob
+	scheduler            *canaryQueue
+	schedulerWorkers     int
 	recorder             metrics.Recorder
 	notifier             notifier.Interface
 	canaryFactory        *canary.Factory
 	routerFactory        *router.Factory
 	observerFactory      *observers.Factory
+	observerFactories    sync.Map
+	kubeRouters          sync.Map
+	meshRouters          sync.Map
 	meshProvider         string
 	eventWebhook         string
 	clusterName          string
 	noCrossNamespaceRefs bool
+	shardKey             string
+	releasePolicies      *releasePolicyStore
+	concurrency          *concurrencyLimiter
 }
 
 type Informers struct {
 	CanaryInformer flaggerinformers.CanaryInformer
 	MetricInformer flaggerinformers.MetricTemplateInformer
 	AlertInformer  flaggerinformers.AlertProviderInformer
+
+	// optional informers used to run the analysis as soon as a target changes
+	DeploymentInformer  cache.SharedIndexInformer
+	DaemonSetInformer   cache.SharedIndexInformer
+	StatefulSetInformer cache.SharedIndexInformer
+	ConfigMapInformer   cache.SharedIndexInformer
+	SecretInformer      cache.SharedIndexInformer
 }
 
 func NewController(
@@ -98,6 +114,12 @@ func NewController(
 	clusterName string,
 	noCrossNamespaceRefs bool,
 	kubeConfig *rest.Config,
+	schedulerWorkers int,
+	schedulerJitter float64,
+	shardKey string,
+	releasePolicy string,
+	maxConcurrentCanaries int,
+	maxConcurrentCanariesPerNamespace int,
 ) *Controller {
 	logger.Debug("Creating event broadcaster")
 	flaggerscheme.AddToScheme(scheme.Scheme)
@@ -109,7 +131,7 @@ func NewController(
 	eventRecorder := eventBroadcaster.NewRecorder(
 		scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 	recorder := metrics.NewRecorder(controllerAgentName, true)
-	recorder.SetInfo(version, meshProvider)
+	recorder.SetInfo(version, meshProvider, shardKey)
 
 	ctrl := &Controller{
 		kubeConfig:           kubeConfig,
@@ -122,7 +144,8 @@ func NewController(
 		eventRecorder:        eventRecorder,
 		logger:               logger,
 		canaries:             new(sync.Map),
-		jobs:                 map[string]CanaryJob{},
+		scheduler:            newCanaryQueue(schedulerJitter),
+		schedulerWorkers:     schedulerWorkers,
 		flaggerWindow:        flaggerWindow,
 		observerFactory:      observerFactory,
 		recorder:             recorder,
@@ -133,6 +156,7 @@ func NewController(
 		eventWebhook:         eventWebhook,
 		clusterName:          clusterName,
 		noCrossNamespaceRefs: noCrossNamespaceRefs,
+		shardKey:             shardKey,
 	}
 
 	flaggerInformers.CanaryInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -184,6 +208,17 @@ func NewController(
 		},
 	})
 
+	if releasePolicy != "" {
+		ns, name, _ := cache.SplitMetaNamespaceKey(releasePolicy)
+		ctrl.releasePolicies = newReleasePolicyStore(kubeClient, ns, name)
+	}
+
+	if maxConcurrentCanaries > 0 || maxConcurrentCanariesPerNamespace > 0 {
+		ctrl.concurrency = newConcurrencyLimiter(maxConcurrentCanaries, maxConcurrentCanariesPerNamespace)
+	}
+
+	ctrl.addTargetEventHandlers(flaggerInformers)
+
 	return ctrl
 }
 
@@ -203,10 +238,20 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 
 	c.logger.Info("Started operator workers")
 
+	defer c.scheduler.shutDown()
+	for i := 0; i < c.schedulerWorkers; i++ {
+		go wait.Until(c.runSchedulerWorker, time.Second, stopCh)
+	}
+
+	c.logger.Infof("Started %d scheduler workers", c.schedulerWorkers)
+
+	c.syncReleasePolicies()
+
 	tickChan := time.NewTicker(c.flaggerWindow).C
 	for {
 		select {
 		case <-tickChan:
+			c.syncReleasePolicies()
 			c.scheduleCanaries()
 		case <-stopCh:
 			c.logger.Info("Shutting down operator workers")
@@ -340,6 +385,57 @@ func (c *Controller) verifyCanary(canary *flaggerv1.Canary) error {
 	if err := verifySessionAffinity(canary); err != nil {
 		return err
 	}
+	if err := verifyMetricComparison(canary, c.getMetricsProvider(canary)); err != nil {
+		return err
+	}
+	if err := verifyMinSamples(canary); err != nil {
+		return err
+	}
+	if err := verifyFailurePolicies(canary); err != nil {
+		return err
+	}
+	if err := verifyScoring(canary); err != nil {
+		return err
+	}
+	if err := verifyBaseline(canary, c.meshProvider); err != nil {
+		return err
+	}
+	if err := verifySteps(canary); err != nil {
+		return err
+	}
+	if err := verifyPostPromotion(canary); err != nil {
+		return err
+	}
+	if err := verifyDependencies(canary); err != nil {
+		return err
+	}
+	if err := c.verifyDependencyCycles(canary); err != nil {
+		return err
+	}
+	if err := verifyAdditionalTargets(canary); err != nil {
+		return err
+	}
+	if err := verifyTargetPaths(canary); err != nil {
+		return err
+	}
+	if err := verifyReplicaScaling(canary); err != nil {
+		return err
+	}
+	if err := verifyPrimaryRollout(canary); err != nil {
+		return err
+	}
+	if err := verifyRevertOnFailure(canary); err != nil {
+		return err
+	}
+	if err := verifyIgnoreChanges(canary); err != nil {
+		return err
+	}
+	if err := verifyFastTrack(canary); err != nil {
+		return err
+	}
+	if err := verifyWarmup(canary); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -348,6 +444,11 @@ func verifyNoCrossNamespaceRefs(canary *flaggerv1.Canary) error {
 	if canary.Spec.UpstreamRef != nil && canary.Spec.UpstreamRef.Namespace != canary.Namespace {
 		return fmt.Errorf("can't access gloo upstream %s.%s, cross-namespace references are blocked", canary.Spec.UpstreamRef.Name, canary.Spec.UpstreamRef.Namespace)
 	}
+	for _, ref := range canary.Spec.DependsOn {
+		if ref.Namespace != "" && ref.Namespace != canary.Namespace {
+			return fmt.Errorf("can't depend on canary %s.%s, cross-namespace references are blocked", ref.Name, ref.Namespace)
+		}
+	}
 	if canary.Spec.Analysis != nil {
 		for _, metric := range canary.Spec.Analysis.Metrics {
 			if metric.TemplateRef != nil && metric.TemplateRef.Namespace != canary.Namespace {
@@ -391,6 +492,481 @@ func verifySessionAffinity(canary *flaggerv1.Canary) error {
 	return nil
 }
 
+func verifyMetricComparison(canary *flaggerv1.Canary, metricsProvider string) error {
+	if canary.GetAnalysis() == nil {
+		return nil
+	}
+
+	observer := observers.Factory{}.Observer(metricsProvider)
+
+	for _, metric := range canary.GetAnalysis().Metrics {
+		if metric.Comparison == nil {
+			continue
+		}
+		if canary.Spec.TargetRef.IsKnativeService() {
+			return fmt.Errorf("can't compare metric %s with the primary when using Knative Service as target", metric.Name)
+		}
+		if isBuiltinMetric(metric) && !canCompareBuiltinMetrics(observer) {
+			return fmt.Errorf("can't compare builtin metric %s with the primary when using %s as metrics provider", metric.Name, metricsProvider)
+		}
+		if metric.Comparison.MaxRatio == nil && metric.Comparison.MaxDelta == nil {
+			return fmt.Errorf("metric %s comparison requires maxRatio or maxDelta", metric.Name)
+		}
+		switch metric.Comparison.Direction {
+		case "", flaggerv1.ComparisonIncrease, flaggerv1.ComparisonDecrease:
+		default:
+			return fmt.Errorf("metric %s comparison direction %s is not supported", metric.Name, metric.Comparison.Direction)
+		}
+	}
+
+	return nil
+}
+
+// isBuiltinMetric returns true if the metric is checked with the builtin query of the metrics provider
+func isBuiltinMetric(metric flaggerv1.CanaryMetric) bool {
+	return metric.TemplateRef == nil && (metric.Name == "request-success-rate" || metric.Name == "request-duration")
+}
+
+// canCompareBuiltinMetrics returns true if the builtin queries of the observer select the workload
+// by the target name only, other observers select the canary by its route, ingress or upstream and
+// would run the same query for the primary
+func canCompareBuiltinMetrics(observer observers.Interface) bool {
+	switch observer.(type) {
+	case *observers.IstioObserver, *observers.LinkerdObserver, *observers.AppMeshObserver,
+		*observers.HttpObserver, *observers.OsmObserver:
+		return true
+	default:
+		return false
+	}
+}
+
+func verifyMinSamples(canary *flaggerv1.Canary) error {
+	if canary.GetAnalysis() == nil {
+		return nil
+	}
+	if canary.GetAnalysis().MaxInconclusive < 0 {
+		return fmt.Errorf("max inconclusive %d can't be negative", canary.GetAnalysis().MaxInconclusive)
+	}
+
+	for _, metric := range canary.GetAnalysis().Metrics {
+		if metric.MinSamples < 0 {
+			return fmt.Errorf("metric %s min samples %d can't be negative", metric.Name, metric.MinSamples)
+		}
+		if metric.MinSamples > 0 && metric.MinSampleQuery == "" {
+			return fmt.Errorf("metric %s min samples requires a min sample query", metric.Name)
+		}
+		if metric.MinSampleQuery != "" && metric.MinSamples == 0 {
+			return fmt.Errorf("metric %s min sample query requires min samples greater than zero", metric.Name)
+		}
+	}
+
+	return nil
+}
+
+func verifyFailurePolicies(canary *flaggerv1.Canary) error {
+	if canary.GetAnalysis() == nil {
+		return nil
+	}
+
+	for _, metric := range canary.GetAnalysis().Metrics {
+		switch metric.FailurePolicy {
+		case "", flaggerv1.MetricFailureWarn, flaggerv1.MetricFailureFailFast:
+			if metric.FailureThreshold != 0 {
+				return fmt.Errorf("metric %s failure threshold requires the threshold failure policy", metric.Name)
+			}
+		case flaggerv1.MetricFailureThreshold:
+			if metric.FailureThreshold < 1 {
+				return fmt.Errorf("metric %s threshold failure policy requires a failure threshold greater than zero", metric.Name)
+			}
+		default:
+			return fmt.Errorf("metric %s failure policy %s is not supported", metric.Name, metric.FailurePolicy)
+		}
+		if metric.FailurePolicy == "" {
+			continue
+		}
+
+		if metric.Name == "" {
+			return fmt.Errorf("metric failure policy %s requires a metric name", metric.FailurePolicy)
+		}
+		builtin := metric.Name == "request-success-rate" || metric.Name == "request-duration"
+		if canary.GetAnalysis().Scoring != nil && !builtin {
+			return fmt.Errorf("metric %s failure policy can't be used with statistical scoring", metric.Name)
+		}
+	}
+
+	return nil
+}
+
+func verifyScoring(canary *flaggerv1.Canary) error {
+	if canary.GetAnalysis() == nil || canary.GetAnalysis().Scoring == nil {
+		return nil
+	}
+
+	scoring := canary.GetAnalysis().Scoring
+	if canary.Spec.TargetRef.IsKnativeService() {
+		return fmt.Errorf("can't use scoring with Knative Service as target")
+	}
+	if scoring.GetPass() < 0 || scoring.GetPass() > 100 || scoring.GetMarginal() < 0 || scoring.GetMarginal() > 100 {
+		return fmt.Errorf("scoring pass and marginal must be in the range of [0, 100]")
+	}
+	if scoring.GetMarginal() > scoring.GetPass() {
+		return fmt.Errorf("scoring marginal %v can't be greater than pass %v", scoring.GetMarginal(), scoring.GetPass())
+	}
+
+	scored := false
+	for _, metric := range canary.GetAnalysis().Metrics {
+		if metric.TemplateRef != nil || metric.Query != "" {
+			scored = true
+			break
+		}
+	}
+	if !scored {
+		return fmt.Errorf("scoring requires at least one metric template or query, the builtin metrics are not scored")
+	}
+
+	return nil
+}
+
+func verifyBaseline(canary *flaggerv1.Canary, meshProvider string) error {
+	if !canary.HasBaseline() {
+		return nil
+	}
+
+	provider := meshProvider
+	if canary.Spec.Provider != "" {
+		provider = canary.Spec.Provider
+	}
+	if provider != flaggerv1.IstioProvider {
+		return fmt.Errorf("baseline is not supported by the %s provider", provider)
+	}
+	if canary.Spec.TargetRef.Kind != "Deployment" {
+		return fmt.Errorf("baseline is not supported for %s targets", canary.Spec.TargetRef.Kind)
+	}
+	analysis := canary.GetAnalysis()
+	if len(analysis.Match) > 0 || analysis.Mirror || analysis.SessionAffinity != nil {
+		return fmt.Errorf("baseline can't be used with A/B testing, traffic mirroring or session affinity")
+	}
+
+	return nil
+}
+
+func verifySteps(canary *flaggerv1.Canary) error {
+	if canary.GetAnalysis() == nil || len(canary.GetAnalysis().Steps) == 0 {
+		return nil
+	}
+
+	analysis := canary.GetAnalysis()
+	if analysis.StepWeight > 0 || len(analysis.StepWeights) > 0 {
+		return fmt.Errorf("steps can't be used with stepWeight or stepWeights")
+	}
+	if analysis.Iterations > 0 {
+		return fmt.Errorf("steps can't be used with A/B testing or Blue/Green iterations")
+	}
+	previous := 0
+	for i, step := range analysis.Steps {
+		if step.Weight <= previous || step.Weight > 100 {
+			return fmt.Errorf("step %d weight %v must be greater than the previous step weight and at most 100", i, step.Weight)
+		}
+		if step.Duration != "" {
+			if d, err := time.ParseDuration(step.Duration); err != nil || d < 0 {
+				return fmt.Errorf("step %d duration %s is invalid", i, step.Duration)
+			}
+		}
+		if step.Iterations < 0 {
+			return fmt.Errorf("step %d iterations can't be negative", i)
+		}
+		previous = step.Weight
+	}
+
+	return nil
+}
+
+func verifyPostPromotion(canary *flaggerv1.Canary) error {
+	if !canary.HasPostPromotion() {
+		return nil
+	}
+
+	if kind := canary.Spec.TargetRef.Kind; kind != "Deployment" && kind != "DaemonSet" && kind != "StatefulSet" {
+		return fmt.Errorf("post-promotion verification is not supported for %s targets", kind)
+	}
+	if window, err := time.ParseDuration(canary.GetAnalysis().PostPromotion.Window); err != nil || window <= 0 {
+		return fmt.Errorf("post-promotion window %s is invalid", canary.GetAnalysis().PostPromotion.Window)
+	}
+
+	return nil
+}
+
+func verifyDependencies(canary *flaggerv1.Canary) error {
+	for _, ref := range canary.Spec.DependsOn {
+		if ref.Name == canary.Name && (ref.Namespace == "" || ref.Namespace == canary.Namespace) {
+			return fmt.Errorf("canary %s.%s can't depend on itself", canary.Name, canary.Namespace)
+		}
+	}
+
+	switch canary.Spec.DependencyFailurePolicy {
+	case "", flaggerv1.DependencyFailureBlock, flaggerv1.DependencyFailureRollback:
+		return nil
+	default:
+		return fmt.Errorf("dependency failure policy %s is invalid, can be %s or %s", canary.Spec.DependencyFailurePolicy,
+			flaggerv1.DependencyFailureBlock, flaggerv1.DependencyFailureRollback)
+	}
+}
+
+// verifyDependencyCycles walks the dependency graph of the canary and returns an error
+// if the canary depends on itself through its dependencies
+func (c *Controller) verifyDependencyCycles(canary *flaggerv1.Canary) error {
+	if len(canary.Spec.DependsOn) == 0 {
+		return nil
+	}
+
+	root := fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)
+	visited := map[string]bool{root: true}
+	var walk func(cd *flaggerv1.Canary, path []string) error
+	walk = func(cd *flaggerv1.Canary, path []string) error {
+		for _, ref := range cd.Spec.DependsOn {
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = cd.Namespace
+			}
+			key := fmt.Sprintf("%s.%s", ref.Name, namespace)
+			if key == root {
+				return fmt.Errorf("dependency cycle detected %s", strings.Join(append(path, key), " -> "))
+			}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			dep, err := c.getCanary(ref.Name, namespace)
+			if err != nil {
+				// the dependencies that don't exist yet are checked once they are created
+				continue
+			}
+			if err := walk(dep, append(path, key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(canary, []string{root})
+}
+
+func verifyAdditionalTargets(canary *flaggerv1.Canary) error {
+	if len(canary.Spec.AdditionalTargetRefs) == 0 {
+		return nil
+	}
+
+	isWorkload := func(kind string) bool {
+		return kind == "Deployment" || kind == "DaemonSet" || kind == "StatefulSet"
+	}
+	if !isWorkload(canary.Spec.TargetRef.Kind) {
+		return fmt.Errorf("additional targets are not supported for %s targets", canary.Spec.TargetRef.Kind)
+	}
+	if canary.HasBaseline() {
+		return fmt.Errorf("baseline is not supported with additional targets")
+	}
+	if canary.HasPostPromotion() {
+		return fmt.Errorf("post-promotion verification is not supported with additional targets")
+	}
+
+	refs := map[string]bool{
+		fmt.Sprintf("%s/%s", canary.Spec.TargetRef.Kind, canary.Spec.TargetRef.Name): true,
+	}
+	for _, ref := range canary.Spec.AdditionalTargetRefs {
+		if !isWorkload(ref.Kind) {
+			return fmt.Errorf("additional target %s %s is not supported, can be a Deployment, a DaemonSet or a StatefulSet", ref.Kind, ref.Name)
+		}
+		key := fmt.Sprintf("%s/%s", ref.Kind, ref.Name)
+		if refs[key] {
+			return fmt.Errorf("target %s is referenced more than once", key)
+		}
+		refs[key] = true
+	}
+	return nil
+}
+
+func verifyTargetPaths(cd *flaggerv1.Canary) error {
+	if cd.Spec.TargetPaths == nil {
+		return nil
+	}
+
+	if !cd.Spec.TargetRef.IsGenericWorkload() {
+		return fmt.Errorf("target paths are not supported for %s targets", cd.Spec.TargetRef.Kind)
+	}
+	return canary.VerifyTargetPaths(cd.Spec.TargetPaths)
+}
+
+func verifyReplicaScaling(canary *flaggerv1.Canary) error {
+	analysis := canary.GetAnalysis()
+	if analysis == nil || analysis.ReplicaScaling == nil {
+		return nil
+	}
+
+	if kind := canary.Spec.TargetRef.Kind; kind != "Deployment" && kind != "StatefulSet" &&
+		!canary.Spec.TargetRef.IsGenericWorkload() {
+		return fmt.Errorf("replica scaling is not supported for %s targets", kind)
+	}
+	if canary.Spec.AutoscalerRef != nil {
+		return fmt.Errorf("replica scaling can't be used with an autoscaler")
+	}
+	if len(canary.Spec.AdditionalTargetRefs) > 0 {
+		return fmt.Errorf("replica scaling can't be used with additional targets")
+	}
+	if len(analysis.Match) > 0 || analysis.Iterations > 0 {
+		return fmt.Errorf("replica scaling can only be used with progressive traffic shifting")
+	}
+	if h := analysis.ReplicaScaling.Headroom; h != nil && *h <= 0 {
+		return fmt.Errorf("replica scaling headroom %v must be greater than zero", *h)
+	}
+	if m := analysis.ReplicaScaling.MinReplicas; m != nil && *m < 1 {
+		return fmt.Errorf("replica scaling min replicas %d must be at least one", *m)
+	}
+	return nil
+}
+
+func verifyPrimaryRollout(canary *flaggerv1.Canary) error {
+	analysis := canary.GetAnalysis()
+	if analysis == nil || analysis.PrimaryRollout == nil {
+		return nil
+	}
+
+	if canary.Spec.TargetRef.Kind != "Deployment" {
+		return fmt.Errorf("primary rollout is not supported for %s targets", canary.Spec.TargetRef.Kind)
+	}
+	if len(canary.Spec.AdditionalTargetRefs) > 0 {
+		return fmt.Errorf("primary rollout can't be used with additional targets")
+	}
+
+	rollout := analysis.PrimaryRollout
+	isZero := func(v *intstr.IntOrString) bool {
+		return v != nil && (v.Type == intstr.Int && v.IntVal == 0 || v.Type == intstr.String && v.StrVal == "0%")
+	}
+	if isZero(rollout.MaxSurge) && isZero(rollout.MaxUnavailable) {
+		return fmt.Errorf("primary rollout maxSurge and maxUnavailable can't both be zero")
+	}
+	if d := rollout.ProgressDeadlineSeconds; d != nil && *d < 1 {
+		return fmt.Errorf("primary rollout progress deadline %d must be at least one second", *d)
+	}
+	return nil
+}
+
+func verifyRevertOnFailure(canary *flaggerv1.Canary) error {
+	if !canary.Spec.RevertOnFailure {
+		return nil
+	}
+
+	switch kind := canary.Spec.TargetRef.Kind; kind {
+	case "Deployment", "DaemonSet", "StatefulSet":
+	default:
+		return fmt.Errorf("revert on failure is not supported for %s targets", kind)
+	}
+	if len(canary.Spec.AdditionalTargetRefs) > 0 {
+		return fmt.Errorf("revert on failure can't be used with additional targets")
+	}
+	if canary.GetRevisionHistoryLimit() == 0 {
+		return fmt.Errorf("revert on failure requires a revision history limit greater than zero")
+	}
+	return nil
+}
+
+func verifyIgnoreChanges(cd *flaggerv1.Canary) error {
+	if cd.Spec.IgnoreChanges == nil {
+		return nil
+	}
+
+	switch kind := cd.Spec.TargetRef.Kind; kind {
+	case "Deployment", "DaemonSet", "StatefulSet":
+	default:
+		return fmt.Errorf("ignore changes is not supported for %s targets", kind)
+	}
+	if len(cd.Spec.AdditionalTargetRefs) > 0 {
+		return fmt.Errorf("ignore changes can't be used with additional targets")
+	}
+	if cd.GetRevisionHistoryLimit() == 0 {
+		return fmt.Errorf("ignore changes requires a revision history limit greater than zero")
+	}
+	return canary.VerifyIgnoreChanges(cd.Spec.IgnoreChanges)
+}
+
+func verifyFastTrack(cd *flaggerv1.Canary) error {
+	if cd.GetAnalysis() == nil || cd.GetAnalysis().FastTrack == nil {
+		return nil
+	}
+	fastTrack := cd.GetAnalysis().FastTrack
+
+	switch kind := cd.Spec.TargetRef.Kind; kind {
+	case "Deployment", "DaemonSet", "StatefulSet":
+	default:
+		return fmt.Errorf("fast-track is not supported for %s targets", kind)
+	}
+	if len(cd.Spec.AdditionalTargetRefs) > 0 {
+		return fmt.Errorf("fast-track can't be used with additional targets")
+	}
+	if cd.GetRevisionHistoryLimit() == 0 {
+		return fmt.Errorf("fast-track requires a revision history limit greater than zero")
+	}
+
+	for _, class := range []flaggerv1.ChangeClass{flaggerv1.ChangeConfigOnly, flaggerv1.ChangeImageTagPatch,
+		flaggerv1.ChangeResourcesOnly, flaggerv1.ChangeOther} {
+		switch profile := cd.GetFastTrackProfile(class); profile {
+		case flaggerv1.AnalysisProfileSkip, flaggerv1.AnalysisProfileFull:
+		case flaggerv1.AnalysisProfileShort:
+			if fastTrack.Short == nil {
+				return fmt.Errorf("fast-track %s profile of %s changes requires a short schedule", profile, class)
+			}
+		default:
+			return fmt.Errorf("fast-track %s profile of %s changes is not supported", profile, class)
+		}
+	}
+
+	if fastTrack.Short != nil && fastTrack.Short.Interval != "" {
+		if _, err := time.ParseDuration(fastTrack.Short.Interval); err != nil {
+			return fmt.Errorf("fast-track short interval %s is invalid: %w", fastTrack.Short.Interval, err)
+		}
+	}
+	return nil
+}
+
+func verifyWarmup(cd *flaggerv1.Canary) error {
+	if cd.GetAnalysis() == nil || cd.GetAnalysis().Warmup == nil {
+		return nil
+	}
+	analysis := cd.GetAnalysis()
+	warmup := analysis.Warmup
+
+	if (warmup.Duration == "") == (warmup.Iterations == 0) {
+		return fmt.Errorf("warm-up requires either a duration or iterations")
+	}
+	if warmup.Iterations < 0 {
+		return fmt.Errorf("warm-up iterations %d can't be negative", warmup.Iterations)
+	}
+	if warmup.Duration != "" {
+		if d, err := time.ParseDuration(warmup.Duration); err != nil || d <= 0 {
+			return fmt.Errorf("warm-up duration %s is invalid", warmup.Duration)
+		}
+	}
+
+	if warmup.Weight < 0 {
+		return fmt.Errorf("warm-up weight %d can't be negative", warmup.Weight)
+	}
+	if warmup.Weight > 0 {
+		if analysis.Iterations > 0 || len(analysis.StepWeights) > 0 || len(analysis.Steps) > 0 || analysis.StepWeight == 0 {
+			return fmt.Errorf("warm-up weight requires the step weight strategy")
+		}
+		if analysis.FastTrack != nil && analysis.FastTrack.Short != nil && len(analysis.FastTrack.Short.StepWeights) > 0 {
+			return fmt.Errorf("warm-up weight can't be used with the fast-track short step weights")
+		}
+		maxWeight := analysis.MaxWeight
+		if maxWeight == 0 {
+			maxWeight = 100
+		}
+		if warmup.Weight >= maxWeight {
+			return fmt.Errorf("warm-up weight %d must be lower than the max weight %d", warmup.Weight, maxWeight)
+		}
+	}
+	return nil
+}
+
 func checkCustomResourceType(obj interface{}, logger *zap.SugaredLogger) (flaggerv1.Canary, bool) {
 	var roll *flaggerv1.Canary
 	var ok bool
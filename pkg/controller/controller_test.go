@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	istiov1beta1 "github.com/fluxcd/flagger/pkg/apis/istio/v1beta1"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -186,6 +187,644 @@ func TestController_verifyCanary(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "metric comparison without max ratio or delta should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Metrics: []flaggerv1.CanaryMetric{
+							{
+								Name:       "request-duration",
+								Comparison: &flaggerv1.CanaryMetricComparison{},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "metric comparison with unknown direction should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Metrics: []flaggerv1.CanaryMetric{
+							{
+								Name: "request-duration",
+								Comparison: &flaggerv1.CanaryMetricComparison{
+									Direction: "sideways",
+									MaxRatio:  toFloatPtr(2),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "metric comparison with max ratio is okay",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Metrics: []flaggerv1.CanaryMetric{
+							{
+								Name: "request-duration",
+								Comparison: &flaggerv1.CanaryMetricComparison{
+									MaxRatio: toFloatPtr(2),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "builtin metric comparison with nginx should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Provider: flaggerv1.NGINXProvider,
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Metrics: []flaggerv1.CanaryMetric{
+							{
+								Name: "request-success-rate",
+								Comparison: &flaggerv1.CanaryMetricComparison{
+									MaxDelta: toFloatPtr(1),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "builtin metric comparison with gloo should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Provider: flaggerv1.GlooProvider,
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Metrics: []flaggerv1.CanaryMetric{
+							{
+								Name: "request-duration",
+								Comparison: &flaggerv1.CanaryMetricComparison{
+									MaxRatio: toFloatPtr(2),
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "scoring with marginal greater than pass should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Scoring: &flaggerv1.CanaryScoring{
+							Pass:     toFloatPtr(80),
+							Marginal: toFloatPtr(90),
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "scoring with defaults is okay",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Scoring: &flaggerv1.CanaryScoring{},
+						Metrics: []flaggerv1.CanaryMetric{
+							{Name: "latency", Query: "latency"},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "scoring with only builtin metrics should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Scoring: &flaggerv1.CanaryScoring{},
+						Metrics: []flaggerv1.CanaryMetric{
+							{Name: "request-success-rate", Threshold: 99},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "baseline with A/B testing should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Provider: "istio",
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "Deployment",
+						Name: "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Baseline: true,
+						Match:    []istiov1beta1.HTTPMatchRequest{{}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "baseline with a non istio provider should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Provider: "linkerd",
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "Deployment",
+						Name: "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Baseline: true,
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "baseline with istio and deployment is okay",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Provider: "istio",
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "Deployment",
+						Name: "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Baseline: true,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "steps with decreasing weights should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Steps: []flaggerv1.CanaryStep{
+							{Weight: 50},
+							{Weight: 5},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "steps with step weights should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						StepWeights: []int{5, 50},
+						Steps: []flaggerv1.CanaryStep{
+							{Weight: 5},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "steps with an invalid duration should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Steps: []flaggerv1.CanaryStep{
+							{Weight: 5, Duration: "forever"},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "steps with increasing weights are okay",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Steps: []flaggerv1.CanaryStep{
+							{Weight: 5, Duration: "30m"},
+							{Weight: 50, Iterations: 3, Pause: true},
+							{Weight: 100},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "post-promotion with a service target should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "Service",
+						Name: "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						PostPromotion: &flaggerv1.CanaryPostPromotion{Window: "30m"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "post-promotion with an invalid window should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "Deployment",
+						Name: "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						PostPromotion: &flaggerv1.CanaryPostPromotion{Window: "0s"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "additional target referencing the target should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "Deployment",
+						Name: "podinfo",
+					},
+					AdditionalTargetRefs: []flaggerv1.LocalObjectReference{
+						{Kind: "Deployment", Name: "podinfo"},
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "additional targets with a Service target should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "Service",
+						Name: "podinfo",
+					},
+					AdditionalTargetRefs: []flaggerv1.LocalObjectReference{
+						{Kind: "Deployment", Name: "worker"},
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "additional StatefulSet target with a StatefulSet target should pass",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						Kind: "StatefulSet",
+						Name: "gateway",
+					},
+					AdditionalTargetRefs: []flaggerv1.LocalObjectReference{
+						{Kind: "StatefulSet", Name: "gateway-cache"},
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "target paths with a custom resource target should pass",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps.kruise.io/v1alpha1",
+						Kind:       "CloneSet",
+						Name:       "podinfo",
+					},
+					TargetPaths: &flaggerv1.TargetPaths{
+						UpdatedReplicas: ".status.updatedReadyReplicas",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "target paths with a Deployment target should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					TargetPaths: &flaggerv1.TargetPaths{},
+					Analysis:    &flaggerv1.CanaryAnalysis{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "replica scaling with an autoscaler should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					AutoscalerRef: &flaggerv1.AutoscalerRefernce{
+						APIVersion: "autoscaling/v2",
+						Kind:       "HorizontalPodAutoscaler",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						ReplicaScaling: &flaggerv1.CanaryReplicaScaling{},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "replica scaling with a DaemonSet target should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "DaemonSet",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						ReplicaScaling: &flaggerv1.CanaryReplicaScaling{},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "primary rollout with a StatefulSet target should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "StatefulSet",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						PrimaryRollout: &flaggerv1.CanaryPrimaryRollout{},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "revert on failure without revision history should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					Analysis:             &flaggerv1.CanaryAnalysis{},
+					RevertOnFailure:      true,
+					RevisionHistoryLimit: int32p(0),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "ignore changes with an invalid path should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{},
+					IgnoreChanges: &flaggerv1.CanaryIgnoreChanges{
+						Paths: []string{".spec.containers[0].env"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "min samples without a min sample query should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Metrics: []flaggerv1.CanaryMetric{
+							{
+								Name:       "request-success-rate",
+								MinSamples: 100,
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "threshold failure policy without a failure threshold should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						Metrics: []flaggerv1.CanaryMetric{
+							{
+								Name:          "request-success-rate",
+								FailurePolicy: flaggerv1.MetricFailureThreshold,
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fast-track short profile without a short schedule should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						FastTrack: &flaggerv1.CanaryFastTrack{
+							ConfigOnly: flaggerv1.AnalysisProfileShort,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "warm-up weight with step weights should return an error",
+			canary: flaggerv1.Canary{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "cd-1",
+					Namespace: "default",
+				},
+				Spec: flaggerv1.CanarySpec{
+					TargetRef: flaggerv1.LocalObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "podinfo",
+					},
+					Analysis: &flaggerv1.CanaryAnalysis{
+						StepWeights: []int{10, 50},
+						Warmup: &flaggerv1.CanaryWarmup{
+							Duration: "5m",
+							Weight:   5,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	ctrl := &Controller{
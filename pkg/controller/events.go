@@ -47,6 +47,14 @@ func (c *Controller) recordEventWarningf(r *flaggerv1.Canary, template string, a
 	c.sendEventToWebhook(r, corev1.EventTypeWarning, template, args)
 }
 
+// recordEventInconclusivef records a warning with the Inconclusive reason
+// for the checks halted by too few metric samples
+func (c *Controller) recordEventInconclusivef(r *flaggerv1.Canary, template string, args ...interface{}) {
+	c.logger.With("canary", fmt.Sprintf("%s.%s", r.Name, r.Namespace)).Infof(template, args...)
+	c.eventRecorder.Event(r, corev1.EventTypeWarning, "Inconclusive", fmt.Sprintf(template, args...))
+	c.sendEventToWebhook(r, corev1.EventTypeWarning, template, args)
+}
+
 func (c *Controller) sendEventToWebhook(r *flaggerv1.Canary, eventType, template string, args []interface{}) {
 	webhookOverride := false
 	for _, canaryWebhook := range r.GetAnalysis().Webhooks {
@@ -199,6 +207,20 @@ func alertMetadata(canary *flaggerv1.Canary) []notifier.Field {
 			Name:  "Target",
 			Value: fmt.Sprintf("%s/%s.%s", canary.Spec.TargetRef.Kind, canary.Spec.TargetRef.Name, canary.Namespace),
 		},
+	)
+
+	if len(canary.Spec.AdditionalTargetRefs) > 0 {
+		var targets []string
+		for _, ref := range canary.Spec.AdditionalTargetRefs {
+			targets = append(targets, fmt.Sprintf("%s/%s.%s", ref.Kind, ref.Name, canary.Namespace))
+		}
+		fields = append(fields, notifier.Field{
+			Name:  "Additional targets",
+			Value: strings.Join(targets, ", "),
+		})
+	}
+
+	fields = append(fields,
 		notifier.Field{
 			Name:  "Failed checks threshold",
 			Value: fmt.Sprintf("%v", canary.GetAnalysisThreshold()),
@@ -216,6 +238,15 @@ func alertMetadata(canary *flaggerv1.Canary) []notifier.Field {
 				canary.GetAnalysis().StepWeight,
 				canary.GetAnalysis().MaxWeight),
 		})
+	} else if len(canary.GetAnalysis().Steps) > 0 {
+		var weights []string
+		for _, step := range canary.GetAnalysis().Steps {
+			weights = append(weights, fmt.Sprint(step.Weight))
+		}
+		fields = append(fields, notifier.Field{
+			Name:  "Traffic routing",
+			Value: fmt.Sprintf("Weight steps: %s", strings.Join(weights, ",")),
+		})
 	} else if len(canary.GetAnalysis().StepWeights) > 0 {
 		fields = append(fields, notifier.Field{
 			Name: "Traffic routing",
@@ -0,0 +1,313 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// releasePolicyKey is the config map key holding the release policies
+const releasePolicyKey = "policy.yaml"
+
+// releaseWindowLookahead is how far ahead the next release window is searched for
+const releaseWindowLookahead = 366 * 24 * time.Hour
+
+// releasePolicies is the content of the release policy config map
+type releasePolicies struct {
+	Policies []releasePolicy `json:"policies"`
+}
+
+// releasePolicy restricts when new canary runs can start for the selected canaries
+type releasePolicy struct {
+	// Name of the policy, used in the canary status and metrics
+	Name string `json:"name"`
+
+	// Namespaces of the selected canaries, all namespaces when empty
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// Selector matches the labels of the selected canaries, all canaries when empty
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// TimeZone of the windows schedule, defaults to UTC
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Windows when new canary runs are allowed, at any time when empty
+	// +optional
+	Windows []releaseWindow `json:"windows,omitempty"`
+
+	// Blackouts when new canary runs are not allowed
+	// +optional
+	Blackouts []releaseBlackout `json:"blackouts,omitempty"`
+
+	selector  labels.Selector
+	location  *time.Location
+	schedules []*cronSchedule
+}
+
+// releaseWindow opens at every schedule tick for the given duration
+type releaseWindow struct {
+	Schedule string          `json:"schedule"`
+	Duration metav1.Duration `json:"duration"`
+}
+
+// releaseBlackout is a freeze period
+type releaseBlackout struct {
+	Start  metav1.Time `json:"start"`
+	End    metav1.Time `json:"end"`
+	Reason string      `json:"reason,omitempty"`
+}
+
+// releasePolicyStore holds the release policies loaded from a config map
+type releasePolicyStore struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+
+	mu              sync.RWMutex
+	policies        []releasePolicy
+	resourceVersion string
+}
+
+func newReleasePolicyStore(kubeClient kubernetes.Interface, namespace string, name string) *releasePolicyStore {
+	return &releasePolicyStore{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+		name:       name,
+	}
+}
+
+// load reads the release policies from the config map, an invalid config map
+// leaves the previously loaded policies in place
+func (s *releasePolicyStore) load() error {
+	cm, err := s.kubeClient.CoreV1().ConfigMaps(s.namespace).Get(context.TODO(), s.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		s.mu.Lock()
+		s.policies, s.resourceVersion = nil, ""
+		s.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("configmap %s.%s get query failed: %w", s.name, s.namespace, err)
+	}
+
+	s.mu.RLock()
+	unchanged := cm.ResourceVersion != "" && cm.ResourceVersion == s.resourceVersion
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	policies, err := parseReleasePolicies(cm)
+	if err != nil {
+		return fmt.Errorf("configmap %s.%s is invalid: %w", s.name, s.namespace, err)
+	}
+
+	s.mu.Lock()
+	s.policies, s.resourceVersion = policies, cm.ResourceVersion
+	s.mu.Unlock()
+	return nil
+}
+
+func parseReleasePolicies(cm *corev1.ConfigMap) ([]releasePolicy, error) {
+	data, ok := cm.Data[releasePolicyKey]
+	if !ok {
+		return nil, fmt.Errorf("%s key not found", releasePolicyKey)
+	}
+
+	var spec releasePolicies
+	if err := yaml.Unmarshal([]byte(data), &spec); err != nil {
+		return nil, fmt.Errorf("%s decoding failed: %w", releasePolicyKey, err)
+	}
+
+	names := make(map[string]bool)
+	for i := range spec.Policies {
+		p := &spec.Policies[i]
+		if p.Name == "" {
+			return nil, fmt.Errorf("policy %d has no name", i)
+		}
+		if names[p.Name] {
+			return nil, fmt.Errorf("policy %s is defined more than once", p.Name)
+		}
+		names[p.Name] = true
+
+		var err error
+		p.selector = labels.Everything()
+		if p.Selector != nil {
+			if p.selector, err = metav1.LabelSelectorAsSelector(p.Selector); err != nil {
+				return nil, fmt.Errorf("policy %s selector: %w", p.Name, err)
+			}
+		}
+
+		p.location = time.UTC
+		if p.TimeZone != "" {
+			if p.location, err = time.LoadLocation(p.TimeZone); err != nil {
+				return nil, fmt.Errorf("policy %s time zone: %w", p.Name, err)
+			}
+		}
+
+		for _, w := range p.Windows {
+			if w.Duration.Duration <= 0 {
+				return nil, fmt.Errorf("policy %s window %q must have a positive duration", p.Name, w.Schedule)
+			}
+			schedule, err := parseCron(w.Schedule)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s window: %w", p.Name, err)
+			}
+			p.schedules = append(p.schedules, schedule)
+		}
+
+		for _, b := range p.Blackouts {
+			if !b.End.After(b.Start.Time) {
+				return nil, fmt.Errorf("policy %s blackout %s must end after its start", p.Name, b.Start.Format(time.RFC3339))
+			}
+		}
+	}
+	return spec.Policies, nil
+}
+
+// matches returns true if the policy selects the canary
+func (p *releasePolicy) matches(cd *flaggerv1.Canary) bool {
+	if len(p.Namespaces) > 0 {
+		found := false
+		for _, ns := range p.Namespaces {
+			if ns == cd.Namespace {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return p.selector.Matches(labels.Set(cd.GetLabels()))
+}
+
+// frozen returns a message describing why new canary runs can't start at the given time
+func (p *releasePolicy) frozen(now time.Time) (string, bool) {
+	for _, b := range p.Blackouts {
+		if !now.Before(b.Start.Time) && now.Before(b.End.Time) {
+			reason := b.Reason
+			if reason == "" {
+				reason = "blackout"
+			}
+			return fmt.Sprintf("release frozen by policy %s (%s) until %s",
+				p.Name, reason, b.End.In(p.location).Format(time.RFC3339)), true
+		}
+	}
+
+	if len(p.Windows) == 0 {
+		return "", false
+	}
+
+	local := now.In(p.location)
+	var next time.Time
+	for i, w := range p.Windows {
+		// the window is open if the schedule fired within its duration
+		if start, ok := p.schedules[i].next(local.Add(-w.Duration.Duration), w.Duration.Duration); ok && !start.After(local) {
+			return "", false
+		}
+		if start, ok := p.schedules[i].next(local, releaseWindowLookahead); ok && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	if next.IsZero() {
+		return fmt.Sprintf("release frozen by policy %s, no release window in the next year", p.Name), true
+	}
+	return fmt.Sprintf("release frozen by policy %s, next window opens at %s",
+		p.Name, next.Format(time.RFC3339)), true
+}
+
+// check returns the reason why the canary can't start a new run at the given time
+func (s *releasePolicyStore) check(cd *flaggerv1.Canary, now time.Time) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := range s.policies {
+		if !s.policies[i].matches(cd) {
+			continue
+		}
+		if message, frozen := s.policies[i].frozen(now); frozen {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// state returns the freeze state of each policy at the given time
+func (s *releasePolicyStore) state(now time.Time) map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := make(map[string]bool, len(s.policies))
+	for i := range s.policies {
+		_, frozen := s.policies[i].frozen(now)
+		state[s.policies[i].Name] = frozen
+	}
+	return state
+}
+
+// syncReleasePolicies reloads the release policies and exports their freeze state
+func (c *Controller) syncReleasePolicies() {
+	if c.releasePolicies == nil {
+		return
+	}
+
+	if err := c.releasePolicies.load(); err != nil {
+		c.logger.Errorf("Release policies loading failed: %v", err)
+	}
+
+	c.recorder.SetReleaseFrozen(c.releasePolicies.state(time.Now()))
+}
+
+// checkReleasePolicies keeps the canary in the waiting phase while a release policy freezes it
+func (c *Controller) checkReleasePolicies(cd *flaggerv1.Canary) bool {
+	if c.releasePolicies == nil {
+		return true
+	}
+
+	message, frozen := c.releasePolicies.check(cd, time.Now())
+	if !frozen {
+		return true
+	}
+
+	if cd.Status.Phase != flaggerv1.CanaryPhaseWaiting {
+		c.recordEventWarningf(cd, "Halt %s.%s advancement %s", cd.Name, cd.Namespace, message)
+		c.alert(cd, fmt.Sprintf("Canary is waiting, %s.", message), false, flaggerv1.SeverityWarn)
+	}
+
+	if err := canary.SetStatusWaiting(c.flaggerClient, cd, message, 0); err != nil {
+		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Errorf("%v", err)
+	}
+	return false
+}
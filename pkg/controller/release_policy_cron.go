@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a standard five fields cron expression:
+// minute, hour, day of month, month and day of week
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// the day matches both the day of month and the day of week fields if one of them is a wildcard,
+	// otherwise it matches either of them
+	domWildcard, dowWildcard bool
+}
+
+var (
+	cronMonthNames = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	// 7 is an alias for Sunday, it's accepted as a value but wildcards and steps stop at Saturday
+	cronDayNames = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6, "7": 7}
+)
+
+// parseCron parses a cron expression such as "0 9 * * MON-FRI"
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have five fields", expr)
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron expression %q minute: %w", expr, err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron expression %q hour: %w", expr, err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("cron expression %q day of month: %w", expr, err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("cron expression %q month: %w", expr, err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 6, cronDayNames); err != nil {
+		return nil, fmt.Errorf("cron expression %q day of week: %w", expr, err)
+	}
+	// fold the Sunday alias
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domWildcard = fields[2] == "*" || fields[2] == "?"
+	s.dowWildcard = fields[4] == "*" || fields[4] == "?"
+	return &s, nil
+}
+
+// parseCronField returns the bitset of the values matched by a comma separated list
+// of values, ranges and steps such as "1,5-10,*/15"
+func parseCronField(field string, min int, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = parseCronValue(bounds[0], min, max, names); err != nil {
+				return 0, err
+			}
+			if end, err = parseCronValue(bounds[1], min, max, names); err != nil {
+				return 0, err
+			}
+			if start > end {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(part, min, max, names); err != nil {
+				return 0, err
+			}
+			// a single value with a step runs until the max value
+			end = start
+			if step > 1 {
+				end = max
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	if bits == 0 {
+		return 0, fmt.Errorf("%q matches no values", field)
+	}
+	return bits, nil
+}
+
+func parseCronValue(value string, min int, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("invalid value %q, must be in the range %d-%d", value, min, max)
+	}
+	return v, nil
+}
+
+// matches returns true if the schedule fires at the minute of the given time
+func (s *cronSchedule) matches(t time.Time) bool {
+	if s.minute&(1<<uint(t.Minute())) == 0 ||
+		s.hour&(1<<uint(t.Hour())) == 0 ||
+		s.month&(1<<uint(t.Month())) == 0 {
+		return false
+	}
+
+	return s.matchesDay(t)
+}
+
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domWildcard || s.dowWildcard {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// next returns the first time the schedule fires after the given time, within the limit.
+// The schedule follows the wall clock of the time's location: a time skipped by a daylight
+// saving transition is shifted forward by the length of the transition and a repeated time fires once.
+func (s *cronSchedule) next(after time.Time, limit time.Duration) (time.Time, bool) {
+	end := after.Add(limit)
+
+	// walk the wall clock in UTC where every day has 24 hours
+	w := cronWallClock(after).Add(time.Minute)
+	last := cronWallClock(end).Add(24 * time.Hour)
+	for !w.After(last) {
+		switch {
+		case s.month&(1<<uint(w.Month())) == 0:
+			w = time.Date(w.Year(), w.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		case !s.matchesDay(w):
+			w = time.Date(w.Year(), w.Month(), w.Day()+1, 0, 0, 0, 0, time.UTC)
+		case s.hour&(1<<uint(w.Hour())) == 0:
+			w = w.Truncate(time.Hour).Add(time.Hour)
+		case s.minute&(1<<uint(w.Minute())) == 0:
+			w = w.Add(time.Minute)
+		default:
+			t := time.Date(w.Year(), w.Month(), w.Day(), w.Hour(), w.Minute(), 0, 0, after.Location())
+			// the wall clock doesn't exist if it was skipped by a transition
+			if skipped := w.Sub(cronWallClock(t)); skipped > 0 {
+				t = t.Add(skipped)
+			}
+			if t.After(end) {
+				return time.Time{}, false
+			}
+			if t.After(after) {
+				return t, true
+			}
+			w = w.Add(time.Minute)
+		}
+	}
+	return time.Time{}, false
+}
+
+// cronWallClock returns the wall clock of the given time, to the minute, in UTC
+func cronWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
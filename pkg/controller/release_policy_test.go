@@ -0,0 +1,303 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestParseCron(t *testing.T) {
+	s, err := parseCron("30 9-17/2 * * MON-FRI")
+	require.NoError(t, err)
+
+	// Monday 2024-01-01 09:30 UTC
+	monday := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	assert.True(t, s.matches(monday))
+	assert.True(t, s.matches(monday.Add(2*time.Hour)))
+	assert.False(t, s.matches(monday.Add(time.Hour)))
+	assert.False(t, s.matches(monday.Add(time.Minute)))
+	assert.False(t, s.matches(monday.AddDate(0, 0, 5)))
+
+	next, ok := s.next(monday.Add(8*time.Hour), 7*24*time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC), next)
+
+	// day of month or day of week
+	s, err = parseCron("0 0 1 * sun")
+	require.NoError(t, err)
+	assert.True(t, s.matches(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, s.matches(time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, s.matches(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+
+	for _, expr := range []string{"* * * *", "60 * * * *", "* * * * MON-XYZ", "*/0 * * * *", "5-1 * * * *"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronSchedule_Matches(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		time  string
+		match bool
+	}{
+		// the day matches the day of month or the day of week when both are restricted
+		{"dom or dow by dom", "0 0 13 * FRI", "2026-01-13T00:00:00Z", true},
+		{"dom or dow by dow", "0 0 13 * FRI", "2026-01-16T00:00:00Z", true},
+		{"dom or dow by both", "0 0 13 * FRI", "2026-02-13T00:00:00Z", true},
+		{"dom or dow by none", "0 0 13 * FRI", "2026-01-14T00:00:00Z", false},
+		{"dom and any dow", "0 0 13 * *", "2026-01-16T00:00:00Z", false},
+		{"any dom and dow", "0 0 * * FRI", "2026-01-13T00:00:00Z", false},
+		{"no specific dom and dow", "0 0 ? * FRI", "2026-01-16T00:00:00Z", true},
+
+		// 7 is Sunday
+		{"7 on sunday", "0 0 * * 7", "2026-01-18T00:00:00Z", true},
+		{"7 on saturday", "0 0 * * 7", "2026-01-17T00:00:00Z", false},
+		{"range to 7 on sunday", "0 0 * * 5-7", "2026-01-18T00:00:00Z", true},
+		{"range to 7 on monday", "0 0 * * 5-7", "2026-01-19T00:00:00Z", false},
+		{"stepped range to 7 on sunday", "0 0 * * 1-7/2", "2026-01-18T00:00:00Z", true},
+		{"stepped value on sunday", "0 0 * * 1/2", "2026-01-18T00:00:00Z", false},
+		{"stepped value on friday", "0 0 * * 1/2", "2026-01-23T00:00:00Z", true},
+
+		// steps
+		{"stepped wildcard", "*/15 * * * *", "2026-01-01T00:45:00Z", true},
+		{"stepped wildcard off step", "*/15 * * * *", "2026-01-01T00:50:00Z", false},
+		{"stepped value", "5/20 * * * *", "2026-01-01T00:45:00Z", true},
+		{"stepped value before start", "5/20 * * * *", "2026-01-01T00:00:00Z", false},
+		{"stepped range", "10-20/5 * * * *", "2026-01-01T00:20:00Z", true},
+		{"stepped range off step", "10-20/5 * * * *", "2026-01-01T00:12:00Z", false},
+		{"stepped range after end", "10-20/5 * * * *", "2026-01-01T00:25:00Z", false},
+		{"stepped hours", "0 9-17/4 * * *", "2026-01-01T17:00:00Z", true},
+		{"stepped hours off step", "0 9-17/4 * * *", "2026-01-01T11:00:00Z", false},
+		{"stepped days", "0 0 1-31/10 * *", "2026-01-21T00:00:00Z", true},
+		{"stepped month names", "0 0 1 jan-mar/2 *", "2026-03-01T00:00:00Z", true},
+		{"stepped month names off step", "0 0 1 jan-mar/2 *", "2026-02-01T00:00:00Z", false},
+		{"list of steps", "0,30-59/15 * * * *", "2026-01-01T00:45:00Z", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			require.NoError(t, err)
+			at, err := time.Parse(time.RFC3339, tt.time)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, s.matches(at))
+		})
+	}
+
+	for _, expr := range []string{"* * * * 8", "* * * * 7/2", "* * 0 * *", "* * * 13 *", "1-2-3 * * * *"} {
+		_, err := parseCron(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		expr  string
+		after string
+		next  string
+	}{
+		{"dom or dow", "0 0 13 * FRI", "2026-01-13T00:00:00-05:00", "2026-01-16T00:00:00-05:00"},
+		{"month rollover", "0 0 1 * *", "2026-01-31T12:00:00-05:00", "2026-02-01T00:00:00-05:00"},
+		{"no match within the limit", "0 0 30 2 *", "2026-01-01T00:00:00-05:00", ""},
+
+		// the clock jumps from 02:00 EST to 03:00 EDT on 2026-03-08
+		{"time skipped by the spring transition", "30 2 * * *", "2026-03-08T00:00:00-05:00", "2026-03-08T03:30:00-04:00"},
+		{"hourly across the spring transition", "0 * * * *", "2026-03-08T01:30:00-05:00", "2026-03-08T03:00:00-04:00"},
+		{"hourly after the spring transition", "0 * * * *", "2026-03-08T03:00:00-04:00", "2026-03-08T04:00:00-04:00"},
+		{"daily after the spring transition", "30 2 * * *", "2026-03-08T03:30:00-04:00", "2026-03-09T02:30:00-04:00"},
+
+		// the clock goes back from 02:00 EDT to 01:00 EST on 2026-11-01
+		{"time repeated by the fall transition", "30 1 * * *", "2026-11-01T00:00:00-04:00", "2026-11-01T01:30:00-04:00"},
+		{"time repeated by the fall transition fires once", "30 1 * * *", "2026-11-01T01:30:00-04:00", "2026-11-02T01:30:00-05:00"},
+		{"within the repeated hour", "30 1 * * *", "2026-11-01T01:10:00-05:00", "2026-11-02T01:30:00-05:00"},
+		{"hourly across the fall transition", "0 * * * *", "2026-11-01T01:00:00-04:00", "2026-11-01T02:00:00-05:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			require.NoError(t, err)
+			after, err := time.Parse(time.RFC3339, tt.after)
+			require.NoError(t, err)
+
+			next, ok := s.next(after.In(newYork), 60*24*time.Hour)
+			if tt.next == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.next, next.Format(time.RFC3339))
+		})
+	}
+}
+
+func TestReleasePolicyStore_Check(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(newReleasePolicyConfigMap(`
+policies:
+  - name: business-hours
+    namespaces: ["default"]
+    timeZone: Europe/Bucharest
+    windows:
+      - schedule: "0 9 * * MON-FRI"
+        duration: 8h
+  - name: holidays
+    selector:
+      matchLabels:
+        tier: frontend
+    blackouts:
+      - start: "2024-12-20T00:00:00Z"
+        end: "2025-01-06T00:00:00Z"
+        reason: end of year freeze
+`))
+	store := newReleasePolicyStore(kubeClient, "flagger-system", "release-policy")
+	require.NoError(t, store.load())
+
+	cd := &flaggerv1.Canary{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"}}
+	bucharest, err := time.LoadLocation("Europe/Bucharest")
+	require.NoError(t, err)
+
+	// Tuesday 10:00 local time is within the window
+	_, frozen := store.check(cd, time.Date(2024, 1, 2, 10, 0, 0, 0, bucharest))
+	assert.False(t, frozen)
+
+	// Tuesday 17:00 local time is the end of the window
+	message, frozen := store.check(cd, time.Date(2024, 1, 2, 17, 0, 0, 0, bucharest))
+	assert.True(t, frozen)
+	assert.Equal(t, "release frozen by policy business-hours, next window opens at 2024-01-03T09:00:00+02:00", message)
+
+	// Saturday waits for Monday
+	message, frozen = store.check(cd, time.Date(2024, 1, 6, 12, 0, 0, 0, bucharest))
+	assert.True(t, frozen)
+	assert.Contains(t, message, "2024-01-08T09:00:00+02:00")
+
+	// other namespaces are not selected
+	other := &flaggerv1.Canary{ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "test"}}
+	_, frozen = store.check(other, time.Date(2024, 1, 6, 12, 0, 0, 0, bucharest))
+	assert.False(t, frozen)
+
+	// blackouts apply to the labeled canaries
+	other.Labels = map[string]string{"tier": "frontend"}
+	message, frozen = store.check(other, time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC))
+	assert.True(t, frozen)
+	assert.Equal(t, "release frozen by policy holidays (end of year freeze) until 2025-01-06T00:00:00Z", message)
+	_, frozen = store.check(other, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC))
+	assert.False(t, frozen)
+
+	state := store.state(time.Date(2024, 12, 24, 12, 0, 0, 0, time.UTC))
+	assert.Equal(t, map[string]bool{"business-hours": false, "holidays": true}, state)
+}
+
+func TestReleasePolicyStore_Load(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	store := newReleasePolicyStore(kubeClient, "flagger-system", "release-policy")
+
+	// a missing config map holds no canary
+	require.NoError(t, store.load())
+	assert.Empty(t, store.state(time.Now()))
+
+	cm := newReleasePolicyConfigMap(`
+policies:
+  - name: weekdays
+    windows:
+      - schedule: "0 0 * * 1-5"
+        duration: 24h
+`)
+	cm.ResourceVersion = "1"
+	_, err := kubeClient.CoreV1().ConfigMaps("flagger-system").Create(context.TODO(), cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, store.load())
+	assert.Contains(t, store.state(time.Now()), "weekdays")
+
+	// an invalid config map keeps the previous policies
+	cm.ResourceVersion = "2"
+	cm.Data[releasePolicyKey] = `
+policies:
+  - name: broken
+    windows:
+      - schedule: "0 25 * * *"
+        duration: 1h
+`
+	_, err = kubeClient.CoreV1().ConfigMaps("flagger-system").Update(context.TODO(), cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Error(t, store.load())
+	assert.Contains(t, store.state(time.Now()), "weekdays")
+}
+
+func TestScheduler_DeploymentReleaseFreeze(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	mocks.initializeCanary(t)
+
+	// freeze all canaries
+	cm := newReleasePolicyConfigMap(`
+policies:
+  - name: freeze
+    blackouts:
+      - start: "2000-01-01T00:00:00Z"
+        end: "2100-01-01T00:00:00Z"
+        reason: incident
+`)
+	_, err := mocks.kubeClient.CoreV1().ConfigMaps(cm.Namespace).Create(context.TODO(), cm, metav1.CreateOptions{})
+	require.NoError(t, err)
+	mocks.ctrl.releasePolicies = newReleasePolicyStore(mocks.kubeClient, cm.Namespace, cm.Name)
+	mocks.ctrl.syncReleasePolicies()
+
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// hold the new revision
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseWaiting, c.Status.Phase)
+	require.Len(t, c.Status.Conditions, 1)
+	assert.Equal(t, "release frozen by policy freeze (incident) until 2100-01-01T00:00:00Z", c.Status.Conditions[0].Message)
+
+	// lift the freeze
+	require.NoError(t, mocks.kubeClient.CoreV1().ConfigMaps(cm.Namespace).Delete(context.TODO(), cm.Name, metav1.DeleteOptions{}))
+	mocks.ctrl.syncReleasePolicies()
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseProgressing, c.Status.Phase)
+}
+
+func newReleasePolicyConfigMap(policy string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "release-policy", Namespace: "flagger-system"},
+		Data:       map[string]string{releasePolicyKey: policy},
+	}
+}
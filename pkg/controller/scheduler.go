@@ -38,9 +38,9 @@ func (c *Controller) min(a int, b int) int {
 }
 
 func (c *Controller) maxWeight(canary *flaggerv1.Canary) int {
-	var stepWeightsLen = len(canary.GetAnalysis().StepWeights)
-	if stepWeightsLen > 0 {
-		return c.min(c.totalWeight(canary), canary.GetAnalysis().StepWeights[stepWeightsLen-1])
+	var stepWeights = c.stepWeights(canary)
+	if len(stepWeights) > 0 {
+		return c.min(c.totalWeight(canary), stepWeights[len(stepWeights)-1])
 	}
 	if canary.GetAnalysis().MaxWeight > 0 {
 		return canary.GetAnalysis().MaxWeight
@@ -54,8 +54,21 @@ func (c *Controller) totalWeight(canary *flaggerv1.Canary) int {
 	return 100
 }
 
+// stepWeights returns the weights of the analysis steps or the step weights list
+func (c *Controller) stepWeights(canary *flaggerv1.Canary) []int {
+	if len(canary.GetAnalysis().Steps) > 0 {
+		weights := make([]int, 0, len(canary.GetAnalysis().Steps))
+		for _, step := range canary.GetAnalysis().Steps {
+			weights = append(weights, step.Weight)
+		}
+		return weights
+	}
+	return canary.GetAnalysis().StepWeights
+}
+
 func (c *Controller) nextStepWeight(canary *flaggerv1.Canary, canaryWeight int) int {
-	var stepWeightsLen = len(canary.GetAnalysis().StepWeights)
+	var stepWeights = c.stepWeights(canary)
+	var stepWeightsLen = len(stepWeights)
 	if canary.GetAnalysis().StepWeight > 0 || stepWeightsLen == 0 {
 		return canary.GetAnalysis().StepWeight
 	}
@@ -73,22 +86,21 @@ func (c *Controller) nextStepWeight(canary *flaggerv1.Canary, canaryWeight int)
 
 	// initial step
 	if canaryWeight == 0 {
-		return c.min(maxStep, canary.GetAnalysis().StepWeights[0])
+		return c.min(maxStep, stepWeights[0])
 	}
 
 	// find the current step and return the difference in weight
 	for i := 0; i < stepWeightsLen-1; i++ {
-		if canary.GetAnalysis().StepWeights[i] == canaryWeight {
-			return c.min(maxStep, canary.GetAnalysis().StepWeights[i+1]-canaryWeight)
+		if stepWeights[i] == canaryWeight {
+			return c.min(maxStep, stepWeights[i+1]-canaryWeight)
 		}
 	}
 
 	return maxStep
 }
 
-// scheduleCanaries synchronises the canary map with the jobs map,
-// for new canaries new jobs are created and started
-// for the removed canaries the jobs are stopped and deleted
+// scheduleCanaries synchronises the canary map with the scheduler queue,
+// new canaries are added to the queue and the removed canaries are unscheduled
 func (c *Controller) scheduleCanaries() {
 	current := make(map[string]string)
 	stats := make(map[string]int)
@@ -100,25 +112,13 @@ func (c *Controller) scheduleCanaries() {
 		name := key.(string)
 		current[name] = fmt.Sprintf("%s.%s", cn.Spec.TargetRef.Name, cn.Namespace)
 
-		job, exists := c.jobs[name]
-		// schedule new job for existing job with different analysis interval or non-existing job
-		if (exists && job.GetCanaryAnalysisInterval() != cn.GetAnalysisInterval()) || !exists {
-			if exists {
-				job.Stop()
-			}
-
-			newJob := CanaryJob{
-				Name:             cn.Name,
-				Namespace:        cn.Namespace,
-				function:         c.advanceCanary,
-				done:             make(chan bool),
-				ticker:           time.NewTicker(cn.GetAnalysisInterval()),
-				analysisInterval: cn.GetAnalysisInterval(),
-			}
-
-			c.jobs[name] = newJob
-			newJob.Start()
+		// schedule new canaries or reschedule the ones with a different analysis interval,
+		// the interval of the fast-track profile depends on the latest status
+		interval := cn.GetAnalysisInterval()
+		if latest, err := c.getCanary(cn.Name, cn.Namespace); err == nil {
+			interval = latest.GetAnalysisInterval()
 		}
+		c.scheduler.schedule(name, cn.Name, cn.Namespace, interval)
 
 		// compute canaries per namespace total
 		t, ok := stats[cn.Namespace]
@@ -130,13 +130,13 @@ func (c *Controller) scheduleCanaries() {
 		return true
 	})
 
-	// cleanup deleted jobs
-	for job := range c.jobs {
-		if _, exists := current[job]; !exists {
-			c.jobs[job].Stop()
-			delete(c.jobs, job)
+	// cleanup deleted canaries
+	for _, name := range c.scheduler.keys() {
+		if _, exists := current[name]; !exists {
+			c.scheduler.unschedule(name)
 		}
 	}
+	c.recorder.SetQueueDepth(c.scheduler.len())
 
 	// check if multiple canaries have the same target
 	for canaryName, targetName := range current {
@@ -150,20 +150,23 @@ func (c *Controller) scheduleCanaries() {
 
 	// set total canaries per namespace metric
 	for k, v := range stats {
-		c.recorder.SetTotal(k, v)
+		c.recorder.SetTotal(k, c.shardKey, v)
 	}
 }
 
 func (c *Controller) advanceCanary(name string, namespace string) {
 	begin := time.Now()
 	// check if the canary exists
-	cd, err := c.flaggerClient.FlaggerV1beta1().Canaries(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	cd, err := c.getCanary(name, namespace)
 	if err != nil {
 		c.logger.With("canary", fmt.Sprintf("%s.%s", name, namespace)).
 			Errorf("Canary %s.%s not found", name, namespace)
 		return
 	}
 
+	// run the analysis with the schedule of the profile chosen for the revision
+	cd.ApplyFastTrack()
+
 	if cd.Spec.Suspend {
 		msg := "skipping canary run as object is suspended"
 		c.logger.With("canary", fmt.Sprintf("%s.%s", name, namespace)).
@@ -179,7 +182,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	}
 
 	// init controller based on target kind
-	canaryController := c.canaryFactory.Controller(cd.Spec.TargetRef)
+	canaryController := c.canaryFactory.CanaryController(cd)
 
 	labelSelector, labelValue, ports, err := canaryController.GetMetadata(cd)
 	if err != nil {
@@ -193,7 +196,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	}
 
 	// init Kubernetes router
-	kubeRouter := c.routerFactory.KubernetesRouter(cd.Spec.TargetRef.Kind, labelSelector, labelValue, ports)
+	kubeRouter := c.getKubernetesRouter(cd.Spec.TargetRef.Kind, labelSelector, labelValue, ports)
 
 	// reconcile the canary/primary services
 	if err := kubeRouter.Initialize(cd); err != nil {
@@ -209,7 +212,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	}
 
 	// init mesh router
-	meshRouter := c.routerFactory.MeshRouter(provider, labelSelector)
+	meshRouter := c.getMeshRouter(provider, labelSelector)
 
 	// register the AppMesh VirtualNodes before creating the primary deployment
 	// otherwise the pods will not be injected with the Envoy proxy
@@ -276,6 +279,12 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		return
 	}
 
+	// restore the primary to a promoted revision on demand
+	if revision, ok := cd.GetAnnotations()[flaggerv1.RollbackToAnnotation]; ok {
+		c.rollbackToRevision(cd, canaryController, revision)
+		return
+	}
+
 	// check for changes
 	shouldAdvance, err := c.shouldAdvance(cd, canaryController)
 	if err != nil {
@@ -284,6 +293,9 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 	}
 
 	if !shouldAdvance {
+		c.promoteIgnoredChanges(cd, canaryController)
+		c.discardManualActions(cd)
+		c.dequeueCanary(cd)
 		c.recorder.SetStatus(cd, cd.Status.Phase)
 		return
 	}
@@ -296,7 +308,16 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		if err != nil {
 			c.recordEventWarningf(cd, "%v", err)
 			if !retriable {
-				c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+				switch {
+				case cd.Status.Phase == flaggerv1.CanaryPhaseVerifying:
+					c.revertPrimary(cd, canaryController)
+				case cd.Status.Phase == flaggerv1.CanaryPhasePromoting && cd.GetAnalysis().PrimaryRollout != nil:
+					c.restorePrimary(cd, canaryController, meshRouter, provider)
+				case cd.Status.Phase == flaggerv1.CanaryPhaseRestoring:
+					// keep the traffic on the canary until the primary is restored
+				default:
+					c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+				}
 			}
 			return
 		}
@@ -316,10 +337,28 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		return
 	}
 
+	// check the primary metrics if the promotion has finished
+	if cd.Status.Phase == flaggerv1.CanaryPhaseVerifying {
+		c.runPostPromotionVerification(cd, canaryController)
+		return
+	}
+
+	// route the traffic back to the primary once it's restored to the previous revision
+	if cd.Status.Phase == flaggerv1.CanaryPhaseRestoring {
+		c.recordEventInfof(cd, "Primary %s-primary.%s restored, routing all traffic back to primary",
+			cd.Spec.TargetRef.Name, cd.Namespace)
+		c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+		return
+	}
+
 	// check if canary revision changed during analysis
 	if restart := c.hasCanaryRevisionChanged(cd, canaryController); restart {
-		c.recordEventInfof(cd, "New revision detected! Restarting analysis for %s.%s",
-			cd.Spec.TargetRef.Name, cd.Namespace)
+		fastTrack := c.selectAnalysisProfile(cd, canaryController)
+		msg := fmt.Sprintf("New revision detected! Restarting analysis for %s.%s", cd.Spec.TargetRef.Name, cd.Namespace)
+		if fastTrack != nil {
+			msg = fmt.Sprintf("%s. %s", msg, fastTrackMessage(fastTrack))
+		}
+		c.recordEventInfof(cd, "%s", msg)
 
 		// route all traffic back to primary
 		primaryWeight = c.totalWeight(cd)
@@ -335,6 +374,7 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 			CanaryWeight: 0,
 			FailedChecks: 0,
 			Iterations:   0,
+			FastTrack:    fastTrack,
 		}
 		if err := canaryController.SyncStatus(cd, status); err != nil {
 			c.recordEventWarningf(cd, "%v", err)
@@ -357,6 +397,14 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		return
 	}
 
+	// create the baseline or scale it to match the canary
+	if cd.HasBaseline() &&
+		(cd.Status.Phase == flaggerv1.CanaryPhaseProgressing || cd.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion) {
+		if ok := c.syncBaseline(cd, canaryController, meshRouter, scalerReconciler); !ok {
+			return
+		}
+	}
+
 	// check if we should rollback
 	if cd.Status.Phase == flaggerv1.CanaryPhaseProgressing ||
 		cd.Status.Phase == flaggerv1.CanaryPhaseWaiting ||
@@ -369,6 +417,22 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 		}
 	}
 
+	// check the canaries this one depends on
+	if cd.Status.Phase == flaggerv1.CanaryPhaseProgressing ||
+		cd.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion {
+		if ok := c.checkRunningDependencies(cd, canaryController, meshRouter, scalerReconciler); !ok {
+			return
+		}
+	}
+
+	// run the manual actions requested with annotations
+	if cd.Status.Phase == flaggerv1.CanaryPhaseProgressing ||
+		cd.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion {
+		if ok := c.runManualActions(cd, canaryController, meshRouter, scalerReconciler); !ok {
+			return
+		}
+	}
+
 	// route traffic back to primary if analysis has succeeded
 	if cd.Status.Phase == flaggerv1.CanaryPhasePromoting {
 		if scalerReconciler != nil {
@@ -377,6 +441,10 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 				return
 			}
 		}
+		// the primary is ready, stop serving its traffic from the canary pods
+		if ok := c.swapSelectorToPrimary(cd, canaryController); !ok {
+			return
+		}
 		c.runPromotionTrafficShift(cd, canaryController, meshRouter, provider, canaryWeight, primaryWeight)
 		return
 	}
@@ -393,23 +461,34 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 			c.recordEventWarningf(cd, "%v", err)
 			return
 		}
-
-		// set status to succeeded
-		if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhaseSucceeded); err != nil {
+		if err := c.deleteBaseline(cd, canaryController, meshRouter); err != nil {
 			c.recordEventWarningf(cd, "%v", err)
 			return
 		}
-		c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseSucceeded)
-		c.runPostRolloutHooks(cd, flaggerv1.CanaryPhaseSucceeded)
-		c.recordEventInfof(cd, "Promotion completed! Scaling down %s.%s", cd.Spec.TargetRef.Name, cd.Namespace)
-		c.alert(cd, "Canary analysis completed successfully, promotion finished.",
-			false, flaggerv1.SeverityInfo)
+
+		// verify the primary before setting the status to succeeded
+		if _, ok := canaryController.(canary.RevisionController); ok && cd.HasPostPromotion() {
+			if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhaseVerifying); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+				return
+			}
+			c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseVerifying)
+			c.recordEventInfof(cd, "Promotion completed! Scaling down %s.%s and verifying %s-primary.%s for %v",
+				cd.Spec.TargetRef.Name, cd.Namespace, cd.Spec.TargetRef.Name, cd.Namespace,
+				cd.GetAnalysis().PostPromotion.GetWindow())
+			return
+		}
+
+		if ok := c.setPhaseSucceeded(cd, canaryController); ok {
+			c.recordEventInfof(cd, "Promotion completed! Scaling down %s.%s", cd.Spec.TargetRef.Name, cd.Namespace)
+		}
 		return
 	}
 
-	// check if the number of failed checks reached the threshold
+	// check if the number of failed or inconclusive checks reached the threshold
+	_, metricThresholdReached := getMetricThresholdReached(cd)
 	if (cd.Status.Phase == flaggerv1.CanaryPhaseProgressing || cd.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion) &&
-		(!retriable || cd.Status.FailedChecks >= cd.GetAnalysisThreshold()) {
+		(!retriable || cd.Status.FailedChecks >= cd.GetAnalysisThreshold() || isInconclusiveLimitReached(cd) || metricThresholdReached) {
 		if !retriable {
 			c.recordEventWarningf(cd, "Rolling back %s.%s progress deadline exceeded %v",
 				cd.Name, cd.Namespace, err)
@@ -427,22 +506,38 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 
 	// check if the canary success rate is above the threshold
 	// skip check if no traffic is routed or mirrored to canary
-	if canaryWeight == 0 && cd.Status.Iterations == 0 &&
-		!(cd.GetAnalysis().Mirror && mirrored) {
-		c.recordEventInfof(cd, "Starting canary analysis for %s.%s", cd.Spec.TargetRef.Name, cd.Namespace)
-
-		// run pre-rollout web hooks
-		if ok := c.runPreRolloutHooks(cd); !ok {
-			if err := canaryController.SetStatusFailedChecks(cd, cd.Status.FailedChecks+1); err != nil {
-				c.recordEventWarningf(cd, "%v", err)
-			}
+	switch {
+	case isWarmingUp(cd):
+		// collect the metrics without judging them until the warm-up ends
+		if ok := c.runWarmup(cd); !ok {
 			return
 		}
-	} else {
-		if ok := c.runAnalysis(cd); !ok {
-			if err := canaryController.SetStatusFailedChecks(cd, cd.Status.FailedChecks+1); err != nil {
-				c.recordEventWarningf(cd, "%v", err)
+		if canaryWeight > 0 {
+			if ok := c.runAnalysisChecks(cd, canaryController, meshRouter, scalerReconciler); !ok {
+				return
 			}
+		}
+	case canaryWeight == 0 && cd.Status.Iterations == 0 &&
+		!(cd.GetAnalysis().Mirror && mirrored):
+		// the pre-rollout hooks ran before the warm-up
+		if getWarmupCondition(cd) == nil {
+			c.recordEventInfof(cd, "Starting canary analysis for %s.%s", cd.Spec.TargetRef.Name, cd.Namespace)
+
+			// run pre-rollout web hooks
+			if ok := c.runPreRolloutHooks(cd); !ok {
+				if err := canaryController.SetStatusFailedChecks(cd, cd.Status.FailedChecks+1); err != nil {
+					c.recordEventWarningf(cd, "%v", err)
+				}
+				return
+			}
+
+			if cd.GetAnalysis().Warmup != nil {
+				c.startWarmup(cd, canaryController, meshRouter)
+				return
+			}
+		}
+	default:
+		if ok := c.runAnalysisChecks(cd, canaryController, meshRouter, scalerReconciler); !ok {
 			return
 		}
 	}
@@ -488,6 +583,30 @@ func (c *Controller) advanceCanary(name string, namespace string) {
 
 }
 
+// runAnalysisChecks runs the analysis and records the failed or inconclusive check,
+// it returns false if the canary should not advance
+func (c *Controller) runAnalysisChecks(cd *flaggerv1.Canary, canaryController canary.Controller,
+	meshRouter router.Interface, scalerReconciler canary.ScalerReconciler) bool {
+	switch c.runAnalysis(cd) {
+	case analysisHalted:
+		return false
+	case analysisFailed:
+		if err := canaryController.SetStatusFailedChecks(cd, cd.Status.FailedChecks+1); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+		return false
+	case analysisInconclusive:
+		if err := canary.SetStatusInconclusiveChecks(c.flaggerClient, cd, cd.Status.InconclusiveChecks+1); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+		return false
+	case analysisAborted:
+		c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+		return false
+	}
+	return true
+}
+
 func (c *Controller) runPromotionTrafficShift(canary *flaggerv1.Canary, canaryController canary.Controller,
 	meshRouter router.Interface, provider string, canaryWeight int, primaryWeight int) {
 	// finalize promotion since no traffic shifting is possible for Kubernetes CNI
@@ -549,6 +668,11 @@ func (c *Controller) runCanary(canary *flaggerv1.Canary, canaryController canary
 	meshRouter router.Interface, mirrored bool, canaryWeight int, primaryWeight int, maxWeight int) {
 	primaryName := fmt.Sprintf("%s-primary", canary.Spec.TargetRef.Name)
 
+	// hold the weight until the current step is completed
+	if !c.runStep(canary, canaryController) {
+		return
+	}
+
 	// increase traffic weight
 	if canaryWeight < maxWeight {
 		// If in "mirror" mode, do one step of mirroring before shifting traffic to canary.
@@ -581,6 +705,29 @@ func (c *Controller) runCanary(canary *flaggerv1.Canary, canaryController canary
 			}
 		}
 
+		// size the canary for the next weight and wait for the new replicas before shifting the traffic
+		if rc, ok := replicaController(canary, canaryController); ok {
+			weight := canaryWeight
+			if mirrored {
+				weight = c.totalWeight(canary)
+				if mw := canary.GetAnalysis().MirrorWeight; mw > 0 {
+					weight = mw
+				}
+			}
+			scaledUp, err := c.scaleCanaryForWeight(canary, rc, weight)
+			if err != nil {
+				c.recordEventWarningf(canary, "%v", err)
+				return
+			}
+			if scaledUp {
+				if _, err := canaryController.IsCanaryReady(canary); err != nil {
+					c.recordEventInfof(canary, "Waiting for %s.%s canary replicas to be ready before advancing the weight to %v",
+						canary.Name, canary.Namespace, weight)
+					return
+				}
+			}
+		}
+
 		if err := meshRouter.SetRoutes(canary, primaryWeight, canaryWeight, mirrored); err != nil {
 			c.recordEventWarningf(canary, "%v", err)
 			return
@@ -606,6 +753,10 @@ func (c *Controller) runCanary(canary *flaggerv1.Canary, canaryController canary
 		// update primary spec
 		c.recordEventInfof(canary, "Copying %s.%s template spec to %s.%s",
 			canary.Spec.TargetRef.Name, canary.Namespace, primaryName, canary.Namespace)
+		if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return
+		}
 		if err := canaryController.Promote(canary); err != nil {
 			c.recordEventWarningf(canary, "%v", err)
 			return
@@ -649,6 +800,10 @@ func (c *Controller) runAB(canary *flaggerv1.Canary, canaryController canary.Con
 	if canary.GetAnalysis().Iterations == canary.Status.Iterations {
 		c.recordEventInfof(canary, "Copying %s.%s template spec to %s.%s",
 			canary.Spec.TargetRef.Name, canary.Namespace, primaryName, canary.Namespace)
+		if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return
+		}
 		if err := canaryController.Promote(canary); err != nil {
 			c.recordEventWarningf(canary, "%v", err)
 			return
@@ -718,6 +873,10 @@ func (c *Controller) runBlueGreen(canary *flaggerv1.Canary, canaryController can
 	if canary.GetAnalysis().Iterations < canary.Status.Iterations {
 		c.recordEventInfof(canary, "Copying %s.%s template spec to %s.%s",
 			canary.Spec.TargetRef.Name, canary.Namespace, primaryName, canary.Namespace)
+		if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return
+		}
 		if err := canaryController.Promote(canary); err != nil {
 			c.recordEventWarningf(canary, "%v", err)
 			return
@@ -732,7 +891,23 @@ func (c *Controller) runBlueGreen(canary *flaggerv1.Canary, canaryController can
 
 }
 
-func (c *Controller) runAnalysis(canary *flaggerv1.Canary) bool {
+// analysisResult is the outcome of an analysis run
+type analysisResult int
+
+const (
+	// analysisPassed allows the canary to advance
+	analysisPassed analysisResult = iota
+	// analysisHalted holds the advancement without counting a failed check
+	analysisHalted
+	// analysisFailed holds the advancement and counts a failed check
+	analysisFailed
+	// analysisInconclusive holds the advancement and counts an inconclusive check
+	analysisInconclusive
+	// analysisAborted rolls back the canary without waiting for the failed checks threshold
+	analysisAborted
+)
+
+func (c *Controller) runAnalysis(canary *flaggerv1.Canary) analysisResult {
 	// run external checks
 	for _, webhook := range canary.GetAnalysis().Webhooks {
 		if webhook.Type == "" || webhook.Type == flaggerv1.RolloutHook {
@@ -740,26 +915,30 @@ func (c *Controller) runAnalysis(canary *flaggerv1.Canary) bool {
 			if err != nil {
 				c.recordEventWarningf(canary, "Halt %s.%s advancement external check %s failed %v",
 					canary.Name, canary.Namespace, webhook.Name, err)
-				return false
+				return analysisFailed
 			}
 		}
 	}
 
-	ok := c.runBuiltinMetricChecks(canary)
-	if !ok {
-		return ok
+	// skip the metric checks if there are too few samples
+	if result := c.runSampleChecks(canary); result != analysisPassed {
+		return result
 	}
 
-	ok = c.runMetricChecks(canary)
-	if !ok {
-		return ok
+	result := c.runBuiltinMetricChecks(canary)
+
+	if canary.GetAnalysis().Scoring != nil {
+		if result != analysisPassed {
+			return result
+		}
+		return c.runMetricScoring(canary)
 	}
 
-	return true
+	return mergeAnalysisResults(result, c.runMetricChecks(canary))
 }
 
 func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryController canary.Controller, meshRouter router.Interface, scalerReconciler canary.ScalerReconciler, err error, retriable bool) bool {
-	if !canary.SkipAnalysis() {
+	if !canary.SkipAnalysis() && !canary.FastTrackSkipsAnalysis() {
 		return false
 	}
 
@@ -784,6 +963,10 @@ func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryControll
 	// copy spec and configs from canary to primary
 	c.recordEventInfof(canary, "Copying %s.%s template spec to %s-primary.%s",
 		canary.Spec.TargetRef.Name, canary.Namespace, canary.Spec.TargetRef.Name, canary.Namespace)
+	if err := c.restoreCanaryReplicas(canary, canaryController); err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return true
+	}
 	if err := canaryController.Promote(canary); err != nil {
 		c.recordEventWarningf(canary, "%v", err)
 		return true
@@ -805,6 +988,10 @@ func (c *Controller) shouldSkipAnalysis(canary *flaggerv1.Canary, canaryControll
 		c.recordEventWarningf(canary, "%v", err)
 		return true
 	}
+	if err := c.deleteBaseline(canary, canaryController, meshRouter); err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return true
+	}
 
 	// update status phase
 	if err := canaryController.SetStatusPhase(canary, flaggerv1.CanaryPhaseSucceeded); err != nil {
@@ -827,7 +1014,9 @@ func (c *Controller) shouldAdvance(canary *flaggerv1.Canary, canaryController ca
 		canary.Status.Phase == flaggerv1.CanaryPhaseWaiting ||
 		canary.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion ||
 		canary.Status.Phase == flaggerv1.CanaryPhasePromoting ||
-		canary.Status.Phase == flaggerv1.CanaryPhaseFinalising {
+		canary.Status.Phase == flaggerv1.CanaryPhaseRestoring ||
+		canary.Status.Phase == flaggerv1.CanaryPhaseFinalising ||
+		canary.Status.Phase == flaggerv1.CanaryPhaseVerifying {
 		return true, nil
 	}
 
@@ -858,44 +1047,80 @@ func (c *Controller) shouldAdvance(canary *flaggerv1.Canary, canaryController ca
 
 func (c *Controller) checkCanaryStatus(canary *flaggerv1.Canary, canaryController canary.Controller, scalerReconciler canary.ScalerReconciler, shouldAdvance bool) bool {
 	c.recorder.SetStatus(canary, canary.Status.Phase)
+	if canary.GetAnalysis().Warmup != nil {
+		c.recorder.SetWarmup(canary, isWarmingUp(canary))
+	}
 	if canary.Status.Phase == flaggerv1.CanaryPhaseProgressing ||
 		canary.Status.Phase == flaggerv1.CanaryPhaseWaitingPromotion ||
 		canary.Status.Phase == flaggerv1.CanaryPhasePromoting ||
-		canary.Status.Phase == flaggerv1.CanaryPhaseFinalising {
+		canary.Status.Phase == flaggerv1.CanaryPhaseRestoring ||
+		canary.Status.Phase == flaggerv1.CanaryPhaseFinalising ||
+		canary.Status.Phase == flaggerv1.CanaryPhaseVerifying {
 		return true
 	}
 
-	var err error
-	canary, err = c.flaggerClient.FlaggerV1beta1().Canaries(canary.Namespace).Get(context.TODO(), canary.Name, metav1.GetOptions{})
-	if err != nil {
-		c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).Errorf("%v", err)
-		return false
-	}
-
 	if shouldAdvance {
+		// check release windows and freezes
+		if ok := c.checkReleasePolicies(canary); !ok {
+			c.dequeueCanary(canary)
+			return false
+		}
+
+		// check the canaries this one depends on
+		if ok := c.checkDependencies(canary, canaryController); !ok {
+			c.dequeueCanary(canary)
+			return false
+		}
+
 		// check confirm-rollout gate
 		if isApproved := c.runConfirmRolloutHooks(canary, canaryController); !isApproved {
+			c.dequeueCanary(canary)
+			return false
+		}
+
+		// check the number of running canaries
+		if ok := c.checkConcurrency(canary); !ok {
 			return false
 		}
 
+		fastTrack := c.selectAnalysisProfile(canary, canaryController)
 		canaryPhaseProgressing := canary.DeepCopy()
 		canaryPhaseProgressing.Status.Phase = flaggerv1.CanaryPhaseProgressing
-		c.recordEventInfof(canaryPhaseProgressing, "New revision detected! Scaling up %s.%s", canaryPhaseProgressing.Spec.TargetRef.Name, canaryPhaseProgressing.Namespace)
-		c.alert(canaryPhaseProgressing, "New revision detected, progressing canary analysis.",
-			true, flaggerv1.SeverityInfo)
+		msg := fmt.Sprintf("New revision detected! Scaling up %s.%s", canaryPhaseProgressing.Spec.TargetRef.Name, canaryPhaseProgressing.Namespace)
+		alert := "New revision detected, progressing canary analysis."
+		if fastTrack != nil {
+			msg = fmt.Sprintf("%s. %s", msg, fastTrackMessage(fastTrack))
+			alert = fmt.Sprintf("%s %s", alert, fastTrackMessage(fastTrack))
+		}
+		c.recordEventInfof(canaryPhaseProgressing, "%s", msg)
+		c.alert(canaryPhaseProgressing, alert, true, flaggerv1.SeverityInfo)
 
 		if scalerReconciler != nil {
-			err = scalerReconciler.ResumeTargetScaler(canary)
-			if err != nil {
+			if err := scalerReconciler.ResumeTargetScaler(canary); err != nil {
 				c.recordEventWarningf(canary, "%v", err)
 				return false
 			}
 		}
-		if err := canaryController.ScaleFromZero(canary); err != nil {
+		status := flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing, FastTrack: fastTrack}
+		if rc, ok := replicaController(canary, canaryController); ok {
+			// record the replicas of the new revision to promote them
+			replicas, err := desiredCanaryReplicas(canary, rc)
+			if err != nil {
+				c.recordEventErrorf(canary, "%v", err)
+				return false
+			}
+			status.CanaryReplicas = &replicas
+
+			// start with the replicas sized for no traffic, they grow with the weight
+			if _, err := c.scaleCanaryForWeight(canary, rc, 0); err != nil {
+				c.recordEventErrorf(canary, "%v", err)
+				return false
+			}
+		} else if err := canaryController.ScaleFromZero(canary); err != nil {
 			c.recordEventErrorf(canary, "%v", err)
 			return false
 		}
-		if err := canaryController.SyncStatus(canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing}); err != nil {
+		if err := canaryController.SyncStatus(canary, status); err != nil {
 			c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, canary.Namespace)).Errorf("%v", err)
 			return false
 		}
@@ -926,6 +1151,18 @@ func (c *Controller) rollback(canary *flaggerv1.Canary, canaryController canary.
 		c.alert(canary, fmt.Sprintf("Failed checks threshold reached %v", canary.Status.FailedChecks),
 			false, flaggerv1.SeverityError)
 	}
+	if isInconclusiveLimitReached(canary) {
+		c.recordEventInconclusivef(canary, "Rolling back %s.%s inconclusive checks limit reached %v",
+			canary.Name, canary.Namespace, canary.Status.InconclusiveChecks)
+		c.alert(canary, fmt.Sprintf("Inconclusive checks limit reached %v", canary.Status.InconclusiveChecks),
+			false, flaggerv1.SeverityError)
+	}
+	if metric, ok := getMetricThresholdReached(canary); ok {
+		c.recordEventWarningf(canary, "Rolling back %s.%s metric %s failed checks threshold reached %v",
+			canary.Name, canary.Namespace, metric, canary.Status.MetricFailedChecks[metric])
+		c.alert(canary, fmt.Sprintf("Metric %s failed checks threshold reached %v", metric, canary.Status.MetricFailedChecks[metric]),
+			false, flaggerv1.SeverityError)
+	}
 
 	// route all traffic back to primary
 	primaryWeight := c.totalWeight(canary)
@@ -935,6 +1172,11 @@ func (c *Controller) rollback(canary *flaggerv1.Canary, canaryController canary.
 		return
 	}
 
+	// point the services back to the primary pods before the canary is scaled down
+	if ok := c.swapSelectorToPrimary(canary, canaryController); !ok {
+		return
+	}
+
 	canaryPhaseFailed := canary.DeepCopy()
 	canaryPhaseFailed.Status.Phase = flaggerv1.CanaryPhaseFailed
 	c.recordEventWarningf(canaryPhaseFailed, "Canary failed! Scaling down %s.%s",
@@ -953,6 +1195,10 @@ func (c *Controller) rollback(canary *flaggerv1.Canary, canaryController canary.
 		c.recordEventWarningf(canary, "%v", err)
 		return
 	}
+	if err := c.deleteBaseline(canary, canaryController, meshRouter); err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return
+	}
 
 	// mark canary as failed
 	if err := canaryController.SyncStatus(canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseFailed, CanaryWeight: 0}); err != nil {
@@ -960,26 +1206,141 @@ func (c *Controller) rollback(canary *flaggerv1.Canary, canaryController canary.
 		return
 	}
 
+	// restore the target so that the rejected spec isn't promoted by a later run
+	if canary.Spec.RevertOnFailure {
+		c.revertTarget(canary, canaryController)
+	}
+
 	c.recorder.SetStatus(canary, flaggerv1.CanaryPhaseFailed)
 	c.runPostRolloutHooks(canary, flaggerv1.CanaryPhaseFailed)
 }
 
+// syncBaseline creates the baseline workload or scales it to match the canary and
+// returns false if the advancement should be halted until the baseline is ready
+func (c *Controller) syncBaseline(cd *flaggerv1.Canary, canaryController canary.Controller,
+	meshRouter router.Interface, scalerReconciler canary.ScalerReconciler) bool {
+	baselineController, ok := canaryController.(canary.BaselineController)
+	if !ok {
+		c.recordEventWarningf(cd, "Baseline is not supported for %s targets", cd.Spec.TargetRef.Kind)
+		return false
+	}
+
+	if err := baselineController.SyncBaseline(cd); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+
+	baselineRouters, err := c.baselineRouters(cd, canaryController, meshRouter)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+	for _, baselineRouter := range baselineRouters {
+		if err := baselineRouter.ReconcileBaseline(cd); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return false
+		}
+	}
+
+	retriable, err := baselineController.IsBaselineReady(cd)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		if !retriable {
+			c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+		}
+		return false
+	}
+	return true
+}
+
+// deleteBaseline removes the baseline workload, service and destination rule
+// once the traffic is routed back to the primary
+func (c *Controller) deleteBaseline(cd *flaggerv1.Canary, canaryController canary.Controller, meshRouter router.Interface) error {
+	baselineController, ok := canaryController.(canary.BaselineController)
+	if !ok {
+		return nil
+	}
+	if err := baselineController.DeleteBaseline(cd); err != nil {
+		return err
+	}
+
+	baselineRouters, err := c.baselineRouters(cd, canaryController, meshRouter)
+	if err != nil {
+		return err
+	}
+	for _, baselineRouter := range baselineRouters {
+		if err := baselineRouter.FinalizeBaseline(cd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getKubernetesRouter returns the Kubernetes router of the target, the routers hold no state
+// and are built once for each target kind, pod selector and ports
+func (c *Controller) getKubernetesRouter(kind string, labelSelector string, labelValue string, ports map[string]int32) router.KubernetesRouter {
+	key := fmt.Sprintf("%s/%s/%s/%v", kind, labelSelector, labelValue, ports)
+	if kubeRouter, ok := c.kubeRouters.Load(key); ok {
+		return kubeRouter.(router.KubernetesRouter)
+	}
+	kubeRouter := c.routerFactory.KubernetesRouter(kind, labelSelector, labelValue, ports)
+	c.kubeRouters.Store(key, kubeRouter)
+	return kubeRouter
+}
+
+// getMeshRouter returns the mesh router of the provider, the routers hold no state
+// and are built once for each provider and pod selector
+func (c *Controller) getMeshRouter(provider string, labelSelector string) router.Interface {
+	key := fmt.Sprintf("%s/%s", provider, labelSelector)
+	if meshRouter, ok := c.meshRouters.Load(key); ok {
+		return meshRouter.(router.Interface)
+	}
+	meshRouter := c.routerFactory.MeshRouter(provider, labelSelector)
+	c.meshRouters.Store(key, meshRouter)
+	return meshRouter
+}
+
+// baselineRouters returns the Kubernetes and mesh routers that manage the baseline routing objects
+func (c *Controller) baselineRouters(cd *flaggerv1.Canary, canaryController canary.Controller,
+	meshRouter router.Interface) ([]router.BaselineRouter, error) {
+	labelSelector, labelValue, ports, err := canaryController.GetMetadata(cd)
+	if err != nil {
+		return nil, err
+	}
+
+	var routers []router.BaselineRouter
+	kubeRouter := c.getKubernetesRouter(cd.Spec.TargetRef.Kind, labelSelector, labelValue, ports)
+	if baselineRouter, ok := kubeRouter.(router.BaselineRouter); ok {
+		routers = append(routers, baselineRouter)
+	}
+	if baselineRouter, ok := meshRouter.(router.BaselineRouter); ok {
+		routers = append(routers, baselineRouter)
+	}
+	return routers, nil
+}
+
+// setPhaseSucceeded sets the status to succeeded once the promotion is finished
+func (c *Controller) setPhaseSucceeded(cd *flaggerv1.Canary, canaryController canary.Controller) bool {
+	if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhaseSucceeded); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+	c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseSucceeded)
+	c.runPostRolloutHooks(cd, flaggerv1.CanaryPhaseSucceeded)
+	c.alert(cd, "Canary analysis completed successfully, promotion finished.",
+		false, flaggerv1.SeverityInfo)
+	return true
+}
+
 func (c *Controller) setPhaseInitialized(cd *flaggerv1.Canary, canaryController canary.Controller) error {
 	if cd.Status.Phase == "" || cd.Status.Phase == flaggerv1.CanaryPhaseInitializing {
 		cd.Status.Phase = flaggerv1.CanaryPhaseInitialized
+		// SyncStatus sets the LastAppliedSpec and TrackedConfigs of the `cd` Canary object as they
+		// are used later to determine whether target revision has changed in `shouldAdvance()`.
 		if err := canaryController.SyncStatus(cd, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseInitialized}); err != nil {
 			return fmt.Errorf("failed to sync canary %s.%s status: %w", cd.Name, cd.Namespace, err)
 		}
 
-		canary, err := c.flaggerClient.FlaggerV1beta1().Canaries(cd.Namespace).Get(context.TODO(), cd.Name, metav1.GetOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to get canary %s.%s: %w", cd.Name, cd.Namespace, err)
-		}
-		// We need to sync the LastAppliedSpec and TrackedConfigs of the `cd` Canary object as it
-		// is used later to determine whether target revision has changed in `shouldAdvance()`.
-		cd.Status.LastAppliedSpec = canary.Status.LastAppliedSpec
-		cd.Status.TrackedConfigs = canary.Status.TrackedConfigs
-
 		c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseInitialized)
 		c.recordEventInfof(cd, "Initialization done! %s.%s", cd.Name, cd.Namespace)
 		c.alert(cd, fmt.Sprintf("New %s detected, initialization completed.", cd.Spec.TargetRef.Kind),
@@ -0,0 +1,213 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// concurrencyStartTimeout is how long an admitted canary counts as running
+// before its progressing phase shows up in the canaries cache
+const concurrencyStartTimeout = time.Minute
+
+// concurrencyLimiter holds new canary runs in FIFO order while the number
+// of running canaries is at the global or namespace limit
+type concurrencyLimiter struct {
+	limit          int
+	namespaceLimit int
+
+	mu       sync.Mutex
+	waiting  map[string]*waitingCanary
+	starting map[string]*waitingCanary
+}
+
+type waitingCanary struct {
+	key       string
+	namespace string
+	since     time.Time
+}
+
+func newConcurrencyLimiter(limit int, namespaceLimit int) *concurrencyLimiter {
+	return &concurrencyLimiter{
+		limit:          limit,
+		namespaceLimit: namespaceLimit,
+		waiting:        make(map[string]*waitingCanary),
+		starting:       make(map[string]*waitingCanary),
+	}
+}
+
+// isCanaryRunning returns true if the canary is shifting traffic or promoting
+func isCanaryRunning(phase flaggerv1.CanaryPhase) bool {
+	return phase == flaggerv1.CanaryPhaseProgressing ||
+		phase == flaggerv1.CanaryPhaseWaitingPromotion ||
+		phase == flaggerv1.CanaryPhasePromoting ||
+		phase == flaggerv1.CanaryPhaseRestoring ||
+		phase == flaggerv1.CanaryPhaseFinalising
+}
+
+// admit queues the canary and returns zero if it can start a new run,
+// otherwise its position in the queue
+func (l *concurrencyLimiter) admit(cd *flaggerv1.Canary, canaries []*flaggerv1.Canary, now time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)
+	phases := make(map[string]flaggerv1.CanaryPhase, len(canaries))
+	running := 0
+	runningNamespace := make(map[string]int)
+	for _, item := range canaries {
+		itemKey := fmt.Sprintf("%s.%s", item.Name, item.Namespace)
+		phases[itemKey] = item.Status.Phase
+		if isCanaryRunning(item.Status.Phase) {
+			running++
+			runningNamespace[item.Namespace]++
+		}
+	}
+
+	// drop the deleted canaries and the ones no longer waiting
+	for k := range l.waiting {
+		if phase, ok := phases[k]; k != key && (!ok || phase != flaggerv1.CanaryPhaseWaiting) {
+			delete(l.waiting, k)
+		}
+	}
+
+	// count the admitted canaries until they show up as running
+	for k, s := range l.starting {
+		if phase, ok := phases[k]; !ok || isCanaryRunning(phase) || now.Sub(s.since) > concurrencyStartTimeout {
+			delete(l.starting, k)
+			continue
+		}
+		if k == key {
+			return 0
+		}
+		running++
+		runningNamespace[s.namespace]++
+	}
+
+	if _, ok := l.waiting[key]; !ok {
+		since := now
+		// keep the queue order across restarts
+		if cd.Status.Phase == flaggerv1.CanaryPhaseWaiting && cd.Status.QueuePosition > 0 {
+			since = cd.Status.LastTransitionTime.Time
+		}
+		l.waiting[key] = &waitingCanary{key: key, namespace: cd.Namespace, since: since}
+	}
+
+	queue := make([]*waitingCanary, 0, len(l.waiting))
+	for _, w := range l.waiting {
+		queue = append(queue, w)
+	}
+	sort.Slice(queue, func(i, j int) bool {
+		if queue[i].since.Equal(queue[j].since) {
+			return queue[i].key < queue[j].key
+		}
+		return queue[i].since.Before(queue[j].since)
+	})
+
+	// the canaries ahead in the queue take the free slots first
+	position := 0
+	for _, w := range queue {
+		if (l.limit == 0 || running < l.limit) &&
+			(l.namespaceLimit == 0 || runningNamespace[w.namespace] < l.namespaceLimit) {
+			if w.key == key {
+				delete(l.waiting, key)
+				l.starting[key] = &waitingCanary{key: key, namespace: w.namespace, since: now}
+				return 0
+			}
+			running++
+			runningNamespace[w.namespace]++
+			continue
+		}
+		position++
+		if w.key == key {
+			break
+		}
+	}
+	return position
+}
+
+// remove takes the canary out of the queue
+func (l *concurrencyLimiter) remove(cd *flaggerv1.Canary) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.waiting, fmt.Sprintf("%s.%s", cd.Name, cd.Namespace))
+}
+
+// checkConcurrency keeps the canary in the waiting phase while the concurrency limit is reached
+func (c *Controller) checkConcurrency(cd *flaggerv1.Canary) bool {
+	if c.concurrency == nil {
+		return true
+	}
+
+	canaries, err := c.listCanaries()
+	if err != nil {
+		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Errorf("%v", err)
+		return false
+	}
+
+	position := c.concurrency.admit(cd, canaries, time.Now())
+	if position == 0 {
+		return true
+	}
+
+	if cd.Status.Phase != flaggerv1.CanaryPhaseWaiting || cd.Status.QueuePosition == 0 {
+		c.recordEventWarningf(cd, "Halt %s.%s advancement concurrency limit reached, queue position %d",
+			cd.Name, cd.Namespace, position)
+	}
+
+	message := fmt.Sprintf("Concurrency limit reached, waiting in queue position %d.", position)
+	if err := canary.SetStatusWaiting(c.flaggerClient, cd, message, position); err != nil {
+		c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Errorf("%v", err)
+	}
+	return false
+}
+
+// dequeueCanary removes the canary from the concurrency queue when it no longer waits for a slot
+func (c *Controller) dequeueCanary(cd *flaggerv1.Canary) {
+	if c.concurrency == nil {
+		return
+	}
+	c.concurrency.remove(cd)
+}
+
+// listCanaries returns the canaries from the cache once it has synced, otherwise from the API
+func (c *Controller) listCanaries() ([]*flaggerv1.Canary, error) {
+	informer := c.flaggerInformers.CanaryInformer
+	if informer.Informer().HasSynced() {
+		return informer.Lister().List(labels.Everything())
+	}
+
+	list, err := c.flaggerClient.FlaggerV1beta1().Canaries(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("canaries list query failed: %w", err)
+	}
+	canaries := make([]*flaggerv1.Canary, 0, len(list.Items))
+	for i := range list.Items {
+		canaries = append(canaries, &list.Items[i])
+	}
+	return canaries, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestConcurrencyLimiter_Admit(t *testing.T) {
+	newCanary := func(name, namespace string, phase flaggerv1.CanaryPhase) *flaggerv1.Canary {
+		return &flaggerv1.Canary{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     flaggerv1.CanaryStatus{Phase: phase},
+		}
+	}
+
+	now := time.Now()
+	limiter := newConcurrencyLimiter(2, 1)
+	running := newCanary("running", "a", flaggerv1.CanaryPhaseProgressing)
+	first := newCanary("first", "a", flaggerv1.CanaryPhaseSucceeded)
+	second := newCanary("second", "b", flaggerv1.CanaryPhaseSucceeded)
+	third := newCanary("third", "c", flaggerv1.CanaryPhaseSucceeded)
+	canaries := []*flaggerv1.Canary{running, first, second, third}
+
+	// the namespace limit holds the first canary
+	assert.Equal(t, 1, limiter.admit(first, canaries, now))
+	first.Status.Phase = flaggerv1.CanaryPhaseWaiting
+
+	// other namespaces start until the global limit is reached
+	assert.Equal(t, 0, limiter.admit(second, canaries, now.Add(time.Second)))
+	assert.Equal(t, 2, limiter.admit(third, canaries, now.Add(2*time.Second)))
+	third.Status.Phase = flaggerv1.CanaryPhaseWaiting
+
+	// the admitted canary counts as running until its phase changes
+	assert.Equal(t, 0, limiter.admit(second, canaries, now.Add(3*time.Second)))
+	second.Status.Phase = flaggerv1.CanaryPhaseProgressing
+
+	// the first canary in the queue takes the free slot
+	running.Status.Phase = flaggerv1.CanaryPhaseSucceeded
+	assert.Equal(t, 1, limiter.admit(third, canaries, now.Add(4*time.Second)))
+	assert.Equal(t, 0, limiter.admit(first, canaries, now.Add(5*time.Second)))
+	first.Status.Phase = flaggerv1.CanaryPhaseProgressing
+
+	// removed canaries leave the queue
+	second.Status.Phase = flaggerv1.CanaryPhaseSucceeded
+	limiter.remove(third)
+	assert.Empty(t, limiter.waiting)
+}
+
+func TestScheduler_DeploymentConcurrencyLimit(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	mocks.initializeCanary(t)
+
+	// another canary is running in the namespace
+	other := newDeploymentTestCanary()
+	other.Name = "other"
+	other.Status.Phase = flaggerv1.CanaryPhaseProgressing
+	_, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Create(context.TODO(), other, metav1.CreateOptions{})
+	require.NoError(t, err)
+	mocks.ctrl.concurrency = newConcurrencyLimiter(0, 1)
+
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// hold the new revision
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseWaiting, c.Status.Phase)
+	assert.Equal(t, 1, c.Status.QueuePosition)
+
+	// start once the other canary has finished
+	other, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "other", metav1.GetOptions{})
+	require.NoError(t, err)
+	other.Status.Phase = flaggerv1.CanaryPhaseSucceeded
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), other, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseProgressing, c.Status.Phase)
+	assert.Equal(t, 0, c.Status.QueuePosition)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+	"github.com/fluxcd/flagger/pkg/router"
+)
+
+// dependencyState is the rollout state of a canary dependency
+type dependencyState int
+
+const (
+	dependencyReady dependencyState = iota
+	dependencyPending
+	dependencyFailed
+)
+
+// getDependencyState returns the rollout state of a dependency and a message describing it
+func (c *Controller) getDependencyState(cd *flaggerv1.Canary, ref flaggerv1.CrossNamespaceObjectReference) (dependencyState, string) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = cd.Namespace
+	}
+
+	dep, err := c.getCanary(ref.Name, namespace)
+	if errors.IsNotFound(err) {
+		return dependencyPending, fmt.Sprintf("Waiting for dependency %s.%s, canary not found.", ref.Name, namespace)
+	}
+	if err != nil {
+		return dependencyPending, fmt.Sprintf("Waiting for dependency %s.%s, %v.", ref.Name, namespace, err)
+	}
+
+	// the dependency state is read from the lister, a new revision of the dependency
+	// is picked up once its own analysis detects it and moves it out of these phases
+	switch dep.Status.Phase {
+	case flaggerv1.CanaryPhaseSucceeded, flaggerv1.CanaryPhaseInitialized:
+		// the dependency has a new revision that's not yet promoted
+		if dep.Status.LastAppliedSpec != dep.Status.LastPromotedSpec {
+			return dependencyPending, fmt.Sprintf("Waiting for dependency %s.%s to analyse its new revision.", dep.Name, dep.Namespace)
+		}
+		return dependencyReady, ""
+	case flaggerv1.CanaryPhaseFailed, flaggerv1.CanaryPhaseReverted:
+		return dependencyFailed, fmt.Sprintf("Dependency %s.%s failed.", dep.Name, dep.Namespace)
+	default:
+		return dependencyPending, fmt.Sprintf("Waiting for dependency %s.%s to be promoted.", dep.Name, dep.Namespace)
+	}
+}
+
+// checkDependencies keeps the canary in the waiting phase until its dependencies are promoted,
+// if a dependency failed the canary is blocked or failed according to the dependency failure policy
+func (c *Controller) checkDependencies(cd *flaggerv1.Canary, canaryController canary.Controller) bool {
+	for _, ref := range cd.Spec.DependsOn {
+		state, message := c.getDependencyState(cd, ref)
+		if state == dependencyReady {
+			continue
+		}
+
+		if state == dependencyFailed && cd.Spec.DependencyFailurePolicy == flaggerv1.DependencyFailureRollback {
+			canaryPhaseFailed := cd.DeepCopy()
+			canaryPhaseFailed.Status.Phase = flaggerv1.CanaryPhaseFailed
+			c.recordEventWarningf(canaryPhaseFailed, "Canary failed! %s", message)
+			c.alert(canaryPhaseFailed, fmt.Sprintf("%s Canary failed.", message), false, flaggerv1.SeverityError)
+
+			if err := canaryController.SyncStatus(cd, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseFailed, CanaryWeight: 0}); err != nil {
+				c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Errorf("%v", err)
+				return false
+			}
+			c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseFailed)
+			c.runPostRolloutHooks(cd, flaggerv1.CanaryPhaseFailed)
+			return false
+		}
+
+		if cd.Status.Phase != flaggerv1.CanaryPhaseWaiting {
+			c.recordEventWarningf(cd, "Halt %s.%s advancement %s", cd.Name, cd.Namespace, message)
+		}
+		if err := canary.SetStatusWaiting(c.flaggerClient, cd, message, 0); err != nil {
+			c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).Errorf("%v", err)
+		}
+		return false
+	}
+	return true
+}
+
+// checkRunningDependencies halts or rolls back the canary analysis if a dependency failed
+func (c *Controller) checkRunningDependencies(cd *flaggerv1.Canary, canaryController canary.Controller,
+	meshRouter router.Interface, scalerReconciler canary.ScalerReconciler) bool {
+	for _, ref := range cd.Spec.DependsOn {
+		state, message := c.getDependencyState(cd, ref)
+		if state != dependencyFailed {
+			continue
+		}
+
+		if cd.Spec.DependencyFailurePolicy == flaggerv1.DependencyFailureRollback {
+			c.recordEventWarningf(cd, "Rolling back %s.%s %s", cd.Name, cd.Namespace, message)
+			c.alert(cd, fmt.Sprintf("Rolling back, %s", message), false, flaggerv1.SeverityWarn)
+			c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+			return false
+		}
+
+		c.recordEventWarningf(cd, "Halt %s.%s advancement %s", cd.Name, cd.Namespace, message)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+func TestScheduler_DeploymentDependsOn(t *testing.T) {
+	mocks := newDeploymentDependencyFixture(t, flaggerv1.DependencyFailureBlock)
+
+	// wait for the dependency to be promoted
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseWaiting, c.Status.Phase)
+	require.Len(t, c.Status.Conditions, 1)
+	assert.Equal(t, "Waiting for dependency backend.default to be promoted.", c.Status.Conditions[0].Message)
+
+	// start once the dependency has been promoted
+	setDependencyPhase(t, mocks, flaggerv1.CanaryPhaseSucceeded)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseProgressing, c.Status.Phase)
+
+	// halt the analysis if the dependency fails
+	mocks.makeCanaryReady(t)
+	setDependencyPhase(t, mocks, flaggerv1.CanaryPhaseFailed)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseProgressing, c.Status.Phase)
+	assert.Equal(t, 0, c.Status.CanaryWeight)
+}
+
+func TestScheduler_DeploymentDependsOnRollback(t *testing.T) {
+	mocks := newDeploymentDependencyFixture(t, flaggerv1.DependencyFailureRollback)
+
+	setDependencyPhase(t, mocks, flaggerv1.CanaryPhaseFailed)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseFailed, c.Status.Phase)
+
+	// the failed revision is not retried
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseFailed, c.Status.Phase)
+}
+
+func TestScheduler_DeploymentDependsOnNewRevision(t *testing.T) {
+	mocks := newDeploymentDependencyFixture(t, flaggerv1.DependencyFailureBlock)
+
+	// the dependency has applied a revision that's not promoted yet
+	backend, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "backend", metav1.GetOptions{})
+	require.NoError(t, err)
+	backend.Status.Phase = flaggerv1.CanaryPhaseSucceeded
+	backend.Status.LastPromotedSpec = "previous"
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), backend, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseWaiting, c.Status.Phase)
+	require.Len(t, c.Status.Conditions, 1)
+	assert.Equal(t, "Waiting for dependency backend.default to analyse its new revision.", c.Status.Conditions[0].Message)
+}
+
+func TestController_VerifyDependencyCycles(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.DependsOn = []flaggerv1.CrossNamespaceObjectReference{{Name: "frontend"}}
+	mocks := newDeploymentFixture(cd)
+
+	newDependency := func(name string, dependsOn string) {
+		dep := newDeploymentTestCanary()
+		dep.Name = name
+		dep.Spec.TargetRef.Name = name
+		dep.Spec.DependsOn = []flaggerv1.CrossNamespaceObjectReference{{Name: dependsOn}}
+		_, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Create(context.TODO(), dep, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	// podinfo -> frontend -> backend
+	newDependency("frontend", "backend")
+	newDependency("backend", "database")
+	assert.NoError(t, mocks.ctrl.verifyDependencyCycles(cd))
+
+	// podinfo -> frontend -> backend -> podinfo
+	backend, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "backend", metav1.GetOptions{})
+	require.NoError(t, err)
+	backend.Spec.DependsOn = append(backend.Spec.DependsOn, flaggerv1.CrossNamespaceObjectReference{Name: "podinfo"})
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), backend, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	err = mocks.ctrl.verifyDependencyCycles(cd)
+	require.Error(t, err)
+	assert.Equal(t, "dependency cycle detected podinfo.default -> frontend.default -> backend.default -> podinfo.default", err.Error())
+}
+
+func TestController_VerifyDependencies(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.DependsOn = []flaggerv1.CrossNamespaceObjectReference{{Name: "podinfo"}}
+	assert.Error(t, verifyDependencies(cd))
+
+	cd.Spec.DependsOn = []flaggerv1.CrossNamespaceObjectReference{{Name: "backend"}}
+	cd.Spec.DependencyFailurePolicy = "Ignore"
+	assert.Error(t, verifyDependencies(cd))
+
+	cd.Spec.DependencyFailurePolicy = flaggerv1.DependencyFailureRollback
+	assert.NoError(t, verifyDependencies(cd))
+
+	cd.Spec.DependsOn = []flaggerv1.CrossNamespaceObjectReference{{Name: "backend", Namespace: "backend"}}
+	assert.Error(t, verifyNoCrossNamespaceRefs(cd))
+}
+
+// newDeploymentDependencyFixture returns an initialized canary with a new revision
+// that depends on a progressing backend canary
+func newDeploymentDependencyFixture(t *testing.T, policy flaggerv1.DependencyFailurePolicy) fixture {
+	cd := newDeploymentTestCanary()
+	cd.Spec.DependsOn = []flaggerv1.CrossNamespaceObjectReference{{Name: "backend"}}
+	cd.Spec.DependencyFailurePolicy = policy
+	mocks := newDeploymentFixture(cd)
+
+	mocks.initializeCanary(t)
+
+	// the backend has analysed its latest revision
+	backendDep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", Namespace: "default"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "backend"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"name": "backend"}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "backend", Image: "backend:1.0.0"}},
+				},
+			},
+		},
+	}
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Create(context.TODO(), backendDep, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	backend := newDeploymentTestCanary()
+	backend.Name = "backend"
+	backend.Spec.TargetRef.Name = "backend"
+	backend.Status = flaggerv1.CanaryStatus{
+		Phase:           flaggerv1.CanaryPhaseProgressing,
+		LastAppliedSpec: canary.ComputeHash(backendDep.Spec.Template),
+	}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Create(context.TODO(), backend, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	return mocks
+}
+
+func setDependencyPhase(t *testing.T, mocks fixture, phase flaggerv1.CanaryPhase) {
+	backend, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "backend", metav1.GetOptions{})
+	require.NoError(t, err)
+	backend.Status.Phase = phase
+	// the promoted phases record the analysed revision like the status setters do
+	if phase == flaggerv1.CanaryPhaseSucceeded || phase == flaggerv1.CanaryPhaseInitialized {
+		backend.Status.LastPromotedSpec = backend.Status.LastAppliedSpec
+	}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), backend, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
@@ -28,6 +28,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	hpav2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes"
@@ -82,6 +83,43 @@ func (f fixture) makeReady(t *testing.T, name string) {
 	require.NoError(t, err)
 }
 
+// initializeCanary runs the canary through the initializing and initialized phases
+func (f fixture) initializeCanary(t *testing.T) {
+	f.ctrl.advanceCanary(f.canary.Name, f.canary.Namespace)
+	f.makePrimaryReady(t)
+	f.ctrl.advanceCanary(f.canary.Name, f.canary.Namespace)
+}
+
+// startCanaryRevision initializes the canary and starts the analysis of the v2 target
+func (f fixture) startCanaryRevision(t *testing.T) {
+	f.initializeCanary(t)
+	f.updateCanaryTarget(t, false)
+}
+
+// updateCanaryTarget updates the target to v2, optionally along with its configs,
+// and detects the new revision leaving the canary ready for the first analysis step
+func (f fixture) updateCanaryTarget(t *testing.T, updateConfigs bool) {
+	_, err := f.kubeClient.AppsV1().Deployments(f.canary.Namespace).Update(context.TODO(), newDeploymentTestDeploymentV2(), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	if updateConfigs {
+		_, err = f.kubeClient.CoreV1().ConfigMaps(f.canary.Namespace).Update(context.TODO(), newDeploymentTestConfigMapV2(), metav1.UpdateOptions{})
+		require.NoError(t, err)
+	}
+
+	f.ctrl.advanceCanary(f.canary.Name, f.canary.Namespace)
+	f.makeCanaryReady(t)
+}
+
+func (f fixture) assertBaselineDeleted(t *testing.T) {
+	_, err := f.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+	_, err = f.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+	_, err = f.meshClient.NetworkingV1beta1().DestinationRules("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err))
+}
+
 func newDeploymentFixture(c *flaggerv1.Canary) fixture {
 	if c == nil {
 		c = newDeploymentTestCanary()
@@ -132,7 +170,7 @@ func newDeploymentFixture(c *flaggerv1.Canary) fixture {
 		KubeClient:    kubeClient,
 		FlaggerClient: flaggerClient,
 	}
-	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, nil, configTracker, []string{"app", "name"}, []string{""}, logger)
+	canaryFactory := canary.NewFactory(kubeClient, flaggerClient, nil, nil, nil, configTracker, []string{"app", "name"}, []string{""}, logger)
 
 	ctrl := &Controller{
 		kubeClient:       kubeClient,
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,14 +46,7 @@ func TestScheduler_DeploymentInit(t *testing.T) {
 func TestScheduler_DeploymentNewRevision(t *testing.T) {
 	mocks := newDeploymentFixture(nil)
 
-	// initializing ...
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// make primary ready
-	mocks.makePrimaryReady(t)
-
-	// initialization done
-	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.initializeCanary(t)
 
 	// check if ScaleToZero was performed
 	dp, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
@@ -74,14 +68,7 @@ func TestScheduler_DeploymentNewRevision(t *testing.T) {
 
 func TestScheduler_DeploymentRollback(t *testing.T) {
 	mocks := newDeploymentFixture(nil)
-	// initializing
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// make primary ready
-	mocks.makePrimaryReady(t)
-
-	// initialized
-	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.initializeCanary(t)
 
 	// update failed checks to max
 	err := mocks.deployer.SyncStatus(mocks.canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing, FailedChecks: 10})
@@ -116,16 +103,82 @@ func TestScheduler_DeploymentRollback(t *testing.T) {
 	assert.Equal(t, flaggerv1.CanaryPhaseFailed, c.Status.Phase)
 }
 
-func TestScheduler_DeploymentSkipAnalysis(t *testing.T) {
-	mocks := newDeploymentFixture(nil)
-	// initializing
+func TestScheduler_DeploymentBaseline(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.Baseline = true
+	mocks := newDeploymentFixture(cd)
+
+	mocks.startCanaryRevision(t)
+
+	// create baseline
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	baseline, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, primary.Spec.Template.Spec.Containers[0].Image, baseline.Spec.Template.Spec.Containers[0].Image)
+
+	_, err = mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = mocks.meshClient.NetworkingV1beta1().DestinationRules("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// the analysis is halted until the baseline is ready
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.Status.CanaryWeight)
+
+	mocks.makeReady(t, "podinfo-baseline")
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 10, c.Status.CanaryWeight)
+
+	// rollback removes the baseline
+	err = mocks.deployer.SyncStatus(mocks.canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseProgressing, FailedChecks: 10})
+	require.NoError(t, err)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseFailed, c.Status.Phase)
+
+	mocks.assertBaselineDeleted(t)
+
+	// the baseline is not recreated once the analysis is over
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.assertBaselineDeleted(t)
+}
+
+func TestScheduler_DeploymentBaselineFinalising(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.Baseline = true
+	mocks := newDeploymentFixture(cd)
 
-	// make primary ready
-	mocks.makePrimaryReady(t)
+	mocks.startCanaryRevision(t)
 
-	// initialized
+	// create baseline
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	_, err := mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// finalising removes the baseline
+	err = mocks.deployer.SyncStatus(mocks.canary, flaggerv1.CanaryStatus{Phase: flaggerv1.CanaryPhaseFinalising})
+	require.NoError(t, err)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseSucceeded, c.Status.Phase)
+
+	mocks.assertBaselineDeleted(t)
+}
+
+func TestScheduler_DeploymentSkipAnalysis(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	mocks.initializeCanary(t)
 
 	// enable skip
 	cd, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
@@ -161,14 +214,7 @@ func TestScheduler_DeploymentAnalysisPhases(t *testing.T) {
 	}
 	mocks := newDeploymentFixture(cd)
 
-	// initializing
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// make primary ready
-	mocks.makePrimaryReady(t)
-
-	// initialized
-	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.initializeCanary(t)
 	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
 
 	// update
@@ -202,22 +248,102 @@ func TestScheduler_DeploymentAnalysisPhases(t *testing.T) {
 	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseSucceeded))
 }
 
-func TestScheduler_DeploymentBlueGreenAnalysisPhases(t *testing.T) {
+func TestScheduler_DeploymentSteps(t *testing.T) {
 	cd := newDeploymentTestCanary()
-	cd.Spec.Analysis = &flaggerv1.CanaryAnalysis{
-		Interval:   "1m",
-		Iterations: 1,
+	cd.Spec.Analysis.StepWeight = 0
+	cd.Spec.Analysis.MaxWeight = 0
+	cd.Spec.Analysis.Steps = []flaggerv1.CanaryStep{
+		{Weight: 10, Iterations: 2},
+		{Weight: 50, Duration: "1h", Pause: true},
+		{Weight: 100},
 	}
 	mocks := newDeploymentFixture(cd)
 
-	// initializing
+	mocks.initializeCanary(t)
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
+
+	// update
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// detect changes
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.makeCanaryReady(t)
+
+	getStatus := func() flaggerv1.CanaryStatus {
+		c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		return c.Status
+	}
+
+	// enter the first step
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	status := getStatus()
+	assert.Equal(t, 10, status.CanaryWeight)
+	assert.Equal(t, 0, status.StepIndex)
+	require.NotNil(t, status.StepStartTime)
+
+	// hold the first step for two iterations
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	status = getStatus()
+	assert.Equal(t, 10, status.CanaryWeight)
+	assert.Equal(t, 1, status.Iterations)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	status = getStatus()
+	assert.Equal(t, 50, status.CanaryWeight)
+	assert.Equal(t, 1, status.StepIndex)
+	assert.Equal(t, 0, status.Iterations)
+
+	// hold the second step for its duration
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	assert.Equal(t, 50, getStatus().CanaryWeight)
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	c.Status.StepStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// wait for resume
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	assert.Equal(t, 50, getStatus().CanaryWeight)
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	c.Annotations = map[string]string{flaggerv1.ResumeAnnotation: "true"}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// resume and enter the last step
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	status = getStatus()
+	assert.Equal(t, 100, status.CanaryWeight)
+	assert.Equal(t, 2, status.StepIndex)
 
-	// make primary ready
-	mocks.makePrimaryReady(t)
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.ResumeAnnotation)
 
-	// initialized
+	// promote after the last step
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+	status = getStatus()
+	assert.Equal(t, 0, status.StepIndex)
+	assert.Nil(t, status.StepStartTime)
+}
+
+func TestScheduler_DeploymentBlueGreenAnalysisPhases(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis = &flaggerv1.CanaryAnalysis{
+		Interval:   "1m",
+		Iterations: 1,
+	}
+	mocks := newDeploymentFixture(cd)
+
+	mocks.initializeCanary(t)
 	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
 
 	// update
@@ -253,24 +379,7 @@ func TestScheduler_DeploymentBlueGreenAnalysisPhases(t *testing.T) {
 
 func TestScheduler_DeploymentNewRevisionReset(t *testing.T) {
 	mocks := newDeploymentFixture(nil)
-	// init
-	// initializing
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// make primary ready
-	mocks.makePrimaryReady(t)
-
-	// initialized
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// first update
-	dep2 := newDeploymentTestDeploymentV2()
-	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
-	require.NoError(t, err)
-
-	// detect changes
-	mocks.ctrl.advanceCanary("podinfo", "default")
-	mocks.makeCanaryReady(t)
+	mocks.startCanaryRevision(t)
 
 	// advance
 	mocks.ctrl.advanceCanary("podinfo", "default")
@@ -282,6 +391,7 @@ func TestScheduler_DeploymentNewRevisionReset(t *testing.T) {
 	assert.False(t, mirrored)
 
 	// second update
+	dep2 := newDeploymentTestDeploymentV2()
 	dep2.Spec.Template.Spec.ServiceAccountName = "test"
 	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
 	require.NoError(t, err)
@@ -299,14 +409,7 @@ func TestScheduler_DeploymentNewRevisionReset(t *testing.T) {
 func TestScheduler_DeploymentPromotion(t *testing.T) {
 	mocks := newDeploymentFixture(nil)
 
-	// initializing
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// make primary ready
-	mocks.makePrimaryReady(t)
-
-	// initialized
-	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.initializeCanary(t)
 
 	// check initialized status
 	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
@@ -394,26 +497,228 @@ func TestScheduler_DeploymentPromotion(t *testing.T) {
 	assert.Equal(t, flaggerv1.CanaryPhaseSucceeded, c.Status.Phase)
 }
 
-func TestScheduler_DeploymentMirroring(t *testing.T) {
-	mocks := newDeploymentFixture(newDeploymentTestCanaryMirror())
+func TestScheduler_DeploymentPostPromotionRevert(t *testing.T) {
+	mocks := newDeploymentPostPromotionFixture(t, false)
 
-	// initializing
+	// verify primary
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseVerifying))
+
+	// make the primary checks fail
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	c.Spec.Analysis.Metrics[0].Threshold = 101
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
 
-	// make primary ready
-	mocks.makePrimaryReady(t)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseVerifying, c.Status.Phase)
+	assert.Equal(t, 1, c.Status.FailedChecks)
 
-	// initialized
+	// revert primary
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseReverted, c.Status.Phase)
+	assert.NotEqual(t, c.Status.LastAppliedSpec, c.Status.LastPromotedSpec)
 
-	// update
-	dep2 := newDeploymentTestDeploymentV2()
-	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	primaryDep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
 	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.0", primaryDep.Spec.Template.Spec.Containers[0].Image)
 
-	// detect pod spec changes
+	// the reverted revision is not analysed again
 	mocks.ctrl.advanceCanary("podinfo", "default")
-	mocks.makeCanaryReady(t)
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseReverted))
+}
+
+func TestScheduler_DeploymentPostPromotionRevertConfigChanged(t *testing.T) {
+	mocks := newDeploymentPostPromotionFixture(t, true)
+
+	// verify primary
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseVerifying))
+
+	// make the primary checks fail
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	c.Spec.Analysis.Metrics[0].Threshold = 101
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseVerifying))
+
+	// the previous revision can't run with the promoted configs
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFailed))
+
+	primaryDep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.1", primaryDep.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestScheduler_DeploymentPostPromotionSucceeded(t *testing.T) {
+	mocks := newDeploymentPostPromotionFixture(t, false)
+
+	// verify primary
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseVerifying))
+
+	// end the verification window
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, c.Status.Conditions, 1)
+	c.Status.Conditions[0].LastUpdateTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseSucceeded, c.Status.Phase)
+	assert.Equal(t, c.Status.LastAppliedSpec, c.Status.LastPromotedSpec)
+
+	primaryDep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.1", primaryDep.Spec.Template.Spec.Containers[0].Image)
+}
+
+func TestScheduler_DeploymentRollbackToRevision(t *testing.T) {
+	mocks := newDeploymentPostPromotionFixture(t, false)
+
+	// verify primary
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseVerifying))
+
+	// the rollback is discarded during the verification
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	c.Annotations = map[string]string{flaggerv1.RollbackToAnnotation: "1"}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	primaryDep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.1", primaryDep.Spec.Template.Spec.Containers[0].Image)
+
+	// end the verification window
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.RollbackToAnnotation)
+	c.Status.Conditions[0].LastUpdateTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseSucceeded))
+
+	// roll back to the initial revision
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	promotedSpec := c.Status.LastPromotedSpec
+	c.Annotations = map[string]string{flaggerv1.RollbackToAnnotation: "1"}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	primaryDep, err = mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.0", primaryDep.Spec.Template.Spec.Containers[0].Image)
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseSucceeded, c.Status.Phase)
+	assert.NotEqual(t, promotedSpec, c.Status.LastPromotedSpec)
+	assert.NotContains(t, c.Annotations, flaggerv1.RollbackToAnnotation)
+
+	// an unknown revision is discarded
+	c.Annotations = map[string]string{flaggerv1.RollbackToAnnotation: "10"}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.RollbackToAnnotation)
+}
+
+func TestScheduler_DeploymentRollbackToRevisionConfigChanged(t *testing.T) {
+	mocks := newDeploymentPostPromotionFixture(t, true)
+
+	// verify primary
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseVerifying))
+
+	// end the verification window
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	c.Status.Conditions[0].LastUpdateTime = metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseSucceeded))
+
+	// the initial revision can't run with the promoted configs
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	promotedSpec := c.Status.LastPromotedSpec
+	c.Annotations = map[string]string{flaggerv1.RollbackToAnnotation: "1"}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	primaryDep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.1", primaryDep.Spec.Template.Spec.Containers[0].Image)
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseSucceeded, c.Status.Phase)
+	assert.Equal(t, promotedSpec, c.Status.LastPromotedSpec)
+	assert.NotContains(t, c.Annotations, flaggerv1.RollbackToAnnotation)
+}
+
+// newDeploymentPostPromotionFixture promotes a new revision and returns the fixture in the finalising phase
+func newDeploymentPostPromotionFixture(t *testing.T, updateConfigs bool) fixture {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.StepWeight = 100
+	cd.Spec.Analysis.MaxWeight = 0
+	cd.Spec.Analysis.Threshold = 1
+	cd.Spec.Analysis.PostPromotion = &flaggerv1.CanaryPostPromotion{Window: "1h"}
+	mocks := newDeploymentFixture(cd)
+
+	mocks.initializeCanary(t)
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
+
+	mocks.updateCanaryTarget(t, updateConfigs)
+
+	// progressing
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// promoting
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+
+	_, err := mocks.kubeClient.CoreV1().ConfigMaps("default").Get(context.TODO(), "podinfo-revisions", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// finalising
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFinalising))
+
+	return mocks
+}
+
+func TestScheduler_DeploymentMirroring(t *testing.T) {
+	mocks := newDeploymentFixture(newDeploymentTestCanaryMirror())
+
+	mocks.startCanaryRevision(t)
 
 	// advance
 	mocks.ctrl.advanceCanary("podinfo", "default")
@@ -438,23 +743,7 @@ func TestScheduler_DeploymentMirroring(t *testing.T) {
 
 func TestScheduler_DeploymentABTesting(t *testing.T) {
 	mocks := newDeploymentFixture(newDeploymentTestCanaryAB())
-	// initializing
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// make primary ready
-	mocks.makePrimaryReady(t)
-
-	// initialized
-	mocks.ctrl.advanceCanary("podinfo", "default")
-
-	// update
-	dep2 := newDeploymentTestDeploymentV2()
-	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
-	require.NoError(t, err)
-
-	// detect pod spec changes
-	mocks.ctrl.advanceCanary("podinfo", "default")
-	mocks.makeCanaryReady(t)
+	mocks.startCanaryRevision(t)
 
 	// advance
 	mocks.ctrl.advanceCanary("podinfo", "default")
@@ -489,7 +778,7 @@ func TestScheduler_DeploymentABTesting(t *testing.T) {
 	require.NoError(t, err)
 
 	primaryImage := primaryDep.Spec.Template.Spec.Containers[0].Image
-	canaryImage := dep2.Spec.Template.Spec.Containers[0].Image
+	canaryImage := newDeploymentTestDeploymentV2().Spec.Template.Spec.Containers[0].Image
 	assert.Equal(t, canaryImage, primaryImage)
 
 	// shutdown canary
@@ -637,12 +926,198 @@ func TestScheduler_DeploymentAlerts(t *testing.T) {
 	_, err := mocks.kubeClient.CoreV1().Secrets("default").Update(context.TODO(), secret, metav1.UpdateOptions{})
 	require.NoError(t, err)
 
-	// init canary
+	mocks.initializeCanary(t)
+}
+
+func TestScheduler_DeploymentStepsResumeOnce(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.StepWeight = 0
+	cd.Spec.Analysis.MaxWeight = 0
+	cd.Spec.Analysis.Steps = []flaggerv1.CanaryStep{
+		{Weight: 10, Pause: true},
+		{Weight: 50, Duration: "1h", Pause: true},
+		{Weight: 100},
+	}
+	mocks := newDeploymentFixture(cd)
+
+	mocks.startCanaryRevision(t)
+
+	getStatus := func() flaggerv1.CanaryStatus {
+		c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		return c.Status
+	}
+
+	// enter the first step and wait for resume
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	assert.Equal(t, 10, getStatus().CanaryWeight)
 
-	// make primary ready
-	mocks.makePrimaryReady(t)
+	// resume and enter the second step
+	setCanaryAnnotation(t, mocks, flaggerv1.ResumeAnnotation)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	status := getStatus()
+	assert.Equal(t, 50, status.CanaryWeight)
+	assert.Equal(t, 1, status.StepIndex)
 
-	// initialization done - now send alert
+	// a resume set while the second step holds its weight is discarded
+	setCanaryAnnotation(t, mocks, flaggerv1.ResumeAnnotation)
 	mocks.ctrl.advanceCanary("podinfo", "default")
+	assert.Equal(t, 50, getStatus().CanaryWeight)
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.ResumeAnnotation)
+
+	c.Status.StepStartTime = &metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").UpdateStatus(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// the second step waits for its own resume
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	assert.Equal(t, 50, getStatus().CanaryWeight)
+
+	setCanaryAnnotation(t, mocks, flaggerv1.ResumeAnnotation)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	status = getStatus()
+	assert.Equal(t, 100, status.CanaryWeight)
+	assert.Equal(t, 2, status.StepIndex)
+}
+
+func TestScheduler_DeploymentManualPauseResume(t *testing.T) {
+	mocks := newDeploymentManualFixture(t)
+
+	getStatus := func() flaggerv1.CanaryStatus {
+		c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		return c.Status
+	}
+
+	// pause at the current weight
+	setCanaryAnnotation(t, mocks, flaggerv1.PauseAnnotation)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	status := getStatus()
+	assert.True(t, status.Paused)
+	assert.Equal(t, 10, status.CanaryWeight)
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.PauseAnnotation)
+
+	// hold the weight while paused
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	assert.Equal(t, 10, getStatus().CanaryWeight)
+
+	// resume and advance
+	setCanaryAnnotation(t, mocks, flaggerv1.ResumeAnnotation)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	status = getStatus()
+	assert.False(t, status.Paused)
+	assert.Equal(t, 20, status.CanaryWeight)
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.ResumeAnnotation)
+}
+
+func TestScheduler_DeploymentManualPromote(t *testing.T) {
+	mocks := newDeploymentManualFixture(t)
+
+	setCanaryAnnotation(t, mocks, flaggerv1.PromoteAnnotation)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+
+	primaryDep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "quay.io/stefanprodan/podinfo:1.2.1", primaryDep.Spec.Template.Spec.Containers[0].Image)
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.PromoteAnnotation)
+
+	// finalising
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFinalising))
+
+	// succeeded
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseSucceeded))
+}
+
+func TestScheduler_DeploymentManualAbort(t *testing.T) {
+	mocks := newDeploymentManualFixture(t)
+
+	setCanaryAnnotation(t, mocks, flaggerv1.AbortAnnotation)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFailed))
+
+	primaryWeight, canaryWeight, _, err := mocks.router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 100, primaryWeight)
+	assert.Equal(t, 0, canaryWeight)
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.AbortAnnotation)
+
+	// annotations set after the analysis are discarded
+	setCanaryAnnotation(t, mocks, flaggerv1.PromoteAnnotation)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFailed))
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.NotContains(t, c.Annotations, flaggerv1.PromoteAnnotation)
+}
+
+func TestScheduler_GetAnnotationManager(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.ManagedFields = []metav1.ManagedFieldsEntry{
+		{
+			Manager:  "flagger",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:phase":{}}}`)},
+		},
+		{
+			Manager:  "kubectl-annotate",
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{".":{},"f:flagger.app/pause":{}}}}`)},
+		},
+	}
+
+	assert.Equal(t, "kubectl-annotate", getAnnotationManager(cd, flaggerv1.PauseAnnotation))
+	assert.Equal(t, "unknown", getAnnotationManager(cd, flaggerv1.PromoteAnnotation))
+}
+
+// newDeploymentManualFixture starts the analysis of a new revision and returns the fixture at the first step
+func newDeploymentManualFixture(t *testing.T) fixture {
+	mocks := newDeploymentFixture(nil)
+
+	mocks.initializeCanary(t)
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseInitialized))
+
+	// update
+	dep2 := newDeploymentTestDeploymentV2()
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// detect changes
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	mocks.makeCanaryReady(t)
+
+	// progressing
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseProgressing))
+
+	return mocks
+}
+
+func setCanaryAnnotation(t *testing.T, mocks fixture, key string) {
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	if c.Annotations == nil {
+		c.Annotations = make(map[string]string)
+	}
+	c.Annotations[key] = "true"
+	_, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Update(context.TODO(), c, metav1.UpdateOptions{})
+	require.NoError(t, err)
 }
@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// metricCheckResult is the outcome of a metric check
+type metricCheckResult int
+
+const (
+	// metricCheckPassed means the metric value is within its threshold
+	metricCheckPassed metricCheckResult = iota
+	// metricCheckBreached means the metric value is outside its threshold
+	metricCheckBreached
+	// metricCheckErrored means the metric couldn't be queried or returned no values
+	metricCheckErrored
+)
+
+// runMetricFailurePolicy returns the analysis result of a metric check, the threshold
+// breaches are recorded and handled according to the metric failure policy while
+// the query errors count as failed checks regardless of the policy
+func (c *Controller) runMetricFailurePolicy(cd *flaggerv1.Canary, metric flaggerv1.CanaryMetric,
+	check metricCheckResult) analysisResult {
+	switch check {
+	case metricCheckPassed:
+		return analysisPassed
+	case metricCheckErrored:
+		return analysisFailed
+	}
+
+	failedChecks := cd.Status.MetricFailedChecks[metric.Name] + 1
+	if metric.Name != "" {
+		if err := canary.SetStatusMetricFailedChecks(c.flaggerClient, cd, metric.Name, failedChecks); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+	}
+
+	switch metric.FailurePolicy {
+	case flaggerv1.MetricFailureWarn:
+		c.recordEventWarningf(cd, "Metric %s check failed for %s.%s, advancing with the warn failure policy",
+			metric.Name, cd.Name, cd.Namespace)
+		// alert once per analysis to not repeat it at every interval
+		if failedChecks == 1 {
+			c.alert(cd, fmt.Sprintf("Metric %s check failed", metric.Name), false, flaggerv1.SeverityWarn)
+		}
+		return analysisPassed
+	case flaggerv1.MetricFailureFailFast:
+		c.recordEventWarningf(cd, "Rolling back %s.%s metric %s check failed with the fail-fast policy",
+			cd.Name, cd.Namespace, metric.Name)
+		c.alert(cd, fmt.Sprintf("Metric %s check failed with the fail-fast policy", metric.Name),
+			false, flaggerv1.SeverityError)
+		return analysisAborted
+	case flaggerv1.MetricFailureThreshold:
+		return analysisHalted
+	default:
+		return analysisFailed
+	}
+}
+
+// mergeAnalysisResults returns the result of two metric checks, an aborted analysis takes
+// precedence over a failed one, and a failed analysis over a halted one
+func mergeAnalysisResults(a analysisResult, b analysisResult) analysisResult {
+	for _, result := range []analysisResult{analysisAborted, analysisFailed, analysisHalted} {
+		if a == result || b == result {
+			return result
+		}
+	}
+	return analysisPassed
+}
+
+// getMetricThresholdReached returns the name of the first metric with the threshold
+// failure policy whose failed checks reached its failure threshold
+func getMetricThresholdReached(cd *flaggerv1.Canary) (string, bool) {
+	if cd.GetAnalysis() == nil {
+		return "", false
+	}
+	for _, metric := range cd.GetAnalysis().Metrics {
+		if metric.FailurePolicy == flaggerv1.MetricFailureThreshold && metric.FailureThreshold > 0 &&
+			cd.Status.MetricFailedChecks[metric.Name] >= metric.FailureThreshold {
+			return metric.Name, true
+		}
+	}
+	return "", false
+}
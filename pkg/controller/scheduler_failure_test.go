@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// newFailurePolicyFixture starts the analysis of a canary with a failing success rate check
+func newFailurePolicyFixture(t *testing.T, policy flaggerv1.MetricFailurePolicy, threshold int) fixture {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.Metrics[0].Threshold = 101
+	cd.Spec.Analysis.Metrics[0].FailurePolicy = policy
+	cd.Spec.Analysis.Metrics[0].FailureThreshold = threshold
+	mocks := newDeploymentFixture(cd)
+
+	mocks.startCanaryRevision(t)
+
+	// advance to the first step
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	return mocks
+}
+
+func TestScheduler_DeploymentMetricFailurePolicies(t *testing.T) {
+	t.Run("warn", func(t *testing.T) {
+		mocks := newFailurePolicyFixture(t, flaggerv1.MetricFailureWarn, 0)
+
+		// the failed check is recorded and the canary advances
+		mocks.ctrl.advanceCanary("podinfo", "default")
+
+		c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, c.Status.MetricFailedChecks["request-success-rate"])
+		assert.Equal(t, 0, c.Status.FailedChecks)
+		assert.Equal(t, 20, c.Status.CanaryWeight)
+	})
+
+	t.Run("failFast", func(t *testing.T) {
+		mocks := newFailurePolicyFixture(t, flaggerv1.MetricFailureFailFast, 0)
+
+		// roll back on the first failed check
+		mocks.ctrl.advanceCanary("podinfo", "default")
+		require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFailed))
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		mocks := newFailurePolicyFixture(t, flaggerv1.MetricFailureThreshold, 2)
+
+		// the failed checks are counted for the metric only
+		mocks.ctrl.advanceCanary("podinfo", "default")
+		mocks.ctrl.advanceCanary("podinfo", "default")
+
+		c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, c.Status.MetricFailedChecks["request-success-rate"])
+		assert.Equal(t, 0, c.Status.FailedChecks)
+		assert.Equal(t, 10, c.Status.CanaryWeight)
+
+		// roll back when the metric failure threshold is reached
+		mocks.ctrl.advanceCanary("podinfo", "default")
+		require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFailed))
+	})
+}
+
+func TestController_runMetricChecksFailurePolicies(t *testing.T) {
+	// the value query returns 50, the error query fails and the empty query returns no values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query()["query"][0] {
+		case "error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "empty":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+		default:
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1545905245.458,"50"]}]}}`))
+		}
+	}))
+	defer ts.Close()
+
+	newCtrl := func() *Controller {
+		ctrl := newDeploymentFixture(nil).ctrl
+		for _, query := range []string{"value", "error", "empty"} {
+			template := newDeploymentTestMetricTemplate()
+			template.Name = query
+			template.Spec.Query = query
+			template.Spec.Provider.Address = ts.URL
+			template.Spec.Provider.SecretRef = nil
+			require.NoError(t, ctrl.flaggerInformers.MetricInformer.Informer().GetIndexer().Add(template))
+		}
+		return ctrl
+	}
+
+	// newMetric returns a metric of the template breaching its max threshold of 10
+	newMetric := func(name string, template string, policy flaggerv1.MetricFailurePolicy) flaggerv1.CanaryMetric {
+		metric := flaggerv1.CanaryMetric{
+			Name:           name,
+			TemplateRef:    &flaggerv1.CrossNamespaceObjectReference{Name: template, Namespace: "default"},
+			ThresholdRange: &flaggerv1.CanaryThresholdRange{Max: toFloatPtr(10)},
+			FailurePolicy:  policy,
+		}
+		if policy == flaggerv1.MetricFailureThreshold {
+			metric.FailureThreshold = 3
+		}
+		return metric
+	}
+
+	newCanary := func(metrics ...flaggerv1.CanaryMetric) *flaggerv1.Canary {
+		return &flaggerv1.Canary{
+			ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+			Spec: flaggerv1.CanarySpec{
+				TargetRef: flaggerv1.LocalObjectReference{Name: "podinfo", Kind: "Deployment"},
+				Analysis:  &flaggerv1.CanaryAnalysis{Metrics: metrics},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		metrics  []flaggerv1.CanaryMetric
+		expected analysisResult
+	}{
+		{"fail-fast breach", []flaggerv1.CanaryMetric{
+			newMetric("errors", "value", flaggerv1.MetricFailureFailFast)}, analysisAborted},
+		{"fail-fast query error", []flaggerv1.CanaryMetric{
+			newMetric("errors", "error", flaggerv1.MetricFailureFailFast)}, analysisFailed},
+		{"fail-fast no values", []flaggerv1.CanaryMetric{
+			newMetric("errors", "empty", flaggerv1.MetricFailureFailFast)}, analysisFailed},
+		{"warn breach", []flaggerv1.CanaryMetric{
+			newMetric("errors", "value", flaggerv1.MetricFailureWarn)}, analysisPassed},
+		{"warn query error", []flaggerv1.CanaryMetric{
+			newMetric("errors", "error", flaggerv1.MetricFailureWarn)}, analysisFailed},
+		{"threshold breach", []flaggerv1.CanaryMetric{
+			newMetric("errors", "value", flaggerv1.MetricFailureThreshold)}, analysisHalted},
+		{"fail-fast after a failed metric", []flaggerv1.CanaryMetric{
+			newMetric("latency", "value", ""),
+			newMetric("errors", "value", flaggerv1.MetricFailureFailFast)}, analysisAborted},
+		{"fail-fast after a query error", []flaggerv1.CanaryMetric{
+			newMetric("latency", "error", ""),
+			newMetric("errors", "value", flaggerv1.MetricFailureFailFast)}, analysisAborted},
+		{"failed after a halted metric", []flaggerv1.CanaryMetric{
+			newMetric("latency", "value", flaggerv1.MetricFailureThreshold),
+			newMetric("errors", "value", "")}, analysisFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, newCtrl().runMetricChecks(newCanary(tt.metrics...)))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// selectAnalysisProfile classifies the target changes and returns the analysis profile of the class,
+// it returns nil if the canary has no fast-track profiles
+func (c *Controller) selectAnalysisProfile(cd *flaggerv1.Canary, canaryController canary.Controller) *flaggerv1.CanaryFastTrackStatus {
+	if cd.GetAnalysis() == nil || cd.GetAnalysis().FastTrack == nil {
+		return nil
+	}
+
+	class, reason := flaggerv1.ChangeOther, fmt.Sprintf("%s targets can't be classified", cd.Spec.TargetRef.Kind)
+	if revisionController, ok := canaryController.(canary.RevisionController); ok {
+		var err error
+		class, reason, err = revisionController.ClassifyChange(cd)
+		if err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			class, reason = flaggerv1.ChangeOther, "the changes can't be classified"
+		}
+	}
+
+	return &flaggerv1.CanaryFastTrackStatus{
+		Class:   class,
+		Profile: cd.GetFastTrackProfile(class),
+		Reason:  reason,
+	}
+}
+
+// fastTrackMessage describes the analysis profile chosen for the revision
+func fastTrackMessage(fastTrack *flaggerv1.CanaryFastTrackStatus) string {
+	return fmt.Sprintf("Analysis profile %s for %s changes: %s.", fastTrack.Profile, fastTrack.Class, fastTrack.Reason)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// promoteIgnoredChanges applies the target changes excluded from the analysis
+// by the canary ignore rules to the primary
+func (c *Controller) promoteIgnoredChanges(cd *flaggerv1.Canary, canaryController canary.Controller) {
+	if cd.Spec.IgnoreChanges == nil {
+		return
+	}
+
+	revisionController, ok := canaryController.(canary.RevisionController)
+	if !ok {
+		return
+	}
+
+	promoted, err := revisionController.PromoteIgnoredChanges(cd)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+	if promoted {
+		c.recordEventInfof(cd, "Ignored changes of %s.%s copied to %s-primary.%s without analysis",
+			cd.Spec.TargetRef.Name, cd.Namespace, cd.Spec.TargetRef.Name, cd.Namespace)
+	}
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+	"github.com/fluxcd/flagger/pkg/router"
+)
+
+// manualAnnotations are the annotations that trigger a manual action on the canary
+var manualAnnotations = []string{
+	flaggerv1.AbortAnnotation,
+	flaggerv1.PromoteAnnotation,
+	flaggerv1.PauseAnnotation,
+	flaggerv1.ResumeAnnotation,
+}
+
+// runManualActions acts on the flagger.app/abort, promote, pause and resume annotations
+// and removes them, it returns true if the analysis should continue
+func (c *Controller) runManualActions(cd *flaggerv1.Canary, canaryController canary.Controller,
+	meshRouter router.Interface, scalerReconciler canary.ScalerReconciler) bool {
+	annotations := cd.GetAnnotations()
+
+	if _, ok := annotations[flaggerv1.AbortAnnotation]; ok {
+		actor := getAnnotationManager(cd, flaggerv1.AbortAnnotation)
+		c.recordEventWarningf(cd, "Rolling back %s.%s aborted by %s", cd.Name, cd.Namespace, actor)
+		c.alert(cd, fmt.Sprintf("Rolling back canary aborted by %s", actor), false, flaggerv1.SeverityWarn)
+		c.rollback(cd, canaryController, meshRouter, scalerReconciler)
+		if err := c.removeCanaryAnnotation(cd, flaggerv1.AbortAnnotation); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+		return false
+	}
+
+	if _, ok := annotations[flaggerv1.PromoteAnnotation]; ok {
+		actor := getAnnotationManager(cd, flaggerv1.PromoteAnnotation)
+		c.recordEventInfof(cd, "Copying %s.%s template spec to %s-primary.%s, analysis skipped by %s",
+			cd.Spec.TargetRef.Name, cd.Namespace, cd.Spec.TargetRef.Name, cd.Namespace, actor)
+		if err := c.restoreCanaryReplicas(cd, canaryController); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return false
+		}
+		if err := canaryController.Promote(cd); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return false
+		}
+		if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhasePromoting); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return false
+		}
+		c.alert(cd, fmt.Sprintf("Promoting canary, analysis skipped by %s", actor), false, flaggerv1.SeverityInfo)
+		if err := c.removeCanaryAnnotation(cd, flaggerv1.PromoteAnnotation); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+		return false
+	}
+
+	// the resume annotation is left in place for the analysis step waiting for it,
+	// otherwise it's removed so that it can't skip a later pause step
+	if _, ok := annotations[flaggerv1.ResumeAnnotation]; ok {
+		if cd.Status.Paused {
+			actor := getAnnotationManager(cd, flaggerv1.ResumeAnnotation)
+			if err := canary.SetStatusPaused(c.flaggerClient, cd, false); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+				return false
+			}
+			c.recordEventInfof(cd, "Resume %s.%s advancement, resumed by %s", cd.Name, cd.Namespace, actor)
+			c.alert(cd, fmt.Sprintf("Canary advancement resumed by %s", actor), false, flaggerv1.SeverityInfo)
+			if err := c.removeCanaryAnnotation(cd, flaggerv1.ResumeAnnotation); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+				return false
+			}
+		} else if !isWaitingForResume(cd) {
+			c.recordEventWarningf(cd, "Annotation %s ignored, canary %s.%s is not paused",
+				flaggerv1.ResumeAnnotation, cd.Name, cd.Namespace)
+			if err := c.removeCanaryAnnotation(cd, flaggerv1.ResumeAnnotation); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+				return false
+			}
+		}
+	}
+
+	if _, ok := annotations[flaggerv1.PauseAnnotation]; ok {
+		if !cd.Status.Paused {
+			actor := getAnnotationManager(cd, flaggerv1.PauseAnnotation)
+			if err := canary.SetStatusPaused(c.flaggerClient, cd, true); err != nil {
+				c.recordEventWarningf(cd, "%v", err)
+				return false
+			}
+			c.recordEventWarningf(cd, "Halt %s.%s advancement at weight %v, paused by %s",
+				cd.Name, cd.Namespace, cd.Status.CanaryWeight, actor)
+			c.alert(cd, fmt.Sprintf("Canary advancement paused by %s", actor), false, flaggerv1.SeverityWarn)
+		}
+		if err := c.removeCanaryAnnotation(cd, flaggerv1.PauseAnnotation); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+		return false
+	}
+
+	if cd.Status.Paused {
+		c.recordEventInfof(cd, "Halt %s.%s advancement paused, waiting for %s annotation",
+			cd.Name, cd.Namespace, flaggerv1.ResumeAnnotation)
+		return false
+	}
+
+	return true
+}
+
+// discardManualActions removes the manual action annotations set while
+// the canary is not running an analysis
+func (c *Controller) discardManualActions(cd *flaggerv1.Canary) {
+	for _, key := range manualAnnotations {
+		if _, ok := cd.GetAnnotations()[key]; !ok {
+			continue
+		}
+		c.recordEventWarningf(cd, "Annotation %s ignored, canary %s.%s is %s",
+			key, cd.Name, cd.Namespace, cd.Status.Phase)
+		if err := c.removeCanaryAnnotation(cd, key); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+	}
+}
+
+// getAnnotationManager returns the name of the field manager that owns
+// the canary annotation as recorded in the object managed fields
+func getAnnotationManager(cd *flaggerv1.Canary, key string) string {
+	for _, entry := range cd.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		var fields struct {
+			Metadata struct {
+				Annotations map[string]json.RawMessage `json:"f:annotations"`
+			} `json:"f:metadata"`
+		}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ok := fields.Metadata.Annotations["f:"+key]; ok && entry.Manager != "" {
+			return entry.Manager
+		}
+	}
+	return "unknown"
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
 	"github.com/fluxcd/flagger/pkg/metrics/observers"
 	"github.com/fluxcd/flagger/pkg/metrics/providers"
+	"github.com/fluxcd/flagger/pkg/metrics/scoring"
 	serving "knative.dev/serving/pkg/apis/serving/v1"
 )
 
@@ -39,13 +41,9 @@ const (
 func (c *Controller) checkMetricProviderAvailability(canary *flaggerv1.Canary) error {
 	for _, metric := range canary.GetAnalysis().Metrics {
 		if metric.Name == "request-success-rate" || metric.Name == "request-duration" {
-			observerFactory := c.observerFactory
-			if canary.Spec.MetricsServer != "" {
-				var err error
-				observerFactory, err = observers.NewFactory(canary.Spec.MetricsServer)
-				if err != nil {
-					return fmt.Errorf("error building Prometheus client for %s %v", canary.Spec.MetricsServer, err)
-				}
+			observerFactory, err := c.getObserverFactory(canary)
+			if err != nil {
+				return fmt.Errorf("error building Prometheus client for %s %v", canary.Spec.MetricsServer, err)
 			}
 			if ok, err := observerFactory.Client.IsOnline(); !ok || err != nil {
 				return fmt.Errorf("prometheus not avaiable: %v", err)
@@ -54,31 +52,9 @@ func (c *Controller) checkMetricProviderAvailability(canary *flaggerv1.Canary) e
 		}
 
 		if metric.TemplateRef != nil {
-			namespace := canary.Namespace
-			if metric.TemplateRef.Namespace != canary.Namespace && metric.TemplateRef.Namespace != "" {
-				namespace = metric.TemplateRef.Namespace
-			}
-
-			template, err := c.flaggerInformers.MetricInformer.Lister().MetricTemplates(namespace).Get(metric.TemplateRef.Name)
+			template, provider, err := c.getMetricTemplateProvider(canary, metric)
 			if err != nil {
-				return fmt.Errorf("metric template %s.%s error: %v", metric.TemplateRef.Name, namespace, err)
-			}
-
-			var credentials map[string][]byte
-			if template.Spec.Provider.SecretRef != nil {
-				secret, err := c.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), template.Spec.Provider.SecretRef.Name, metav1.GetOptions{})
-				if err != nil {
-					return fmt.Errorf("metric template %s.%s secret %s error: %v",
-						metric.TemplateRef.Name, namespace, template.Spec.Provider.SecretRef.Name, err)
-				}
-				credentials = secret.Data
-			}
-
-			factory := providers.Factory{}
-			provider, err := factory.Provider(metric.Interval, template.Spec.Provider, credentials, c.kubeConfig)
-			if err != nil {
-				return fmt.Errorf("metric template %s.%s provider %s error: %v",
-					metric.TemplateRef.Name, namespace, template.Spec.Provider.Type, err)
+				return err
 			}
 
 			if ok, err := provider.IsOnline(); !ok || err != nil {
@@ -91,7 +67,41 @@ func (c *Controller) checkMetricProviderAvailability(canary *flaggerv1.Canary) e
 	return nil
 }
 
-func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
+// getMetricTemplateProvider returns the metric template referenced by the canary metric
+// along with the provider built from the template spec and credentials
+func (c *Controller) getMetricTemplateProvider(canary *flaggerv1.Canary, metric flaggerv1.CanaryMetric) (*flaggerv1.MetricTemplate, providers.Interface, error) {
+	namespace := canary.Namespace
+	if metric.TemplateRef.Namespace != canary.Namespace && metric.TemplateRef.Namespace != "" {
+		namespace = metric.TemplateRef.Namespace
+	}
+
+	template, err := c.flaggerInformers.MetricInformer.Lister().MetricTemplates(namespace).Get(metric.TemplateRef.Name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("metric template %s.%s error: %v", metric.TemplateRef.Name, namespace, err)
+	}
+
+	var credentials map[string][]byte
+	if template.Spec.Provider.SecretRef != nil {
+		secret, err := c.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), template.Spec.Provider.SecretRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("metric template %s.%s secret %s error: %v",
+				metric.TemplateRef.Name, namespace, template.Spec.Provider.SecretRef.Name, err)
+		}
+		credentials = secret.Data
+	}
+
+	factory := providers.Factory{}
+	provider, err := factory.Provider(metric.Interval, template.Spec.Provider, credentials, c.kubeConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("metric template %s.%s provider %s error: %v",
+			metric.TemplateRef.Name, namespace, template.Spec.Provider.Type, err)
+	}
+
+	return template, provider, nil
+}
+
+// getMetricsProvider returns the provider of the builtin metrics based on the mesh provider and the target kind
+func (c *Controller) getMetricsProvider(canary *flaggerv1.Canary) string {
 	// override the global provider if one is specified in the canary spec
 	var metricsProvider string
 	// set the metrics provider to Crossover Prometheus when Crossover is the mesh provider
@@ -115,241 +125,523 @@ func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) bool {
 		metricsProvider = metricsProvider + MetricsProviderServiceSuffix
 	}
 
+	return metricsProvider
+}
+
+// getObserverFactory returns the builtin metrics observers factory, built once for
+// each metrics server specified in the canary specs
+func (c *Controller) getObserverFactory(canary *flaggerv1.Canary) (*observers.Factory, error) {
+	if canary.Spec.MetricsServer == "" {
+		return c.observerFactory, nil
+	}
+	if factory, ok := c.observerFactories.Load(canary.Spec.MetricsServer); ok {
+		return factory.(*observers.Factory), nil
+	}
+	factory, err := observers.NewFactory(canary.Spec.MetricsServer)
+	if err != nil {
+		return nil, err
+	}
+	c.observerFactories.Store(canary.Spec.MetricsServer, factory)
+	return factory, nil
+}
+
+// runBuiltinMetricChecks checks the builtin metrics and the in-line queries, all the metrics
+// are checked and the threshold breaches are handled according to their failure policy
+func (c *Controller) runBuiltinMetricChecks(canary *flaggerv1.Canary) analysisResult {
+	metricsProvider := c.getMetricsProvider(canary)
+
 	var knativeService *serving.Service
 	if canary.Spec.Provider == flaggerv1.KnativeProvider || c.meshProvider == flaggerv1.KnativeProvider {
 		var err error
 		knativeService, err = c.knativeClient.ServingV1().Services(canary.Namespace).Get(context.TODO(), canary.Spec.TargetRef.Name, metav1.GetOptions{})
 		if err != nil {
 			c.recordEventErrorf(canary, "Error fetching Knative service %s/%s %v", canary.Namespace, canary.Spec.TargetRef.Name, err)
-			return false
+			return analysisFailed
 		}
 	}
 
 	// create observer based on the mesh provider
-	observerFactory := c.observerFactory
-
-	// override the global metrics server if one is specified in the canary spec
-	if canary.Spec.MetricsServer != "" {
-		var err error
-		observerFactory, err = observers.NewFactory(canary.Spec.MetricsServer)
-		if err != nil {
-			c.recordEventErrorf(canary, "Error building Prometheus client for %s %v", canary.Spec.MetricsServer, err)
-			return false
-		}
+	observerFactory, err := c.getObserverFactory(canary)
+	if err != nil {
+		c.recordEventErrorf(canary, "Error building Prometheus client for %s %v", canary.Spec.MetricsServer, err)
+		return analysisFailed
 	}
 	observer := observerFactory.Observer(metricsProvider)
 
 	// run metrics checks
+	result := analysisPassed
 	for _, metric := range canary.GetAnalysis().Metrics {
 		if metric.Interval == "" {
 			metric.Interval = canary.GetMetricInterval()
 		}
 
-		if metric.Name == "request-success-rate" {
-			model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
-			if knativeService != nil {
-				model.Route = knativeService.Status.LatestCreatedRevisionName
+		check := c.runBuiltinMetricCheck(canary, metric, observerFactory, observer, metricsProvider, knativeService)
+		result = mergeAnalysisResults(result, c.runMetricFailurePolicy(canary, metric, check))
+	}
+
+	return result
+}
+
+// runBuiltinMetricCheck checks the builtin metric or the in-line query of the canary metric
+func (c *Controller) runBuiltinMetricCheck(canary *flaggerv1.Canary, metric flaggerv1.CanaryMetric,
+	observerFactory *observers.Factory, observer observers.Interface, metricsProvider string,
+	knativeService *serving.Service) metricCheckResult {
+	if metric.Name == "request-success-rate" {
+		model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
+		if knativeService != nil {
+			model.Route = knativeService.Status.LatestCreatedRevisionName
+		}
+		val, err := observer.GetRequestSuccessRate(model)
+		if err != nil {
+			if errors.Is(err, providers.ErrNoValuesFound) {
+				c.recordEventWarningf(canary,
+					"Halt advancement no values found for %s metric %s probably %s.%s is not receiving traffic: %v",
+					metricsProvider, metric.Name, canary.Spec.TargetRef.Name, canary.Namespace, err)
+			} else {
+				c.recordEventErrorf(canary, "Prometheus query failed: %v", err)
 			}
-			val, err := observer.GetRequestSuccessRate(model)
-			if err != nil {
-				if errors.Is(err, providers.ErrNoValuesFound) {
-					c.recordEventWarningf(canary,
-						"Halt advancement no values found for %s metric %s probably %s.%s is not receiving traffic: %v",
-						metricsProvider, metric.Name, canary.Spec.TargetRef.Name, canary.Namespace, err)
-				} else {
-					c.recordEventErrorf(canary, "Prometheus query failed: %v", err)
-				}
-				return false
+			return metricCheckErrored
+		}
+		c.recorder.SetAnalysis(canary, metric.Name, val)
+		if metric.Comparison != nil {
+			if result := c.runMetricComparison(canary, metric, val, observer.GetRequestSuccessRate); result != metricCheckPassed {
+				return result
 			}
-			c.recorder.SetAnalysis(canary, metric.Name, val)
-			if metric.ThresholdRange != nil {
-				tr := *metric.ThresholdRange
-				if tr.Min != nil && val < *tr.Min {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement success rate %.2f%% < %v%%",
-						canary.Name, canary.Namespace, val, *tr.Min)
-					return false
-				}
-				if tr.Max != nil && val > *tr.Max {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement success rate %.2f%% > %v%%",
-						canary.Name, canary.Namespace, val, *tr.Max)
-					return false
-				}
-			} else if metric.Threshold > val {
+		}
+		if metric.ThresholdRange != nil {
+			tr := *metric.ThresholdRange
+			if tr.Min != nil && val < *tr.Min {
 				c.recordEventWarningf(canary, "Halt %s.%s advancement success rate %.2f%% < %v%%",
-					canary.Name, canary.Namespace, val, metric.Threshold)
-				return false
+					canary.Name, canary.Namespace, val, *tr.Min)
+				return metricCheckBreached
 			}
+			if tr.Max != nil && val > *tr.Max {
+				c.recordEventWarningf(canary, "Halt %s.%s advancement success rate %.2f%% > %v%%",
+					canary.Name, canary.Namespace, val, *tr.Max)
+				return metricCheckBreached
+			}
+		} else if metric.Comparison == nil && metric.Threshold > val {
+			c.recordEventWarningf(canary, "Halt %s.%s advancement success rate %.2f%% < %v%%",
+				canary.Name, canary.Namespace, val, metric.Threshold)
+			return metricCheckBreached
 		}
+	}
 
-		if metric.Name == "request-duration" {
-			model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
-			if knativeService != nil {
-				model.Route = knativeService.Status.LatestCreatedRevisionName
+	if metric.Name == "request-duration" {
+		model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
+		if knativeService != nil {
+			model.Route = knativeService.Status.LatestCreatedRevisionName
+		}
+		val, err := observer.GetRequestDuration(model)
+		if err != nil {
+			if errors.Is(err, providers.ErrNoValuesFound) {
+				c.recordEventWarningf(canary, "Halt advancement no values found for %s metric %s probably %s.%s is not receiving traffic",
+					metricsProvider, metric.Name, canary.Spec.TargetRef.Name, canary.Namespace)
+			} else {
+				c.recordEventErrorf(canary, "Prometheus query failed: %v", err)
 			}
-			val, err := observer.GetRequestDuration(model)
-			if err != nil {
-				if errors.Is(err, providers.ErrNoValuesFound) {
-					c.recordEventWarningf(canary, "Halt advancement no values found for %s metric %s probably %s.%s is not receiving traffic",
-						metricsProvider, metric.Name, canary.Spec.TargetRef.Name, canary.Namespace)
-				} else {
-					c.recordEventErrorf(canary, "Prometheus query failed: %v", err)
-				}
-				return false
+			return metricCheckErrored
+		}
+		c.recorder.SetAnalysis(canary, metric.Name, val.Seconds())
+		if metric.Comparison != nil {
+			query := func(model flaggerv1.MetricTemplateModel) (float64, error) {
+				d, err := observer.GetRequestDuration(model)
+				return toMilliseconds(d), err
 			}
-			c.recorder.SetAnalysis(canary, metric.Name, val.Seconds())
-			if metric.ThresholdRange != nil {
-				tr := *metric.ThresholdRange
-				if tr.Min != nil && val < time.Duration(*tr.Min)*time.Millisecond {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement request duration %v < %v",
-						canary.Name, canary.Namespace, val, time.Duration(*tr.Min)*time.Millisecond)
-					return false
-				}
-				if tr.Max != nil && val > time.Duration(*tr.Max)*time.Millisecond {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement request duration %v > %v",
-						canary.Name, canary.Namespace, val, time.Duration(*tr.Max)*time.Millisecond)
-					return false
-				}
-			} else if val > time.Duration(metric.Threshold)*time.Millisecond {
+			if result := c.runMetricComparison(canary, metric, toMilliseconds(val), query); result != metricCheckPassed {
+				return result
+			}
+		}
+		if metric.ThresholdRange != nil {
+			tr := *metric.ThresholdRange
+			if tr.Min != nil && val < time.Duration(*tr.Min)*time.Millisecond {
+				c.recordEventWarningf(canary, "Halt %s.%s advancement request duration %v < %v",
+					canary.Name, canary.Namespace, val, time.Duration(*tr.Min)*time.Millisecond)
+				return metricCheckBreached
+			}
+			if tr.Max != nil && val > time.Duration(*tr.Max)*time.Millisecond {
 				c.recordEventWarningf(canary, "Halt %s.%s advancement request duration %v > %v",
-					canary.Name, canary.Namespace, val, time.Duration(metric.Threshold)*time.Millisecond)
-				return false
+					canary.Name, canary.Namespace, val, time.Duration(*tr.Max)*time.Millisecond)
+				return metricCheckBreached
 			}
+		} else if metric.Comparison == nil && val > time.Duration(metric.Threshold)*time.Millisecond {
+			c.recordEventWarningf(canary, "Halt %s.%s advancement request duration %v > %v",
+				canary.Name, canary.Namespace, val, time.Duration(metric.Threshold)*time.Millisecond)
+			return metricCheckBreached
 		}
+	}
 
-		// in-line PromQL
-		if metric.Query != "" {
-			model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
-			if knativeService != nil {
-				model.Route = knativeService.Status.LatestCreatedRevisionName
+	// in-line PromQL, scored along with the metric templates when statistical scoring is enabled
+	if metric.Query != "" && canary.GetAnalysis().Scoring == nil {
+		model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
+		if knativeService != nil {
+			model.Route = knativeService.Status.LatestCreatedRevisionName
+		}
+		query, err := observers.RenderQuery(metric.Query, model)
+		val, err := observerFactory.Client.RunQuery(query)
+		if err != nil {
+			if errors.Is(err, providers.ErrNoValuesFound) {
+				c.recordEventWarningf(canary, "Halt advancement no values found for metric: %s",
+					metric.Name)
+			} else {
+				c.recordEventErrorf(canary, "Prometheus query failed for %s: %v", metric.Name, err)
 			}
-			query, err := observers.RenderQuery(metric.Query, model)
-			val, err := observerFactory.Client.RunQuery(query)
-			if err != nil {
-				if errors.Is(err, providers.ErrNoValuesFound) {
-					c.recordEventWarningf(canary, "Halt advancement no values found for metric: %s",
-						metric.Name)
-				} else {
-					c.recordEventErrorf(canary, "Prometheus query failed for %s: %v", metric.Name, err)
+			return metricCheckErrored
+		}
+		c.recorder.SetAnalysis(canary, metric.Name, val)
+		if metric.Comparison != nil {
+			query := func(model flaggerv1.MetricTemplateModel) (float64, error) {
+				q, err := observers.RenderQuery(metric.Query, model)
+				if err != nil {
+					return 0, err
 				}
-				return false
+				return observerFactory.Client.RunQuery(q)
 			}
-			c.recorder.SetAnalysis(canary, metric.Name, val)
-			if metric.ThresholdRange != nil {
-				tr := *metric.ThresholdRange
-				if tr.Min != nil && val < *tr.Min {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f < %v",
-						canary.Name, canary.Namespace, metric.Name, val, *tr.Min)
-					return false
-				}
-				if tr.Max != nil && val > *tr.Max {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f > %v",
-						canary.Name, canary.Namespace, metric.Name, val, *tr.Max)
-					return false
-				}
-			} else if val > metric.Threshold {
+			if result := c.runMetricComparison(canary, metric, val, query); result != metricCheckPassed {
+				return result
+			}
+		}
+		if metric.ThresholdRange != nil {
+			tr := *metric.ThresholdRange
+			if tr.Min != nil && val < *tr.Min {
+				c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f < %v",
+					canary.Name, canary.Namespace, metric.Name, val, *tr.Min)
+				return metricCheckBreached
+			}
+			if tr.Max != nil && val > *tr.Max {
 				c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f > %v",
-					canary.Name, canary.Namespace, metric.Name, val, metric.Threshold)
-				return false
+					canary.Name, canary.Namespace, metric.Name, val, *tr.Max)
+				return metricCheckBreached
 			}
+		} else if metric.Comparison == nil && val > metric.Threshold {
+			c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f > %v",
+				canary.Name, canary.Namespace, metric.Name, val, metric.Threshold)
+			return metricCheckBreached
 		}
 	}
 
-	return true
+	return metricCheckPassed
 }
 
-func (c *Controller) runMetricChecks(canary *flaggerv1.Canary) bool {
+// runMetricChecks checks the metric templates, all the metrics are checked and
+// the threshold breaches are handled according to their failure policy
+func (c *Controller) runMetricChecks(canary *flaggerv1.Canary) analysisResult {
 	var knativeService *serving.Service
 	if canary.Spec.Provider == flaggerv1.KnativeProvider || c.meshProvider == flaggerv1.KnativeProvider {
 		var err error
 		knativeService, err = c.knativeClient.ServingV1().Services(canary.Namespace).Get(context.TODO(), canary.Spec.TargetRef.Name, metav1.GetOptions{})
 		if err != nil {
 			c.recordEventErrorf(canary, "Error fetching Knative service %s/%s %v", canary.Namespace, canary.Spec.TargetRef.Name, err)
-			return false
+			return analysisFailed
 		}
 	}
 
+	result := analysisPassed
 	for _, metric := range canary.GetAnalysis().Metrics {
-		if metric.TemplateRef != nil {
-			namespace := canary.Namespace
-			if metric.TemplateRef.Namespace != canary.Namespace && metric.TemplateRef.Namespace != "" {
-				namespace = metric.TemplateRef.Namespace
-			}
+		check := c.runMetricTemplateCheck(canary, metric, knativeService)
+		result = mergeAnalysisResults(result, c.runMetricFailurePolicy(canary, metric, check))
+	}
+
+	return result
+}
 
-			template, err := c.flaggerInformers.MetricInformer.Lister().MetricTemplates(namespace).Get(metric.TemplateRef.Name)
+// runMetricTemplateCheck checks the metric template of the canary metric
+func (c *Controller) runMetricTemplateCheck(canary *flaggerv1.Canary, metric flaggerv1.CanaryMetric,
+	knativeService *serving.Service) metricCheckResult {
+	if metric.TemplateRef != nil {
+		namespace := canary.Namespace
+		if metric.TemplateRef.Namespace != canary.Namespace && metric.TemplateRef.Namespace != "" {
+			namespace = metric.TemplateRef.Namespace
+		}
+
+		template, err := c.flaggerInformers.MetricInformer.Lister().MetricTemplates(namespace).Get(metric.TemplateRef.Name)
+		if err != nil {
+			c.recordEventErrorf(canary, "Metric template %s.%s error: %v", metric.TemplateRef.Name, namespace, err)
+			return metricCheckErrored
+		}
+
+		var credentials map[string][]byte
+		if template.Spec.Provider.SecretRef != nil {
+			secret, err := c.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), template.Spec.Provider.SecretRef.Name, metav1.GetOptions{})
 			if err != nil {
-				c.recordEventErrorf(canary, "Metric template %s.%s error: %v", metric.TemplateRef.Name, namespace, err)
-				return false
+				c.recordEventErrorf(canary, "Metric template %s.%s secret %s error: %v",
+					metric.TemplateRef.Name, namespace, template.Spec.Provider.SecretRef.Name, err)
+				return metricCheckErrored
 			}
+			credentials = secret.Data
+		}
 
-			var credentials map[string][]byte
-			if template.Spec.Provider.SecretRef != nil {
-				secret, err := c.kubeClient.CoreV1().Secrets(namespace).Get(context.TODO(), template.Spec.Provider.SecretRef.Name, metav1.GetOptions{})
+		factory := providers.Factory{}
+		provider, err := factory.Provider(metric.Interval, template.Spec.Provider, credentials, c.kubeConfig)
+		if err != nil {
+			c.recordEventErrorf(canary, "Metric template %s.%s provider %s error: %v",
+				metric.TemplateRef.Name, namespace, template.Spec.Provider.Type, err)
+			return metricCheckErrored
+		}
+
+		model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
+		if knativeService != nil {
+			model.Route = knativeService.Status.LatestCreatedRevisionName
+		}
+		query, err := observers.RenderQuery(template.Spec.Query, model)
+		c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, namespace)).
+			Debugf("Metric template %s.%s query: %s", metric.TemplateRef.Name, namespace, query)
+		if err != nil {
+			c.recordEventErrorf(canary, "Metric template %s.%s query render error: %v",
+				metric.TemplateRef.Name, namespace, err)
+			return metricCheckErrored
+		}
+
+		val, err := provider.RunQuery(query)
+		if err != nil {
+			if errors.Is(err, providers.ErrNoValuesFound) {
+				c.recordEventWarningf(canary, "Halt advancement no values found for custom metric: %s: %v",
+					metric.Name, err)
+			} else {
+				c.recordEventErrorf(canary, "Metric query failed for %s: %v", metric.Name, err)
+			}
+			return metricCheckErrored
+		}
+
+		c.recorder.SetAnalysis(canary, metric.Name, val)
+
+		if metric.Comparison != nil {
+			query := func(model flaggerv1.MetricTemplateModel) (float64, error) {
+				q, err := observers.RenderQuery(template.Spec.Query, model)
 				if err != nil {
-					c.recordEventErrorf(canary, "Metric template %s.%s secret %s error: %v",
-						metric.TemplateRef.Name, namespace, template.Spec.Provider.SecretRef.Name, err)
-					return false
+					return 0, err
 				}
-				credentials = secret.Data
+				return provider.RunQuery(q)
+			}
+			if result := c.runMetricComparison(canary, metric, val, query); result != metricCheckPassed {
+				return result
+			}
+		}
+		if metric.ThresholdRange != nil {
+			tr := *metric.ThresholdRange
+			if tr.Min != nil && val < *tr.Min {
+				c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f < %v",
+					canary.Name, canary.Namespace, metric.Name, val, *tr.Min)
+				return metricCheckBreached
 			}
+			if tr.Max != nil && val > *tr.Max {
+				c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f > %v",
+					canary.Name, canary.Namespace, metric.Name, val, *tr.Max)
+				return metricCheckBreached
+			}
+		} else if metric.Comparison == nil && val > metric.Threshold {
+			c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f > %v",
+				canary.Name, canary.Namespace, metric.Name, val, metric.Threshold)
+			return metricCheckBreached
+		}
+	} else if metric.Name != "request-success-rate" && metric.Name != "request-duration" && metric.Query == "" {
+		c.recordEventErrorf(canary, "Metric query failed for no usable metrics template and query were configured")
+		return metricCheckErrored
+	}
+
+	return metricCheckPassed
+}
 
-			factory := providers.Factory{}
-			provider, err := factory.Provider(metric.Interval, template.Spec.Provider, credentials, c.kubeConfig)
+// runMetricScoring compares the primary (or baseline) and canary time series of the metric templates and
+// in-line queries over the last analysis interval using the Mann-Whitney U test, the analysis
+// passes, halts or fails based on the percentage of metrics that didn't regress
+func (c *Controller) runMetricScoring(canary *flaggerv1.Canary) analysisResult {
+	spec := canary.GetAnalysis().Scoring
+	end := time.Now()
+	start := end.Add(-canary.GetAnalysisInterval())
+
+	// override the global metrics server if one is specified in the canary spec
+	observerFactory, err := c.getObserverFactory(canary)
+	if err != nil {
+		c.recordEventErrorf(canary, "Error building Prometheus client for %s %v", canary.Spec.MetricsServer, err)
+		return analysisFailed
+	}
+
+	var passed, total int
+	for _, metric := range canary.GetAnalysis().Metrics {
+		if metric.Interval == "" {
+			metric.Interval = canary.GetMetricInterval()
+		}
+
+		var provider providers.Interface
+		var query string
+		switch {
+		case metric.TemplateRef != nil:
+			template, templateProvider, err := c.getMetricTemplateProvider(canary, metric)
 			if err != nil {
-				c.recordEventErrorf(canary, "Metric template %s.%s provider %s error: %v",
-					metric.TemplateRef.Name, namespace, template.Spec.Provider.Type, err)
-				return false
+				c.recordEventErrorf(canary, "%v", err)
+				return analysisFailed
 			}
+			provider, query = templateProvider, template.Spec.Query
+		case metric.Query != "":
+			provider, query = observerFactory.Client, metric.Query
+		default:
+			continue
+		}
 
-			model := toMetricModel(canary, metric.Interval, metric.TemplateVariables)
-			if knativeService != nil {
-				model.Route = knativeService.Status.LatestCreatedRevisionName
-			}
-			query, err := observers.RenderQuery(template.Spec.Query, model)
-			c.logger.With("canary", fmt.Sprintf("%s.%s", canary.Name, namespace)).
-				Debugf("Metric template %s.%s query: %s", metric.TemplateRef.Name, namespace, query)
+		rangeProvider, ok := provider.(providers.RangeInterface)
+		if !ok {
+			c.recordEventErrorf(canary, "Metric %s provider doesn't support range queries required for scoring", metric.Name)
+			return analysisFailed
+		}
+
+		var series [2][]float64
+		for i, model := range []flaggerv1.MetricTemplateModel{
+			toControlMetricModel(canary, metric.Interval, metric.TemplateVariables),
+			toMetricModel(canary, metric.Interval, metric.TemplateVariables),
+		} {
+			q, err := observers.RenderQuery(query, model)
 			if err != nil {
-				c.recordEventErrorf(canary, "Metric template %s.%s query render error: %v",
-					metric.TemplateRef.Name, namespace, err)
-				return false
+				c.recordEventErrorf(canary, "Metric %s query render error: %v", metric.Name, err)
+				return analysisFailed
 			}
-
-			val, err := provider.RunQuery(query)
+			series[i], err = rangeProvider.RunRangeQuery(q, start, end, spec.GetStep())
 			if err != nil {
 				if errors.Is(err, providers.ErrNoValuesFound) {
-					c.recordEventWarningf(canary, "Halt advancement no values found for custom metric: %s: %v",
-						metric.Name, err)
+					c.recordEventWarningf(canary, "Halt advancement no values found for metric %s probably %s.%s is not receiving traffic: %v",
+						metric.Name, model.Target, canary.Namespace, err)
 				} else {
-					c.recordEventErrorf(canary, "Metric query failed for %s: %v", metric.Name, err)
+					c.recordEventErrorf(canary, "Metric query failed for %s on %s.%s: %v", metric.Name, model.Target, canary.Namespace, err)
 				}
-				return false
+				return analysisFailed
 			}
+		}
 
-			c.recorder.SetAnalysis(canary, metric.Name, val)
+		primaryMedian, canaryMedian := scoring.Median(series[0]), scoring.Median(series[1])
+		delta, _ := compareMetric(metric.GetComparisonDirection(), primaryMedian, canaryMedian)
+		c.recorder.SetAnalysis(canary, metric.Name, canaryMedian)
+		c.recorder.SetComparison(canary, metric.Name, primaryMedian, canaryMedian, delta)
 
-			if metric.ThresholdRange != nil {
-				tr := *metric.ThresholdRange
-				if tr.Min != nil && val < *tr.Min {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f < %v",
-						canary.Name, canary.Namespace, metric.Name, val, *tr.Min)
-					return false
-				}
-				if tr.Max != nil && val > *tr.Max {
-					c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f > %v",
-						canary.Name, canary.Namespace, metric.Name, val, *tr.Max)
-					return false
-				}
-			} else if val > metric.Threshold {
-				c.recordEventWarningf(canary, "Halt %s.%s advancement %s %.2f > %v",
-					canary.Name, canary.Namespace, metric.Name, val, metric.Threshold)
-				return false
-			}
-		} else if metric.Name != "request-success-rate" && metric.Name != "request-duration" && metric.Query == "" {
-			c.recordEventErrorf(canary, "Metric query failed for no usable metrics template and query were configured")
-			return false
+		total++
+		classification, p := scoring.Classify(series[0], series[1], spec.GetConfidenceLevel())
+		if isRegression(metric, classification, primaryMedian, canaryMedian) {
+			c.recordEventWarningf(canary, "Metric %s regressed canary median %.2f primary median %.2f p-value %.4f",
+				metric.Name, canaryMedian, primaryMedian, p)
+			continue
 		}
+		passed++
 	}
 
-	return true
+	// a score without metrics would always pass
+	if total == 0 {
+		c.recordEventWarningf(canary, "Halt %s.%s advancement no metric templates or queries were scored",
+			canary.Name, canary.Namespace)
+		return analysisFailed
+	}
+
+	score := scoring.Score(passed, total)
+	c.recorder.SetScore(canary, score)
+
+	switch scoring.Judge(score, spec.GetPass(), spec.GetMarginal()) {
+	case scoring.VerdictPass:
+		return analysisPassed
+	case scoring.VerdictMarginal:
+		c.recordEventWarningf(canary, "Halt %s.%s advancement marginal score %.0f < %v",
+			canary.Name, canary.Namespace, score, spec.GetPass())
+		return analysisHalted
+	default:
+		c.recordEventWarningf(canary, "Halt %s.%s advancement score %.0f < %v",
+			canary.Name, canary.Namespace, score, spec.GetMarginal())
+		return analysisFailed
+	}
+}
+
+// isRegression returns true if the canary is significantly worse than the primary
+// and the difference between the medians exceeds the comparison bounds when set
+func isRegression(metric flaggerv1.CanaryMetric, classification scoring.Classification, primaryMedian float64, canaryMedian float64) bool {
+	direction := metric.GetComparisonDirection()
+	switch {
+	case classification == scoring.High && direction == flaggerv1.ComparisonIncrease:
+	case classification == scoring.Low && direction == flaggerv1.ComparisonDecrease:
+	default:
+		return false
+	}
+
+	if metric.Comparison == nil {
+		return true
+	}
+	delta, ratio := compareMetric(direction, primaryMedian, canaryMedian)
+	if maxDelta := metric.Comparison.MaxDelta; maxDelta != nil && delta > *maxDelta {
+		return true
+	}
+	if maxRatio := metric.Comparison.MaxRatio; maxRatio != nil && ratio > *maxRatio {
+		return true
+	}
+	return false
+}
+
+// runMetricComparison runs the metric query for the primary (or baseline) workload and halts the
+// advancement if the canary value regressed beyond the max ratio or delta
+func (c *Controller) runMetricComparison(canary *flaggerv1.Canary, metric flaggerv1.CanaryMetric, canaryVal float64,
+	query func(model flaggerv1.MetricTemplateModel) (float64, error)) metricCheckResult {
+	model := toControlMetricModel(canary, metric.Interval, metric.TemplateVariables)
+	primaryVal, err := query(model)
+	if err != nil {
+		if errors.Is(err, providers.ErrNoValuesFound) {
+			c.recordEventWarningf(canary, "Halt advancement no values found for metric %s probably %s.%s is not receiving traffic: %v",
+				metric.Name, model.Target, canary.Namespace, err)
+		} else {
+			c.recordEventErrorf(canary, "Metric query failed for %s on %s.%s: %v", metric.Name, model.Target, canary.Namespace, err)
+		}
+		return metricCheckErrored
+	}
+
+	delta, ratio := compareMetric(metric.GetComparisonDirection(), primaryVal, canaryVal)
+	c.recorder.SetComparison(canary, metric.Name, primaryVal, canaryVal, delta)
+
+	if maxDelta := metric.Comparison.MaxDelta; maxDelta != nil && delta > *maxDelta {
+		c.recordEventWarningf(canary, "Halt %s.%s advancement %s canary %.2f primary %.2f delta %.2f > %v",
+			canary.Name, canary.Namespace, metric.Name, canaryVal, primaryVal, delta, *maxDelta)
+		return metricCheckBreached
+	}
+	if maxRatio := metric.Comparison.MaxRatio; maxRatio != nil && ratio > *maxRatio {
+		c.recordEventWarningf(canary, "Halt %s.%s advancement %s canary %.2f primary %.2f delta %.2f ratio %.2f > %v",
+			canary.Name, canary.Namespace, metric.Name, canaryVal, primaryVal, delta, ratio, *maxRatio)
+		return metricCheckBreached
+	}
+
+	return metricCheckPassed
+}
+
+// compareMetric returns the difference and the ratio between the canary and the primary
+// values oriented so that positive deltas and ratios above one are regressions,
+// a regression from a zero value has an infinite ratio
+func compareMetric(direction flaggerv1.ComparisonDirection, primaryVal float64, canaryVal float64) (float64, float64) {
+	worse, better := canaryVal, primaryVal
+	if direction == flaggerv1.ComparisonDecrease {
+		worse, better = primaryVal, canaryVal
+	}
+
+	delta := worse - better
+	if better == 0 {
+		if worse == 0 {
+			return delta, 1
+		}
+		return delta, math.Copysign(math.Inf(1), worse)
+	}
+	return delta, worse / better
+}
+
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// toControlMetricModel returns the model for the workload the canary is compared against,
+// the baseline when enabled or else the primary
+func toControlMetricModel(r *flaggerv1.Canary, interval string, variables map[string]string) flaggerv1.MetricTemplateModel {
+	model := toPrimaryMetricModel(r, interval, variables)
+	if r.HasBaseline() {
+		model.Target = model.Baseline
+	}
+	return model
+}
+
+// toPrimaryMetricModel returns the model for the primary workload
+func toPrimaryMetricModel(r *flaggerv1.Canary, interval string, variables map[string]string) flaggerv1.MetricTemplateModel {
+	model := toMetricModel(r, interval, variables)
+	model.Target = fmt.Sprintf("%s-primary", r.Spec.TargetRef.Name)
+	model.Targets = targetNames(r, "-primary")
+	return model
 }
 
 func toMetricModel(r *flaggerv1.Canary, interval string, variables map[string]string) flaggerv1.MetricTemplateModel {
@@ -369,6 +661,8 @@ func toMetricModel(r *flaggerv1.Canary, interval string, variables map[string]st
 		Name:      r.Name,
 		Namespace: r.Namespace,
 		Target:    r.Spec.TargetRef.Name,
+		Targets:   targetNames(r, ""),
+		Baseline:  fmt.Sprintf("%s-baseline", r.Spec.TargetRef.Name),
 		Service:   service,
 		Ingress:   ingress,
 		Route:     route,
@@ -376,3 +670,13 @@ func toMetricModel(r *flaggerv1.Canary, interval string, variables map[string]st
 		Variables: variables,
 	}
 }
+
+// targetNames returns the names of the target and additional targets with the suffix
+// as a regular expression alternation, for matching all the canary workloads in a query
+func targetNames(r *flaggerv1.Canary, suffix string) string {
+	names := []string{r.Spec.TargetRef.Name + suffix}
+	for _, ref := range r.Spec.AdditionalTargetRefs {
+		names = append(names, ref.Name+suffix)
+	}
+	return strings.Join(names, "|")
+}
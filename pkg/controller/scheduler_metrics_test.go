@@ -17,6 +17,11 @@ limitations under the License.
 package controller
 
 import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -106,7 +111,7 @@ func TestController_runMetricChecks(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
 			Spec:       flaggerv1.CanarySpec{Analysis: analysis},
 		}
-		assert.Equal(t, true, ctrl.runMetricChecks(canary))
+		assert.Equal(t, analysisPassed, ctrl.runMetricChecks(canary))
 	})
 
 	t.Run("undefined metric", func(t *testing.T) {
@@ -122,7 +127,7 @@ func TestController_runMetricChecks(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
 			Spec:       flaggerv1.CanarySpec{Analysis: analysis},
 		}
-		assert.Equal(t, false, ctrl.runMetricChecks(canary))
+		assert.Equal(t, analysisFailed, ctrl.runMetricChecks(canary))
 	})
 
 	t.Run("builtinMetric", func(t *testing.T) {
@@ -138,7 +143,7 @@ func TestController_runMetricChecks(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
 			Spec:       flaggerv1.CanarySpec{Analysis: analysis},
 		}
-		assert.Equal(t, true, ctrl.runMetricChecks(canary))
+		assert.Equal(t, analysisPassed, ctrl.runMetricChecks(canary))
 	})
 
 	t.Run("no metric Template is defined, but a query is specified", func(t *testing.T) {
@@ -155,7 +160,7 @@ func TestController_runMetricChecks(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
 			Spec:       flaggerv1.CanarySpec{Analysis: analysis},
 		}
-		assert.Equal(t, true, ctrl.runMetricChecks(canary))
+		assert.Equal(t, analysisPassed, ctrl.runMetricChecks(canary))
 	})
 
 	t.Run("both have metric Template and query", func(t *testing.T) {
@@ -180,6 +185,269 @@ func TestController_runMetricChecks(t *testing.T) {
 			ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
 			Spec:       flaggerv1.CanarySpec{Analysis: analysis},
 		}
-		assert.Equal(t, true, ctrl.runMetricChecks(canary))
+		assert.Equal(t, analysisPassed, ctrl.runMetricChecks(canary))
 	})
 }
+
+func TestController_runMetricComparison(t *testing.T) {
+	// the primary reports 100 and the canary 120
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		val := "120"
+		if strings.Contains(r.URL.Query()["query"][0], "podinfo-primary") {
+			val = "100"
+		}
+		w.Write([]byte(fmt.Sprintf(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1545905245.458,"%s"]}]}}`, val)))
+	}))
+	defer ts.Close()
+
+	newCanary := func(comparison *flaggerv1.CanaryMetricComparison) *flaggerv1.Canary {
+		return &flaggerv1.Canary{
+			ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+			Spec: flaggerv1.CanarySpec{
+				TargetRef: flaggerv1.LocalObjectReference{Name: "podinfo", Kind: "Deployment"},
+				Analysis: &flaggerv1.CanaryAnalysis{Metrics: []flaggerv1.CanaryMetric{{
+					Name:        "latency",
+					TemplateRef: &flaggerv1.CrossNamespaceObjectReference{Name: "compare", Namespace: "default"},
+					Comparison:  comparison,
+				}}},
+			},
+		}
+	}
+
+	newCtrl := func() *Controller {
+		ctrl := newDeploymentFixture(nil).ctrl
+		template := newDeploymentTestMetricTemplate()
+		template.Name = "compare"
+		template.Spec.Provider.Address = ts.URL
+		template.Spec.Provider.SecretRef = nil
+		require.NoError(t, ctrl.flaggerInformers.MetricInformer.Informer().GetIndexer().Add(template))
+		return ctrl
+	}
+
+	t.Run("within ratio", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryMetricComparison{MaxRatio: toFloatPtr(2)})
+		assert.Equal(t, analysisPassed, newCtrl().runMetricChecks(canary))
+	})
+
+	t.Run("ratio exceeded", func(t *testing.T) {
+		ratio := 1.1
+		canary := newCanary(&flaggerv1.CanaryMetricComparison{MaxRatio: &ratio})
+		assert.Equal(t, analysisFailed, newCtrl().runMetricChecks(canary))
+	})
+
+	t.Run("delta exceeded", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryMetricComparison{MaxDelta: toFloatPtr(10)})
+		assert.Equal(t, analysisFailed, newCtrl().runMetricChecks(canary))
+	})
+
+	t.Run("within ratio out of threshold range", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryMetricComparison{MaxRatio: toFloatPtr(2)})
+		canary.Spec.Analysis.Metrics[0].ThresholdRange = &flaggerv1.CanaryThresholdRange{Max: toFloatPtr(110)}
+		assert.Equal(t, analysisFailed, newCtrl().runMetricChecks(canary))
+	})
+
+	t.Run("within ratio and threshold range", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryMetricComparison{MaxRatio: toFloatPtr(2)})
+		canary.Spec.Analysis.Metrics[0].ThresholdRange = &flaggerv1.CanaryThresholdRange{Max: toFloatPtr(150)}
+		assert.Equal(t, analysisPassed, newCtrl().runMetricChecks(canary))
+	})
+
+	t.Run("decrease direction", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryMetricComparison{
+			Direction: flaggerv1.ComparisonDecrease,
+			MaxDelta:  toFloatPtr(0),
+		})
+		assert.Equal(t, analysisPassed, newCtrl().runMetricChecks(canary))
+	})
+}
+
+func TestController_compareMetric(t *testing.T) {
+	delta, ratio := compareMetric(flaggerv1.ComparisonIncrease, 100, 150)
+	assert.Equal(t, float64(50), delta)
+	assert.Equal(t, 1.5, ratio)
+
+	delta, ratio = compareMetric(flaggerv1.ComparisonDecrease, 100, 80)
+	assert.Equal(t, float64(20), delta)
+	assert.Equal(t, 1.25, ratio)
+
+	delta, ratio = compareMetric(flaggerv1.ComparisonIncrease, 0, 5)
+	assert.Equal(t, float64(5), delta)
+	assert.True(t, math.IsInf(ratio, 1))
+
+	delta, ratio = compareMetric(flaggerv1.ComparisonDecrease, 99, 0)
+	assert.Equal(t, float64(99), delta)
+	assert.True(t, math.IsInf(ratio, 1))
+
+	delta, ratio = compareMetric(flaggerv1.ComparisonIncrease, 0, 0)
+	assert.Equal(t, float64(0), delta)
+	assert.Equal(t, float64(1), ratio)
+}
+
+func TestController_runMetricScoring(t *testing.T) {
+	// the primary and the canary report similar latencies while the canary errors are twice the primary ones
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()["query"][0]
+		base := 100
+		if !strings.Contains(query, "podinfo-primary") && strings.Contains(query, "errors") {
+			base = 200
+		}
+		var values []string
+		for i := 0; i < 6; i++ {
+			values = append(values, fmt.Sprintf(`[%d,"%d"]`, 1545905200+i*10, base+i))
+		}
+		w.Write([]byte(fmt.Sprintf(`{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[%s]}]}}`,
+			strings.Join(values, ","))))
+	}))
+	defer ts.Close()
+
+	newCanary := func(scoring *flaggerv1.CanaryScoring, metrics ...string) *flaggerv1.Canary {
+		canary := &flaggerv1.Canary{
+			ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+			Spec: flaggerv1.CanarySpec{
+				TargetRef: flaggerv1.LocalObjectReference{Name: "podinfo", Kind: "Deployment"},
+				Analysis:  &flaggerv1.CanaryAnalysis{Scoring: scoring},
+			},
+		}
+		for _, metric := range metrics {
+			canary.Spec.Analysis.Metrics = append(canary.Spec.Analysis.Metrics, flaggerv1.CanaryMetric{
+				Name:              metric,
+				TemplateRef:       &flaggerv1.CrossNamespaceObjectReference{Name: "scoring", Namespace: "default"},
+				TemplateVariables: map[string]string{"metric": metric},
+			})
+		}
+		return canary
+	}
+
+	newCtrl := func() *Controller {
+		ctrl := newDeploymentFixture(nil).ctrl
+		template := newDeploymentTestMetricTemplate()
+		template.Name = "scoring"
+		template.Spec.Query = `{{ variables.metric }}{workload="{{ target }}"}`
+		template.Spec.Provider.Address = ts.URL
+		template.Spec.Provider.SecretRef = nil
+		require.NoError(t, ctrl.flaggerInformers.MetricInformer.Informer().GetIndexer().Add(template))
+		return ctrl
+	}
+
+	t.Run("pass", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryScoring{}, "latency")
+		assert.Equal(t, analysisPassed, newCtrl().runMetricScoring(canary))
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryScoring{}, "latency", "errors")
+		assert.Equal(t, analysisFailed, newCtrl().runMetricScoring(canary))
+	})
+
+	t.Run("marginal", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryScoring{Pass: toFloatPtr(100), Marginal: toFloatPtr(50)}, "latency", "errors")
+		assert.Equal(t, analysisHalted, newCtrl().runMetricScoring(canary))
+	})
+
+	t.Run("zero marginal", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryScoring{Pass: toFloatPtr(100), Marginal: toFloatPtr(0)}, "errors")
+		assert.Equal(t, analysisHalted, newCtrl().runMetricScoring(canary))
+	})
+
+	t.Run("builtin metrics only", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryScoring{})
+		canary.Spec.Analysis.Metrics = []flaggerv1.CanaryMetric{{Name: "request-success-rate", Threshold: 99}}
+		assert.Equal(t, analysisFailed, newCtrl().runMetricScoring(canary))
+	})
+
+	t.Run("within comparison bounds", func(t *testing.T) {
+		canary := newCanary(&flaggerv1.CanaryScoring{}, "errors")
+		canary.Spec.Analysis.Metrics[0].Comparison = &flaggerv1.CanaryMetricComparison{MaxRatio: toFloatPtr(3)}
+		assert.Equal(t, analysisPassed, newCtrl().runMetricScoring(canary))
+	})
+}
+
+func TestController_toMetricModelTargets(t *testing.T) {
+	canary := &flaggerv1.Canary{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+		Spec: flaggerv1.CanarySpec{
+			TargetRef: flaggerv1.LocalObjectReference{Kind: "Deployment", Name: "podinfo"},
+			AdditionalTargetRefs: []flaggerv1.LocalObjectReference{
+				{Kind: "Deployment", Name: "worker"},
+			},
+		},
+	}
+
+	assert.Equal(t, "podinfo|worker", toMetricModel(canary, "1m", nil).Targets)
+	assert.Equal(t, "podinfo-primary|worker-primary", toPrimaryMetricModel(canary, "1m", nil).Targets)
+
+	canary.Spec.AdditionalTargetRefs = nil
+	assert.Equal(t, "podinfo", toMetricModel(canary, "1m", nil).Targets)
+}
+
+func TestController_builtinMetricComparisonQueries(t *testing.T) {
+	var queries []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query()["query"][0])
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1545905245.458,"100"]}]}}`))
+	}))
+	defer ts.Close()
+
+	factory, err := observers.NewFactory(ts.URL)
+	require.NoError(t, err)
+
+	canary := &flaggerv1.Canary{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+		Spec: flaggerv1.CanarySpec{
+			TargetRef: flaggerv1.LocalObjectReference{Kind: "Deployment", Name: "podinfo"},
+			Analysis: &flaggerv1.CanaryAnalysis{Metrics: []flaggerv1.CanaryMetric{{
+				Name:       "request-success-rate",
+				Comparison: &flaggerv1.CanaryMetricComparison{MaxDelta: toFloatPtr(1)},
+			}}},
+		},
+	}
+
+	// the builtin queries of these providers select the primary by its name
+	for _, provider := range []string{
+		flaggerv1.IstioProvider,
+		flaggerv1.LinkerdProvider,
+		flaggerv1.AppMeshProvider,
+		flaggerv1.KubernetesProvider,
+		flaggerv1.GatewayAPIProvider,
+		flaggerv1.OsmProvider,
+	} {
+		t.Run(provider, func(t *testing.T) {
+			require.NoError(t, verifyMetricComparison(canary, provider))
+
+			observer := factory.Observer(provider)
+			for _, model := range []flaggerv1.MetricTemplateModel{
+				toMetricModel(canary, "1m", nil),
+				toPrimaryMetricModel(canary, "1m", nil),
+			} {
+				queries = nil
+				_, err := observer.GetRequestSuccessRate(model)
+				require.NoError(t, err)
+				_, err = observer.GetRequestDuration(model)
+				require.NoError(t, err)
+				require.Len(t, queries, 2)
+				if model.Target == "podinfo" {
+					assert.NotContains(t, strings.Join(queries, " "), "podinfo-primary")
+				} else {
+					assert.Contains(t, queries[0], "podinfo-primary")
+					assert.Contains(t, queries[1], "podinfo-primary")
+				}
+			}
+		})
+	}
+
+	// the builtin queries of these providers select the canary by its route, ingress or upstream
+	for _, provider := range []string{
+		flaggerv1.ContourProvider,
+		flaggerv1.GlooProvider,
+		flaggerv1.NGINXProvider,
+		flaggerv1.SkipperProvider,
+		flaggerv1.TraefikProvider,
+		flaggerv1.KumaProvider,
+		flaggerv1.ApisixProvider,
+		flaggerv1.KnativeProvider,
+	} {
+		t.Run(provider, func(t *testing.T) {
+			require.Error(t, verifyMetricComparison(canary, provider))
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+// canarySchedule holds the analysis interval and the next run time of a canary
+type canarySchedule struct {
+	name      string
+	namespace string
+	interval  time.Duration
+	due       time.Time
+}
+
+// canaryQueue schedules the analysis runs of all canaries on a single delay queue,
+// a canary is processed by one worker at a time and is added back to the queue
+// after each run with a random jitter to spread the load on the Kubernetes API
+type canaryQueue struct {
+	queue     workqueue.DelayingInterface
+	jitter    float64
+	mu        sync.Mutex
+	schedules map[string]*canarySchedule
+}
+
+func newCanaryQueue(jitter float64) *canaryQueue {
+	return &canaryQueue{
+		queue:     workqueue.NewNamedDelayingQueue("flagger-scheduler"),
+		jitter:    jitter,
+		schedules: make(map[string]*canarySchedule),
+	}
+}
+
+// schedule adds a new canary to the queue or updates the analysis interval of a scheduled one,
+// new canaries and canaries with a changed interval are run within the jitter of their interval
+func (q *canaryQueue) schedule(key string, name string, namespace string, interval time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if s, ok := q.schedules[key]; ok {
+		if s.interval == interval {
+			return
+		}
+		s.interval = interval
+		q.addAfter(key, s, q.randomJitter(interval))
+		return
+	}
+
+	s := &canarySchedule{name: name, namespace: namespace, interval: interval}
+	q.schedules[key] = s
+	q.addAfter(key, s, q.randomJitter(interval))
+}
+
+// unschedule removes the canary from the queue, a pending run is discarded when it's due
+func (q *canaryQueue) unschedule(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.schedules, key)
+}
+
+// keys returns the scheduled canaries
+func (q *canaryQueue) keys() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	keys := make([]string, 0, len(q.schedules))
+	for key := range q.schedules {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// get blocks until a canary is due and returns its key and schedule,
+// it returns false when the queue is shutting down
+func (q *canaryQueue) get() (string, canarySchedule, bool) {
+	for {
+		item, shutdown := q.queue.Get()
+		if shutdown {
+			return "", canarySchedule{}, false
+		}
+
+		key := item.(string)
+		q.mu.Lock()
+		s, ok := q.schedules[key]
+		if ok {
+			current := *s
+			s.due = time.Time{}
+			q.mu.Unlock()
+			return key, current, true
+		}
+		q.mu.Unlock()
+		q.queue.Done(item)
+	}
+}
+
+// trigger runs the canary after the delay unless it's already due earlier,
+// the runs triggered within the delay are merged into a single one
+func (q *canaryQueue) trigger(key string, delay time.Duration) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	s, ok := q.schedules[key]
+	if !ok {
+		return false
+	}
+	q.addAfter(key, s, delay)
+	return true
+}
+
+// done marks the canary run as finished and schedules the next run
+func (q *canaryQueue) done(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queue.Done(key)
+	if s, ok := q.schedules[key]; ok {
+		q.addAfter(key, s, s.interval+q.randomJitter(s.interval))
+	}
+}
+
+// len returns the number of canaries due for analysis waiting for a worker
+func (q *canaryQueue) len() int {
+	return q.queue.Len()
+}
+
+func (q *canaryQueue) shutDown() {
+	q.queue.ShutDown()
+}
+
+// addAfter adds the canary to the queue, the delay queue keeps a single
+// pending run per canary at the earliest due time
+func (q *canaryQueue) addAfter(key string, s *canarySchedule, delay time.Duration) {
+	due := time.Now().Add(delay)
+	if s.due.IsZero() || due.Before(s.due) {
+		s.due = due
+	}
+	q.queue.AddAfter(key, delay)
+}
+
+func (q *canaryQueue) randomJitter(interval time.Duration) time.Duration {
+	if q.jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Float64() * q.jitter * float64(interval))
+}
+
+// runSchedulerWorker advances the canaries due for analysis until the queue is shut down
+func (c *Controller) runSchedulerWorker() {
+	for c.processNextCanary() {
+	}
+}
+
+func (c *Controller) processNextCanary() bool {
+	key, s, ok := c.scheduler.get()
+	if !ok {
+		return false
+	}
+	defer c.scheduler.done(key)
+
+	c.recorder.SetQueueLag(time.Since(s.due))
+	c.recorder.SetQueueDepth(c.scheduler.len())
+	c.advanceCanary(s.name, s.namespace)
+	return true
+}
+
+// getCanary returns a copy of the canary from the informer cache,
+// the Kubernetes API is queried if the cache has not been synced
+func (c *Controller) getCanary(name string, namespace string) (*flaggerv1.Canary, error) {
+	informer := c.flaggerInformers.CanaryInformer
+	if informer.Informer().HasSynced() {
+		cd, err := informer.Lister().Canaries(namespace).Get(name)
+		if err != nil {
+			return nil, err
+		}
+		return cd.DeepCopy(), nil
+	}
+	return c.flaggerClient.FlaggerV1beta1().Canaries(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
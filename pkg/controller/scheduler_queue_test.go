@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanaryQueue_Schedule(t *testing.T) {
+	q := newCanaryQueue(0)
+	defer q.shutDown()
+
+	q.schedule("podinfo.default", "podinfo", "default", 10*time.Millisecond)
+	q.schedule("podinfo.default", "podinfo", "default", 10*time.Millisecond)
+	assert.Len(t, q.keys(), 1)
+
+	// new canaries are run right away
+	key, s, ok := q.get()
+	require.True(t, ok)
+	assert.Equal(t, "podinfo.default", key)
+	assert.Equal(t, "podinfo", s.name)
+	assert.Equal(t, "default", s.namespace)
+
+	// the next run is scheduled after the interval
+	begin := time.Now()
+	q.done(key)
+	_, s, ok = q.get()
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, time.Since(begin), 10*time.Millisecond)
+	assert.False(t, s.due.IsZero())
+	q.done(key)
+
+	// unscheduled canaries are discarded when due
+	q.unschedule(key)
+	assert.Empty(t, q.keys())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		q.shutDown()
+	}()
+	_, _, ok = q.get()
+	assert.False(t, ok)
+}
+
+func TestCanaryQueue_Jitter(t *testing.T) {
+	q := newCanaryQueue(0.5)
+	defer q.shutDown()
+
+	for i := 0; i < 100; i++ {
+		jitter := q.randomJitter(time.Minute)
+		assert.GreaterOrEqual(t, jitter, time.Duration(0))
+		assert.Less(t, jitter, 30*time.Second)
+	}
+
+	noJitter := newCanaryQueue(0)
+	defer noJitter.shutDown()
+	assert.Equal(t, time.Duration(0), noJitter.randomJitter(time.Minute))
+}
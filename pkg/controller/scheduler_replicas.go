@@ -0,0 +1,98 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"math"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// canaryReplicas returns the canary replicas sized for the traffic weight
+func canaryReplicas(scaling *flaggerv1.CanaryReplicaScaling, primaryReplicas int32, weight int, totalWeight int) int32 {
+	replicas := int32(math.Ceil(float64(primaryReplicas) * float64(weight) / float64(totalWeight) * scaling.GetHeadroom()))
+	if minReplicas := scaling.GetMinReplicas(); replicas < minReplicas {
+		return minReplicas
+	}
+	return replicas
+}
+
+// replicaController returns the controller sizing the canary replicas if the replica scaling is enabled
+func replicaController(cd *flaggerv1.Canary, canaryController canary.Controller) (canary.ReplicaController, bool) {
+	if cd.GetAnalysis() == nil || cd.GetAnalysis().ReplicaScaling == nil {
+		return nil, false
+	}
+	rc, ok := canaryController.(canary.ReplicaController)
+	return rc, ok
+}
+
+// scaleCanaryForWeight sizes the canary replicas for the traffic weight,
+// it returns true if the canary has been scaled up and its new replicas must be ready
+// before the traffic is shifted
+func (c *Controller) scaleCanaryForWeight(cd *flaggerv1.Canary, rc canary.ReplicaController, weight int) (bool, error) {
+	current, primary, err := rc.GetReplicas(cd)
+	if err != nil {
+		return false, err
+	}
+
+	desired := canaryReplicas(cd.GetAnalysis().ReplicaScaling, primary, weight, c.totalWeight(cd))
+	if desired == current {
+		return false, nil
+	}
+
+	if err := rc.ScaleCanary(cd, desired); err != nil {
+		return false, err
+	}
+	c.recordEventInfof(cd, "Scaling %s.%s canary from %d to %d replicas for weight %d",
+		cd.Spec.TargetRef.Name, cd.Namespace, current, desired, weight)
+	return desired > current, nil
+}
+
+// desiredCanaryReplicas returns the replicas of the new canary revision before they are sized for the traffic weight,
+// a canary scaled to zero after the last promotion gets the primary replicas
+func desiredCanaryReplicas(cd *flaggerv1.Canary, rc canary.ReplicaController) (int32, error) {
+	current, primary, err := rc.GetReplicas(cd)
+	if err != nil {
+		return 0, err
+	}
+	if current > 0 {
+		return current, nil
+	}
+	return primary, nil
+}
+
+// restoreCanaryReplicas scales the canary back to the replicas of its revision before the promotion,
+// otherwise the primary would be promoted with the replicas sized for the last weight
+func (c *Controller) restoreCanaryReplicas(cd *flaggerv1.Canary, canaryController canary.Controller) error {
+	rc, ok := replicaController(cd, canaryController)
+	if !ok {
+		return nil
+	}
+
+	current, desired, err := rc.GetReplicas(cd)
+	if err != nil {
+		return err
+	}
+	if cd.Status.CanaryReplicas != nil {
+		desired = *cd.Status.CanaryReplicas
+	}
+	if current == desired {
+		return nil
+	}
+	return rc.ScaleCanary(cd, desired)
+}
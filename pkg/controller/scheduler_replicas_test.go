@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestCanaryReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		scaling  *flaggerv1.CanaryReplicaScaling
+		primary  int32
+		weight   int
+		expected int32
+	}{
+		{"defaults no traffic", &flaggerv1.CanaryReplicaScaling{}, 10, 0, 1},
+		{"defaults proportional", &flaggerv1.CanaryReplicaScaling{}, 10, 20, 2},
+		{"rounds up", &flaggerv1.CanaryReplicaScaling{}, 10, 15, 2},
+		{"headroom", &flaggerv1.CanaryReplicaScaling{Headroom: float64p(1.5)}, 10, 20, 3},
+		{"min replicas", &flaggerv1.CanaryReplicaScaling{MinReplicas: int32p(3)}, 10, 10, 3},
+		{"full traffic", &flaggerv1.CanaryReplicaScaling{}, 10, 100, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, canaryReplicas(tt.scaling, tt.primary, tt.weight, 100))
+		})
+	}
+}
+
+func TestScheduler_DeploymentReplicaScaling(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.AutoscalerRef = nil
+	cd.Spec.Analysis.ReplicaScaling = &flaggerv1.CanaryReplicaScaling{
+		Headroom:    float64p(1.5),
+		MinReplicas: int32p(2),
+	}
+	mocks := newDeploymentFixture(cd)
+
+	// initializing
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	setDeploymentReplicas(t, mocks, "podinfo-primary", 10)
+
+	// initialized
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// update without setting the replicas, the canary keeps the replicas it was scaled to
+	dep2 := newDeploymentTestDeploymentV2()
+	dep2.Spec.Replicas = int32p(0)
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// detect changes, the canary starts with the min replicas
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	assert.Equal(t, int32(2), getDeploymentReplicas(t, mocks, "podinfo"))
+	setDeploymentReplicas(t, mocks, "podinfo", 2)
+
+	// advance to 10% with ceil(10 * 0.1 * 1.5) = 2 replicas
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	_, canaryWeight, _, err := mocks.router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 10, canaryWeight)
+	assert.Equal(t, int32(2), getDeploymentReplicas(t, mocks, "podinfo"))
+
+	// scale up to ceil(10 * 0.2 * 1.5) = 3 replicas and hold the weight
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	_, canaryWeight, _, err = mocks.router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 10, canaryWeight)
+	assert.Equal(t, int32(3), getDeploymentReplicas(t, mocks, "podinfo"))
+
+	// advance to 20% once the new replicas are ready
+	setDeploymentReplicas(t, mocks, "podinfo", 3)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	_, canaryWeight, _, err = mocks.router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 20, canaryWeight)
+
+	// advance to the max weight and promote with the replicas sized for each step
+	for i := 0; i < 10; i++ {
+		c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		if c.Status.Phase == flaggerv1.CanaryPhasePromoting {
+			break
+		}
+		setDeploymentReplicas(t, mocks, "podinfo", getDeploymentReplicas(t, mocks, "podinfo"))
+		mocks.ctrl.advanceCanary("podinfo", "default")
+	}
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+
+	// the primary keeps its replicas after the promotion
+	assert.Equal(t, int32(10), getDeploymentReplicas(t, mocks, "podinfo-primary"))
+}
+
+func TestScheduler_DeploymentReplicaScalingPromotesRevisionReplicas(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.AutoscalerRef = nil
+	cd.Spec.Analysis.ReplicaScaling = &flaggerv1.CanaryReplicaScaling{}
+	mocks := newDeploymentFixture(cd)
+
+	// initializing
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	setDeploymentReplicas(t, mocks, "podinfo-primary", 10)
+
+	// initialized
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// update with new replicas
+	dep2 := newDeploymentTestDeploymentV2()
+	dep2.Spec.Replicas = int32p(4)
+	_, err := mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep2, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// detect changes, the revision replicas are recorded before the canary is sized for the weight
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, c.Status.CanaryReplicas)
+	assert.Equal(t, int32(4), *c.Status.CanaryReplicas)
+	assert.Equal(t, int32(1), getDeploymentReplicas(t, mocks, "podinfo"))
+
+	for i := 0; i < 20; i++ {
+		c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+		require.NoError(t, err)
+		if c.Status.Phase == flaggerv1.CanaryPhaseFinalising {
+			break
+		}
+		setDeploymentReplicas(t, mocks, "podinfo", getDeploymentReplicas(t, mocks, "podinfo"))
+		mocks.ctrl.advanceCanary("podinfo", "default")
+	}
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFinalising))
+
+	// the primary is promoted with the replicas of the new revision
+	assert.Equal(t, int32(4), getDeploymentReplicas(t, mocks, "podinfo-primary"))
+}
+
+func getDeploymentReplicas(t *testing.T, mocks fixture, name string) int32 {
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, dep.Spec.Replicas)
+	return *dep.Spec.Replicas
+}
+
+// setDeploymentReplicas sets the deployment replicas and marks them as ready
+func setDeploymentReplicas(t *testing.T, mocks fixture, name string, replicas int32) {
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	dep.Spec.Replicas = &replicas
+	dep.Status = appsv1.DeploymentStatus{Replicas: replicas, UpdatedReplicas: replicas,
+		ReadyReplicas: replicas, AvailableReplicas: replicas}
+
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+}
+
+func float64p(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+	"github.com/fluxcd/flagger/pkg/router"
+)
+
+// restorePrimary routes all traffic to the canary and restores the primary pod template
+// replaced by the promotion when the primary rollout stalls
+func (c *Controller) restorePrimary(cd *flaggerv1.Canary, canaryController canary.Controller,
+	meshRouter router.Interface, provider string) {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	c.recordEventWarningf(cd, "Promotion of %s.%s stalled, routing all traffic to canary", primaryName, cd.Namespace)
+
+	revisionController, ok := canaryController.(canary.RevisionController)
+	if !ok {
+		c.recordEventWarningf(cd, "Primary restore is not supported for %s targets", cd.Spec.TargetRef.Kind)
+		return
+	}
+
+	// the kubernetes provider can't shift traffic, the services select the canary pods instead
+	if provider == flaggerv1.KubernetesProvider {
+		if err := c.setSelectorSwapped(cd, canaryController, true); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+	} else {
+		if err := meshRouter.SetRoutes(cd, 0, c.totalWeight(cd), false); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+		c.recorder.SetWeight(cd, 0, c.totalWeight(cd))
+	}
+
+	if err := revisionController.RevertPrimary(cd); err != nil {
+		// the previous template would run with the promoted configs, the traffic stays on the canary
+		if errors.Is(err, canary.ErrConfigChanged) {
+			c.recordEventWarningf(cd, "Primary %s.%s not restored, its configs changed since the previous revision: %v",
+				primaryName, cd.Namespace, err)
+			return
+		}
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+
+	if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhaseRestoring); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+	c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseRestoring)
+	c.recordEventWarningf(cd, "Restoring %s.%s to the previous revision", primaryName, cd.Namespace)
+	c.alert(cd, fmt.Sprintf("Primary rollout stalled, %s is restored to the previous revision.", primaryName),
+		false, flaggerv1.SeverityError)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// revertTarget restores the target pod template to the last promoted spec
+// after the canary analysis failed
+func (c *Controller) revertTarget(cd *flaggerv1.Canary, canaryController canary.Controller) {
+	revisionController, ok := canaryController.(canary.RevisionController)
+	if !ok {
+		c.recordEventWarningf(cd, "Target revert is not supported for %s targets", cd.Spec.TargetRef.Kind)
+		return
+	}
+
+	// the status was changed by the rollback, the revert must start from the stored canary
+	current, err := c.flaggerClient.FlaggerV1beta1().Canaries(cd.Namespace).Get(context.TODO(), cd.Name, metav1.GetOptions{})
+	if err != nil {
+		c.recordEventWarningf(cd, "canary %s.%s get query failed: %v", cd.Name, cd.Namespace, err)
+		return
+	}
+
+	if err := revisionController.RevertTarget(current); err != nil {
+		c.recordEventWarningf(cd, "Reverting %s.%s failed: %v", cd.Spec.TargetRef.Name, cd.Namespace, err)
+		return
+	}
+	c.recordEventInfof(cd, "Reverted %s.%s to the last promoted spec, rejected revision %s",
+		cd.Spec.TargetRef.Name, cd.Namespace, current.Status.LastAppliedSpec)
+}
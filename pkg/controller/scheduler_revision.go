@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// rollbackToRevision restores the primary to the promoted revision set in the flagger.app/rollback-to
+// annotation, the annotation is removed once the rollback is done or if it can't be applied
+func (c *Controller) rollbackToRevision(cd *flaggerv1.Canary, canaryController canary.Controller, value string) {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+
+	revision, err := strconv.Atoi(value)
+	switch {
+	case err != nil || revision < 1:
+		c.recordEventWarningf(cd, "Rollback of %s.%s ignored, invalid revision %q", primaryName, cd.Namespace, value)
+	case cd.Status.Phase != flaggerv1.CanaryPhaseInitialized &&
+		cd.Status.Phase != flaggerv1.CanaryPhaseSucceeded &&
+		cd.Status.Phase != flaggerv1.CanaryPhaseFailed &&
+		cd.Status.Phase != flaggerv1.CanaryPhaseReverted:
+		c.recordEventWarningf(cd, "Rollback of %s.%s to revision %d ignored, canary is %s",
+			primaryName, cd.Namespace, revision, cd.Status.Phase)
+	default:
+		revisionController, ok := canaryController.(canary.RevisionController)
+		if !ok {
+			c.recordEventWarningf(cd, "Rollback is not supported for %s targets", cd.Spec.TargetRef.Kind)
+			break
+		}
+		if err := revisionController.RollbackPrimary(cd, revision); err != nil {
+			c.recordEventWarningf(cd, "Rollback of %s.%s to revision %d failed: %v", primaryName, cd.Namespace, revision, err)
+			if !errors.Is(err, canary.ErrRevisionNotFound) && !errors.Is(err, canary.ErrConfigChanged) {
+				// retry on the next run
+				return
+			}
+			break
+		}
+		c.recordEventWarningf(cd, "Rolled back %s.%s to revision %d", primaryName, cd.Namespace, revision)
+		c.alert(cd, fmt.Sprintf("Rolled back %s to revision %d.", primaryName, revision), false, flaggerv1.SeverityWarn)
+	}
+
+	if err := c.removeCanaryAnnotation(cd, flaggerv1.RollbackToAnnotation); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+	}
+}
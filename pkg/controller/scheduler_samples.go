@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/metrics/observers"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// runSampleChecks runs the min sample queries of the metrics, the analysis
+// is inconclusive if a metric has fewer samples than its min samples
+func (c *Controller) runSampleChecks(canary *flaggerv1.Canary) analysisResult {
+	for _, metric := range canary.GetAnalysis().Metrics {
+		if metric.MinSampleQuery == "" {
+			continue
+		}
+		if metric.Interval == "" {
+			metric.Interval = canary.GetMetricInterval()
+		}
+
+		var provider providers.Interface
+		if metric.TemplateRef != nil {
+			_, templateProvider, err := c.getMetricTemplateProvider(canary, metric)
+			if err != nil {
+				c.recordEventErrorf(canary, "%v", err)
+				return analysisFailed
+			}
+			provider = templateProvider
+		} else {
+			observerFactory, err := c.getObserverFactory(canary)
+			if err != nil {
+				c.recordEventErrorf(canary, "Error building Prometheus client for %s %v", canary.Spec.MetricsServer, err)
+				return analysisFailed
+			}
+			provider = observerFactory.Client
+		}
+
+		query, err := observers.RenderQuery(metric.MinSampleQuery, toMetricModel(canary, metric.Interval, metric.TemplateVariables))
+		if err != nil {
+			c.recordEventErrorf(canary, "Metric %s min sample query render error: %v", metric.Name, err)
+			return analysisFailed
+		}
+
+		samples, err := provider.RunQuery(query)
+		if err != nil && !errors.Is(err, providers.ErrNoValuesFound) {
+			c.recordEventErrorf(canary, "Metric %s min sample query failed: %v", metric.Name, err)
+			return analysisFailed
+		}
+
+		if samples < float64(metric.MinSamples) {
+			c.recordEventInconclusivef(canary, "Halt %s.%s advancement %s has %.0f samples < %v, check inconclusive",
+				canary.Name, canary.Namespace, metric.Name, samples, metric.MinSamples)
+			return analysisInconclusive
+		}
+	}
+	return analysisPassed
+}
+
+// isInconclusiveLimitReached returns true if the number of inconclusive checks reached the max inconclusive
+func isInconclusiveLimitReached(cd *flaggerv1.Canary) bool {
+	limit := cd.GetAnalysis().MaxInconclusive
+	return limit > 0 && cd.Status.InconclusiveChecks >= limit
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestController_runSampleChecks(t *testing.T) {
+	// the canary received 40 requests, the empty query returns no values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query()["query"][0] == "empty" {
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1545905245.458,"40"]}]}}`))
+	}))
+	defer ts.Close()
+
+	newCanary := func(query string, minSamples int) *flaggerv1.Canary {
+		return &flaggerv1.Canary{
+			ObjectMeta: metav1.ObjectMeta{Name: "podinfo", Namespace: "default"},
+			Spec: flaggerv1.CanarySpec{
+				TargetRef: flaggerv1.LocalObjectReference{Name: "podinfo", Kind: "Deployment"},
+				Analysis: &flaggerv1.CanaryAnalysis{Metrics: []flaggerv1.CanaryMetric{{
+					Name:           "errors",
+					TemplateRef:    &flaggerv1.CrossNamespaceObjectReference{Name: "samples", Namespace: "default"},
+					MinSampleQuery: query,
+					MinSamples:     minSamples,
+				}}},
+			},
+		}
+	}
+
+	ctrl := newDeploymentFixture(nil).ctrl
+	template := newDeploymentTestMetricTemplate()
+	template.Name = "samples"
+	template.Spec.Provider.Address = ts.URL
+	template.Spec.Provider.SecretRef = nil
+	require.NoError(t, ctrl.flaggerInformers.MetricInformer.Informer().GetIndexer().Add(template))
+
+	assert.Equal(t, analysisPassed, ctrl.runSampleChecks(newCanary("requests", 40)))
+	assert.Equal(t, analysisInconclusive, ctrl.runSampleChecks(newCanary("requests", 100)))
+	assert.Equal(t, analysisInconclusive, ctrl.runSampleChecks(newCanary("empty", 1)))
+	assert.Equal(t, analysisPassed, ctrl.runSampleChecks(newCanary("", 0)))
+}
+
+func TestScheduler_DeploymentInconclusiveChecks(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.MaxInconclusive = 2
+	cd.Spec.Analysis.Metrics[0].MinSampleQuery = "sum(requests)"
+	cd.Spec.Analysis.Metrics[0].MinSamples = 1000
+	mocks := newDeploymentFixture(cd)
+
+	mocks.startCanaryRevision(t)
+
+	// advance to the first step
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// the metrics have too few samples
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Status.InconclusiveChecks)
+	assert.Equal(t, 0, c.Status.FailedChecks)
+	assert.Equal(t, 10, c.Status.CanaryWeight)
+
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.Status.InconclusiveChecks)
+
+	// roll back when the inconclusive checks limit is reached
+	mocks.ctrl.advanceCanary("podinfo", "default")
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhaseFailed))
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// runStep counts the analysis runs of the current step and returns true when the step
+// has held its weight for the minimum duration and iterations, and if paused, when it
+// has been resumed with the flagger.app/resume annotation
+func (c *Controller) runStep(canary *flaggerv1.Canary, canaryController canary.Controller) bool {
+	steps := canary.GetAnalysis().Steps
+	if len(steps) == 0 || canary.Status.Phase != flaggerv1.CanaryPhaseProgressing ||
+		canary.Status.StepStartTime == nil || canary.Status.StepIndex >= len(steps) {
+		return true
+	}
+
+	index := canary.Status.StepIndex
+	step := steps[index]
+
+	iterations := canary.Status.Iterations + 1
+	if err := canaryController.SetStatusIterations(canary, iterations); err != nil {
+		c.recordEventWarningf(canary, "%v", err)
+		return false
+	}
+	if iterations < step.GetIterations() {
+		c.recordEventInfof(canary, "Hold %s.%s step %d/%d weight %v iteration %v/%v",
+			canary.Name, canary.Namespace, index+1, len(steps), step.Weight, iterations, step.GetIterations())
+		return false
+	}
+
+	if elapsed := time.Since(canary.Status.StepStartTime.Time); elapsed < step.GetDuration() {
+		c.recordEventInfof(canary, "Hold %s.%s step %d/%d weight %v for %v",
+			canary.Name, canary.Namespace, index+1, len(steps), step.Weight,
+			(step.GetDuration() - elapsed).Round(time.Second))
+		return false
+	}
+
+	if step.Pause {
+		if _, ok := canary.GetAnnotations()[flaggerv1.ResumeAnnotation]; !ok {
+			c.recordEventWarningf(canary, "Halt %s.%s advancement paused at step %d/%d weight %v, waiting for %s annotation",
+				canary.Name, canary.Namespace, index+1, len(steps), step.Weight, flaggerv1.ResumeAnnotation)
+			return false
+		}
+		if err := c.removeCanaryAnnotation(canary, flaggerv1.ResumeAnnotation); err != nil {
+			c.recordEventWarningf(canary, "%v", err)
+			return false
+		}
+		c.recordEventInfof(canary, "Resume %s.%s advancement from step %d/%d",
+			canary.Name, canary.Namespace, index+1, len(steps))
+	}
+
+	return true
+}
+
+// isWaitingForResume returns true if the current analysis step is a pause step that
+// has held its weight and waits for the flagger.app/resume annotation
+func isWaitingForResume(canary *flaggerv1.Canary) bool {
+	steps := canary.GetAnalysis().Steps
+	if canary.Status.Phase != flaggerv1.CanaryPhaseProgressing ||
+		canary.Status.StepStartTime == nil || canary.Status.StepIndex >= len(steps) {
+		return false
+	}
+
+	step := steps[canary.Status.StepIndex]
+	return step.Pause && canary.Status.Iterations+1 >= step.GetIterations() &&
+		time.Since(canary.Status.StepStartTime.Time) >= step.GetDuration()
+}
+
+// removeCanaryAnnotation deletes an annotation from the canary object
+func (c *Controller) removeCanaryAnnotation(canary *flaggerv1.Canary, key string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				key: nil,
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("annotation patch marshal failed: %w", err)
+	}
+
+	_, err = c.flaggerClient.FlaggerV1beta1().Canaries(canary.Namespace).
+		Patch(context.TODO(), canary.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("canary %s.%s annotation %s remove failed: %w", canary.Name, canary.Namespace, key, err)
+	}
+	delete(canary.Annotations, key)
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// swapSelectorToPrimary points the apex and primary services back to the primary pods
+func (c *Controller) swapSelectorToPrimary(cd *flaggerv1.Canary, canaryController canary.Controller) bool {
+	if !cd.Status.SelectorSwapped {
+		return true
+	}
+
+	if err := c.setSelectorSwapped(cd, canaryController, false); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+	c.recordEventInfof(cd, "Routing the primary traffic back to %s-primary.%s pods",
+		cd.Spec.TargetRef.Name, cd.Namespace)
+	return true
+}
+
+// setSelectorSwapped records the services pod selector in the canary status and applies it
+func (c *Controller) setSelectorSwapped(cd *flaggerv1.Canary, canaryController canary.Controller, swapped bool) error {
+	if err := canary.SetStatusSelectorSwapped(c.flaggerClient, cd, swapped); err != nil {
+		return err
+	}
+
+	labelSelector, labelValue, ports, err := canaryController.GetMetadata(cd)
+	if err != nil {
+		return err
+	}
+	kubeRouter := c.getKubernetesRouter(cd.Spec.TargetRef.Kind, labelSelector, labelValue, ports)
+	if err := kubeRouter.Initialize(cd); err != nil {
+		return err
+	}
+	return kubeRouter.Reconcile(cd)
+}
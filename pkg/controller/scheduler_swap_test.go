@@ -0,0 +1,94 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestScheduler_DeploymentSelectorSwapRestore(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Provider = flaggerv1.KubernetesProvider
+	cd.Spec.Analysis.Iterations = 1
+	cd.Spec.Analysis.PrimaryRollout = &flaggerv1.CanaryPrimaryRollout{
+		ProgressDeadlineSeconds: int32p(60),
+	}
+	mocks := newDeploymentFixture(cd)
+
+	mocks.startCanaryRevision(t)
+
+	// run the analysis and promote
+	for i := 0; i < 3; i++ {
+		mocks.ctrl.advanceCanary("podinfo", "default")
+	}
+	require.NoError(t, assertPhase(mocks.flaggerClient, "podinfo", flaggerv1.CanaryPhasePromoting))
+	assertServiceSelector(t, mocks, "podinfo", "podinfo-primary")
+
+	// stall the primary rollout
+	primary, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo-primary", metav1.GetOptions{})
+	require.NoError(t, err)
+	primary.Status = appsv1.DeploymentStatus{
+		Replicas:        2,
+		UpdatedReplicas: 1,
+		Conditions: []appsv1.DeploymentCondition{
+			{
+				Type:   appsv1.DeploymentProgressing,
+				Status: corev1.ConditionFalse,
+				Reason: "ProgressDeadlineExceeded",
+			},
+		},
+	}
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), primary, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// the services select the canary pods while the primary is restored
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseRestoring, c.Status.Phase)
+	assert.True(t, c.Status.SelectorSwapped)
+	assertServiceSelector(t, mocks, "podinfo", "podinfo")
+	assertServiceSelector(t, mocks, "podinfo-primary", "podinfo")
+
+	// the services select the primary pods again before the canary is scaled down
+	mocks.makePrimaryReady(t)
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, flaggerv1.CanaryPhaseFailed, c.Status.Phase)
+	assert.False(t, c.Status.SelectorSwapped)
+	assertServiceSelector(t, mocks, "podinfo", "podinfo-primary")
+	assertServiceSelector(t, mocks, "podinfo-primary", "podinfo-primary")
+	assert.Equal(t, int32(0), getDeploymentReplicas(t, mocks, "podinfo"))
+}
+
+func assertServiceSelector(t *testing.T, mocks fixture, name string, expected string) {
+	svc, err := mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, expected, svc.Spec.Selector["app"], name)
+}
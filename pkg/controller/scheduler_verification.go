@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+	"github.com/fluxcd/flagger/pkg/metrics/observers"
+	"github.com/fluxcd/flagger/pkg/metrics/providers"
+)
+
+// runPostPromotionVerification checks the primary metrics at every interval of the
+// post-promotion window and restores the primary to the previous revision when
+// the number of failed checks reaches the analysis threshold
+func (c *Controller) runPostPromotionVerification(cd *flaggerv1.Canary, canaryController canary.Controller) {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+
+	// end the verification if a new revision is waiting to be analysed
+	newTarget, err := canaryController.HasTargetChanged(cd)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+	newCfg, err := canaryController.HaveDependenciesChanged(cd)
+	if err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+	if newTarget || newCfg {
+		if ok := c.setPhaseSucceeded(cd, canaryController); ok {
+			c.recordEventInfof(cd, "New revision detected! Ending post-promotion verification of %s.%s",
+				primaryName, cd.Namespace)
+		}
+		return
+	}
+
+	if cd.Status.FailedChecks >= cd.GetAnalysisThreshold() {
+		c.revertPrimary(cd, canaryController)
+		return
+	}
+
+	if elapsed := time.Since(getVerificationStartTime(cd)); elapsed >= cd.GetAnalysis().PostPromotion.GetWindow() {
+		if ok := c.setPhaseSucceeded(cd, canaryController); ok {
+			c.recordEventInfof(cd, "Post-promotion verification of %s.%s passed", primaryName, cd.Namespace)
+		}
+		return
+	}
+
+	if ok := c.runPostPromotionChecks(cd); !ok {
+		if err := canaryController.SetStatusFailedChecks(cd, cd.Status.FailedChecks+1); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+	}
+}
+
+// revertPrimary restores the primary pod template replaced by the last promotion
+func (c *Controller) revertPrimary(cd *flaggerv1.Canary, canaryController canary.Controller) {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	c.recordEventWarningf(cd, "Rolling back %s.%s post-promotion failed checks threshold reached %v",
+		primaryName, cd.Namespace, cd.Status.FailedChecks)
+
+	revisionController, ok := canaryController.(canary.RevisionController)
+	if !ok {
+		c.recordEventWarningf(cd, "Post-promotion rollback is not supported for %s targets", cd.Spec.TargetRef.Kind)
+		return
+	}
+	if err := revisionController.RevertPrimary(cd); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		// the previous template would run with the configs of the failed revision
+		if errors.Is(err, canary.ErrConfigChanged) {
+			c.failVerification(cd, canaryController)
+		}
+		return
+	}
+
+	if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhaseReverted); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+	c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseReverted)
+	c.runPostRolloutHooks(cd, flaggerv1.CanaryPhaseReverted)
+	c.recordEventWarningf(cd, "Promotion reverted! Restored %s.%s to the previous revision", primaryName, cd.Namespace)
+	c.alert(cd, fmt.Sprintf("Post-promotion verification failed, %s restored to the previous revision.", primaryName),
+		false, flaggerv1.SeverityError)
+}
+
+// failVerification sets the canary phase to failed when the primary can't be restored
+func (c *Controller) failVerification(cd *flaggerv1.Canary, canaryController canary.Controller) {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	if err := canaryController.SetStatusPhase(cd, flaggerv1.CanaryPhaseFailed); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+	c.recorder.SetStatus(cd, flaggerv1.CanaryPhaseFailed)
+	c.runPostRolloutHooks(cd, flaggerv1.CanaryPhaseFailed)
+	c.recordEventWarningf(cd, "Promotion not reverted! The configs of %s.%s changed since the previous revision",
+		primaryName, cd.Namespace)
+	c.alert(cd, fmt.Sprintf("Post-promotion verification failed, %s can't be restored to the previous revision "+
+		"since its configs changed.", primaryName), false, flaggerv1.SeverityError)
+}
+
+// getVerificationStartTime returns the time the canary entered the verifying phase
+func getVerificationStartTime(cd *flaggerv1.Canary) time.Time {
+	for _, condition := range cd.Status.Conditions {
+		if condition.Type == flaggerv1.PromotedType && condition.Reason == string(flaggerv1.CanaryPhaseVerifying) {
+			return condition.LastUpdateTime.Time
+		}
+	}
+	return cd.Status.LastTransitionTime.Time
+}
+
+// runPostPromotionChecks runs the analysis metrics against the primary, the metrics
+// are checked against their threshold range, the canary comparison is ignored
+func (c *Controller) runPostPromotionChecks(cd *flaggerv1.Canary) bool {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+
+	observerFactory, err := c.getObserverFactory(cd)
+	if err != nil {
+		c.recordEventErrorf(cd, "Error building Prometheus client for %s %v", cd.Spec.MetricsServer, err)
+		return false
+	}
+	observer := observerFactory.Observer(c.getMetricsProvider(cd))
+
+	for _, metric := range cd.GetAnalysis().Metrics {
+		if metric.Interval == "" {
+			metric.Interval = cd.GetMetricInterval()
+		}
+		model := toPrimaryMetricModel(cd, metric.Interval, metric.TemplateVariables)
+
+		var val float64
+		var err error
+		switch {
+		case metric.TemplateRef != nil:
+			template, provider, tErr := c.getMetricTemplateProvider(cd, metric)
+			if tErr != nil {
+				c.recordEventErrorf(cd, "%v", tErr)
+				return false
+			}
+			var query string
+			if query, err = observers.RenderQuery(template.Spec.Query, model); err == nil {
+				val, err = provider.RunQuery(query)
+			}
+		case metric.Name == "request-success-rate":
+			val, err = observer.GetRequestSuccessRate(model)
+		case metric.Name == "request-duration":
+			var d time.Duration
+			d, err = observer.GetRequestDuration(model)
+			val = toMilliseconds(d)
+		case metric.Query != "":
+			var query string
+			if query, err = observers.RenderQuery(metric.Query, model); err == nil {
+				val, err = observerFactory.Client.RunQuery(query)
+			}
+		default:
+			continue
+		}
+
+		if err != nil {
+			if errors.Is(err, providers.ErrNoValuesFound) {
+				c.recordEventWarningf(cd, "Post-promotion check no values found for metric %s probably %s.%s is not receiving traffic",
+					metric.Name, primaryName, cd.Namespace)
+			} else {
+				c.recordEventErrorf(cd, "Post-promotion metric query failed for %s: %v", metric.Name, err)
+			}
+			return false
+		}
+
+		if ok := c.checkPostPromotionMetric(cd, metric, val); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkPostPromotionMetric returns false if the primary metric value is out of the metric threshold range
+func (c *Controller) checkPostPromotionMetric(cd *flaggerv1.Canary, metric flaggerv1.CanaryMetric, val float64) bool {
+	primaryName := fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name)
+	if tr := metric.ThresholdRange; tr != nil {
+		if tr.Min != nil && val < *tr.Min {
+			c.recordEventWarningf(cd, "Post-promotion check of %s.%s failed %s %.2f < %v",
+				primaryName, cd.Namespace, metric.Name, val, *tr.Min)
+			return false
+		}
+		if tr.Max != nil && val > *tr.Max {
+			c.recordEventWarningf(cd, "Post-promotion check of %s.%s failed %s %.2f > %v",
+				primaryName, cd.Namespace, metric.Name, val, *tr.Max)
+			return false
+		}
+		return true
+	}
+
+	if metric.Comparison != nil {
+		return true
+	}
+
+	// the deprecated threshold is the minimum success rate and the maximum value for all other metrics
+	if metric.Name == "request-success-rate" && metric.TemplateRef == nil {
+		if val < metric.Threshold {
+			c.recordEventWarningf(cd, "Post-promotion check of %s.%s failed %s %.2f < %v",
+				primaryName, cd.Namespace, metric.Name, val, metric.Threshold)
+			return false
+		}
+	} else if val > metric.Threshold {
+		c.recordEventWarningf(cd, "Post-promotion check of %s.%s failed %s %.2f > %v",
+			primaryName, cd.Namespace, metric.Name, val, metric.Threshold)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+	"github.com/fluxcd/flagger/pkg/metrics/observers"
+	"github.com/fluxcd/flagger/pkg/router"
+)
+
+const (
+	warmupReasonWarmingUp = "WarmingUp"
+	warmupReasonCompleted = "WarmupCompleted"
+)
+
+// getWarmupCondition returns the warm-up condition of the current analysis run
+func getWarmupCondition(cd *flaggerv1.Canary) *flaggerv1.CanaryCondition {
+	if cd.GetAnalysis().Warmup == nil {
+		return nil
+	}
+	for i := range cd.Status.Conditions {
+		if cd.Status.Conditions[i].Type == flaggerv1.WarmedUpType {
+			return &cd.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// isWarmingUp returns true if the canary is in the warm-up period of the analysis
+func isWarmingUp(cd *flaggerv1.Canary) bool {
+	if cd.Status.Phase != flaggerv1.CanaryPhaseProgressing {
+		return false
+	}
+	condition := getWarmupCondition(cd)
+	return condition != nil && condition.Status == corev1.ConditionFalse
+}
+
+// startWarmup routes the warm-up traffic to the canary and marks the start of the warm-up
+func (c *Controller) startWarmup(cd *flaggerv1.Canary, canaryController canary.Controller, meshRouter router.Interface) {
+	warmup := cd.GetAnalysis().Warmup
+	message := fmt.Sprintf("Warming up for %s with %v%% traffic.", warmupLength(warmup), warmup.Weight)
+	if err := canary.SetStatusWarmup(c.flaggerClient, cd, corev1.ConditionFalse, warmupReasonWarmingUp, message, 0); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return
+	}
+
+	if warmup.Weight > 0 {
+		primaryWeight := c.totalWeight(cd) - warmup.Weight
+		if err := meshRouter.SetRoutes(cd, primaryWeight, warmup.Weight, false); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+		if err := canaryController.SetStatusWeight(cd, warmup.Weight); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+			return
+		}
+		c.recorder.SetWeight(cd, primaryWeight, warmup.Weight)
+	}
+	c.recorder.SetWarmup(cd, true)
+	c.recordEventInfof(cd, "Warming up %s.%s for %s with %v%% traffic",
+		cd.Spec.TargetRef.Name, cd.Namespace, warmupLength(warmup), warmup.Weight)
+}
+
+// runWarmup collects the canary metrics without judging them and returns true
+// once the warm-up duration or iterations have elapsed
+func (c *Controller) runWarmup(cd *flaggerv1.Canary) bool {
+	warmup := cd.GetAnalysis().Warmup
+	condition := getWarmupCondition(cd)
+
+	// run the load tests, a failed call doesn't count as a failed check
+	for _, webhook := range cd.GetAnalysis().Webhooks {
+		if webhook.Type == "" || webhook.Type == flaggerv1.RolloutHook {
+			if err := CallWebhook(*cd, flaggerv1.CanaryPhaseProgressing, webhook); err != nil {
+				c.recordEventWarningf(cd, "Warm-up of %s.%s external check %s failed %v",
+					cd.Name, cd.Namespace, webhook.Name, err)
+			}
+		}
+	}
+	c.collectWarmupMetrics(cd)
+
+	iterations := cd.Status.WarmupIterations + 1
+	done := (warmup.Iterations > 0 && iterations >= warmup.Iterations) ||
+		(warmup.Iterations == 0 && time.Since(condition.LastTransitionTime.Time) >= warmup.GetDuration())
+	if !done {
+		if err := canary.SetStatusWarmup(c.flaggerClient, cd, corev1.ConditionFalse, warmupReasonWarmingUp, condition.Message, iterations); err != nil {
+			c.recordEventWarningf(cd, "%v", err)
+		}
+		return false
+	}
+
+	if err := canary.SetStatusWarmup(c.flaggerClient, cd, corev1.ConditionTrue, warmupReasonCompleted, "Warm-up completed.", iterations); err != nil {
+		c.recordEventWarningf(cd, "%v", err)
+		return false
+	}
+	c.recorder.SetWarmup(cd, false)
+	c.recordEventInfof(cd, "Warm-up of %s.%s completed, starting the metric checks",
+		cd.Spec.TargetRef.Name, cd.Namespace)
+	return true
+}
+
+// collectWarmupMetrics records the metric values without comparing them to the thresholds
+func (c *Controller) collectWarmupMetrics(cd *flaggerv1.Canary) {
+	logger := c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace))
+
+	observerFactory, err := c.getObserverFactory(cd)
+	if err != nil {
+		logger.Debugf("Warm-up metrics skipped, error building Prometheus client for %s %v", cd.Spec.MetricsServer, err)
+		return
+	}
+	observer := observerFactory.Observer(c.getMetricsProvider(cd))
+
+	var route string
+	if cd.Spec.Provider == flaggerv1.KnativeProvider || c.meshProvider == flaggerv1.KnativeProvider {
+		knativeService, err := c.knativeClient.ServingV1().Services(cd.Namespace).Get(context.TODO(), cd.Spec.TargetRef.Name, metav1.GetOptions{})
+		if err != nil {
+			logger.Debugf("Warm-up metrics skipped, error fetching Knative service %s/%s %v", cd.Namespace, cd.Spec.TargetRef.Name, err)
+			return
+		}
+		route = knativeService.Status.LatestCreatedRevisionName
+	}
+
+	for _, metric := range cd.GetAnalysis().Metrics {
+		if metric.Interval == "" {
+			metric.Interval = cd.GetMetricInterval()
+		}
+		model := toMetricModel(cd, metric.Interval, metric.TemplateVariables)
+		if route != "" {
+			model.Route = route
+		}
+
+		var val float64
+		var err error
+		switch {
+		case metric.TemplateRef != nil:
+			template, provider, tErr := c.getMetricTemplateProvider(cd, metric)
+			if tErr != nil {
+				err = tErr
+				break
+			}
+			query, qErr := observers.RenderQuery(template.Spec.Query, model)
+			if qErr != nil {
+				err = qErr
+				break
+			}
+			val, err = provider.RunQuery(query)
+		case metric.Query != "":
+			query, qErr := observers.RenderQuery(metric.Query, model)
+			if qErr != nil {
+				err = qErr
+				break
+			}
+			val, err = observerFactory.Client.RunQuery(query)
+		case metric.Name == "request-success-rate":
+			val, err = observer.GetRequestSuccessRate(model)
+		case metric.Name == "request-duration":
+			var d time.Duration
+			d, err = observer.GetRequestDuration(model)
+			val = d.Seconds()
+		default:
+			continue
+		}
+		if err != nil {
+			logger.Debugf("Warm-up metric %s query failed: %v", metric.Name, err)
+			continue
+		}
+		c.recorder.SetAnalysis(cd, metric.Name, val)
+	}
+}
+
+// warmupLength returns the duration or the number of iterations of the warm-up
+func warmupLength(warmup *flaggerv1.CanaryWarmup) string {
+	if warmup.Iterations > 0 {
+		return fmt.Sprintf("%v iterations", warmup.Iterations)
+	}
+	return warmup.GetDuration().String()
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+)
+
+func TestScheduler_DeploymentWarmup(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.Warmup = &flaggerv1.CanaryWarmup{
+		Iterations: 2,
+		Weight:     5,
+	}
+	// the metric would fail the analysis if it was judged during the warm-up
+	cd.Spec.Analysis.Metrics[0].ThresholdRange = &flaggerv1.CanaryThresholdRange{Min: toFloatPtr(101)}
+	mocks := newDeploymentFixture(cd)
+
+	mocks.startCanaryRevision(t)
+
+	// start the warm-up
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 5, c.Status.CanaryWeight)
+	condition := getWarmupCondition(c)
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, warmupReasonWarmingUp, condition.Reason)
+
+	// the metrics are collected but not judged
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Status.WarmupIterations)
+	assert.Equal(t, 0, c.Status.FailedChecks)
+	assert.Equal(t, 5, c.Status.CanaryWeight)
+	assert.True(t, isWarmingUp(c))
+
+	// the warm-up ends and the first metric check fails
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err = mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.Status.WarmupIterations)
+	assert.Equal(t, 1, c.Status.FailedChecks)
+	assert.False(t, isWarmingUp(c))
+	condition = getWarmupCondition(c)
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, warmupReasonCompleted, condition.Reason)
+
+	// the promoted condition is kept along the warm-up one
+	var promoted bool
+	for _, cond := range c.Status.Conditions {
+		if cond.Type == flaggerv1.PromotedType {
+			promoted = true
+		}
+	}
+	assert.True(t, promoted)
+}
+
+func TestScheduler_DeploymentWarmupProgress(t *testing.T) {
+	cd := newDeploymentTestCanary()
+	cd.Spec.Analysis.Warmup = &flaggerv1.CanaryWarmup{
+		Iterations: 1,
+		Weight:     5,
+	}
+	mocks := newDeploymentFixture(cd)
+
+	mocks.startCanaryRevision(t)
+
+	// start the warm-up
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	// the warm-up ends and the canary advances from the warm-up weight
+	mocks.ctrl.advanceCanary("podinfo", "default")
+
+	c, err := mocks.flaggerClient.FlaggerV1beta1().Canaries("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.Status.FailedChecks)
+	assert.Equal(t, 15, c.Status.CanaryWeight)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+
+	flaggerv1 "github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
+	"github.com/fluxcd/flagger/pkg/canary"
+)
+
+// targetChangeDelay debounces the analysis runs triggered by target changes,
+// the changes made within the delay result in a single run
+const targetChangeDelay = 5 * time.Second
+
+// addTargetEventHandlers schedules an analysis run of the canaries
+// when their target pod template or tracked configs change
+func (c *Controller) addTargetEventHandlers(informers Informers) {
+	if informers.DeploymentInformer != nil {
+		informers.DeploymentInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) {
+				oldDep, ok := old.(*appsv1.Deployment)
+				if !ok {
+					return
+				}
+				newDep, ok := new.(*appsv1.Deployment)
+				if !ok || equality.Semantic.DeepEqual(oldDep.Spec.Template, newDep.Spec.Template) {
+					return
+				}
+				c.triggerTargetCanaries("Deployment", newDep.Name, newDep.Namespace)
+			},
+		})
+	}
+
+	if informers.DaemonSetInformer != nil {
+		informers.DaemonSetInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) {
+				oldDae, ok := old.(*appsv1.DaemonSet)
+				if !ok {
+					return
+				}
+				newDae, ok := new.(*appsv1.DaemonSet)
+				if !ok || equality.Semantic.DeepEqual(oldDae.Spec.Template, newDae.Spec.Template) {
+					return
+				}
+				c.triggerTargetCanaries("DaemonSet", newDae.Name, newDae.Namespace)
+			},
+		})
+	}
+
+	if informers.StatefulSetInformer != nil {
+		informers.StatefulSetInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) {
+				oldSts, ok := old.(*appsv1.StatefulSet)
+				if !ok {
+					return
+				}
+				newSts, ok := new.(*appsv1.StatefulSet)
+				if !ok || equality.Semantic.DeepEqual(oldSts.Spec.Template, newSts.Spec.Template) {
+					return
+				}
+				c.triggerTargetCanaries("StatefulSet", newSts.Name, newSts.Namespace)
+			},
+		})
+	}
+
+	if informers.ConfigMapInformer != nil {
+		informers.ConfigMapInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) {
+				c.triggerConfigCanaries(canary.ConfigRefMap, old, new)
+			},
+		})
+	}
+
+	if informers.SecretInformer != nil {
+		informers.SecretInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, new interface{}) {
+				c.triggerConfigCanaries(canary.ConfigRefSecret, old, new)
+			},
+		})
+	}
+}
+
+// triggerTargetCanaries schedules the canaries that target the workload
+func (c *Controller) triggerTargetCanaries(kind string, name string, namespace string) {
+	c.triggerCanaries(namespace, func(cd *flaggerv1.Canary) bool {
+		if cd.Spec.TargetRef.Kind == kind && cd.Spec.TargetRef.Name == name {
+			return true
+		}
+		for _, ref := range cd.Spec.AdditionalTargetRefs {
+			if ref.Kind == kind && ref.Name == name {
+				return true
+			}
+		}
+		return false
+	}, fmt.Sprintf("%s %s.%s", kind, name, namespace))
+}
+
+// triggerConfigCanaries schedules the canaries tracking the config map or secret,
+// the resync events are ignored
+func (c *Controller) triggerConfigCanaries(refType canary.ConfigRefType, old interface{}, new interface{}) {
+	oldMeta, err := meta.Accessor(old)
+	if err != nil {
+		return
+	}
+	newMeta, err := meta.Accessor(new)
+	if err != nil || oldMeta.GetResourceVersion() == newMeta.GetResourceVersion() {
+		return
+	}
+
+	ref := canary.ConfigRef{Name: newMeta.GetName(), Type: refType}
+	c.triggerCanaries(newMeta.GetNamespace(), func(cd *flaggerv1.Canary) bool {
+		if cd.Status.TrackedConfigs == nil {
+			return false
+		}
+		_, ok := (*cd.Status.TrackedConfigs)[ref.GetName()]
+		return ok
+	}, fmt.Sprintf("%s %s.%s", refType, newMeta.GetName(), newMeta.GetNamespace()))
+}
+
+func (c *Controller) triggerCanaries(namespace string, match func(cd *flaggerv1.Canary) bool, change string) {
+	canaries, err := c.flaggerInformers.CanaryInformer.Lister().Canaries(namespace).List(labels.Everything())
+	if err != nil {
+		c.logger.Errorf("Canaries in namespace %s list failed: %v", namespace, err)
+		return
+	}
+
+	for _, cd := range canaries {
+		if !match(cd) {
+			continue
+		}
+		if ok := c.scheduler.trigger(fmt.Sprintf("%s.%s", cd.Name, cd.Namespace), targetChangeDelay); ok {
+			c.logger.With("canary", fmt.Sprintf("%s.%s", cd.Name, cd.Namespace)).
+				Debugf("%s has changed, scheduling analysis in %v", change, targetChangeDelay)
+		}
+	}
+}
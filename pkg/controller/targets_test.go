@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestController_TriggerTargetCanaries(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	mocks.ctrl.scheduler = newScheduledTestQueue(t, "podinfo.default")
+	defer mocks.ctrl.scheduler.shutDown()
+
+	// changes of other workloads are ignored
+	mocks.ctrl.triggerTargetCanaries("Deployment", "other", "default")
+	mocks.ctrl.triggerTargetCanaries("DaemonSet", "podinfo", "default")
+	assert.Greater(t, time.Until(mocks.ctrl.scheduler.schedules["podinfo.default"].due), time.Minute)
+
+	mocks.ctrl.triggerTargetCanaries("Deployment", "podinfo", "default")
+	assert.LessOrEqual(t, time.Until(mocks.ctrl.scheduler.schedules["podinfo.default"].due), targetChangeDelay)
+}
+
+func TestController_TriggerConfigCanaries(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	mocks.ctrl.scheduler = newScheduledTestQueue(t, "podinfo.default")
+	defer mocks.ctrl.scheduler.shutDown()
+
+	cd := mocks.canary.DeepCopy()
+	cd.Status.TrackedConfigs = &map[string]string{"configmap/podinfo-config-env": "1234"}
+	require.NoError(t, mocks.ctrl.flaggerInformers.CanaryInformer.Informer().GetIndexer().Update(cd))
+
+	old := newDeploymentTestConfigMap()
+	old.ResourceVersion = "1"
+	updated := newDeploymentTestConfigMapV2()
+	updated.ResourceVersion = "2"
+
+	// resync events and untracked configs are ignored
+	mocks.ctrl.triggerConfigCanaries("configmap", old, old)
+	mocks.ctrl.triggerConfigCanaries("secret", old, updated)
+	assert.Greater(t, time.Until(mocks.ctrl.scheduler.schedules["podinfo.default"].due), time.Minute)
+
+	mocks.ctrl.triggerConfigCanaries("configmap", old, updated)
+	assert.LessOrEqual(t, time.Until(mocks.ctrl.scheduler.schedules["podinfo.default"].due), targetChangeDelay)
+}
+
+func TestController_TargetEventHandlers(t *testing.T) {
+	mocks := newDeploymentFixture(nil)
+	mocks.ctrl.scheduler = newScheduledTestQueue(t, "podinfo.default")
+	defer mocks.ctrl.scheduler.shutDown()
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory := kubeinformers.NewSharedInformerFactory(mocks.kubeClient, 0)
+	informers := Informers{DeploymentInformer: factory.Apps().V1().Deployments().Informer()}
+	mocks.ctrl.addTargetEventHandlers(informers)
+	factory.Start(stopCh)
+	require.True(t, cache.WaitForCacheSync(stopCh, informers.DeploymentInformer.HasSynced))
+
+	// scaling the target doesn't trigger the analysis
+	dep, err := mocks.kubeClient.AppsV1().Deployments("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	dep.Spec.Replicas = int32p(3)
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), dep, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	time.Sleep(100 * time.Millisecond)
+	mocks.ctrl.scheduler.mu.Lock()
+	due := mocks.ctrl.scheduler.schedules["podinfo.default"].due
+	mocks.ctrl.scheduler.mu.Unlock()
+	assert.Greater(t, time.Until(due), time.Minute)
+
+	// a new pod template triggers the analysis
+	_, err = mocks.kubeClient.AppsV1().Deployments("default").Update(context.TODO(), newDeploymentTestDeploymentV2(), metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		mocks.ctrl.scheduler.mu.Lock()
+		defer mocks.ctrl.scheduler.mu.Unlock()
+		return time.Until(mocks.ctrl.scheduler.schedules["podinfo.default"].due) <= targetChangeDelay
+	}, time.Second, 10*time.Millisecond)
+}
+
+// newScheduledTestQueue returns a queue with the canary next run scheduled in one hour
+func newScheduledTestQueue(t *testing.T, key string) *canaryQueue {
+	q := newCanaryQueue(0)
+	q.schedule(key, "podinfo", "default", time.Hour)
+	key, _, ok := q.get()
+	require.True(t, ok)
+	q.done(key)
+	return q
+}
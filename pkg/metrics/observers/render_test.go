@@ -43,6 +43,24 @@ func Test_RenderQuery(t *testing.T) {
 		assert.Equal(t, expected, actual)
 	})
 
+	t.Run("ok_with_baseline", func(t *testing.T) {
+		expected := `sum(rate(errors{workload="myapp"}[1m])) / sum(rate(errors{workload="myapp-baseline"}[1m]))`
+		templateQuery := `sum(rate(errors{workload="{{ target }}"}[{{ interval }}])) / sum(rate(errors{workload="{{ baseline }}"}[{{ interval }}]))`
+
+		model := &flaggerv1.MetricTemplateModel{
+			Name:      "standard",
+			Namespace: "default",
+			Target:    "myapp",
+			Baseline:  "myapp-baseline",
+			Interval:  "1m",
+		}
+
+		actual, err := RenderQuery(templateQuery, *model)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, actual)
+	})
+
 	t.Run("ok_with_variables", func(t *testing.T) {
 		expected := `delta(max by (consumer_group) (kafka_consumer_current_offset{cluster="dev", consumer_group="my_consumer"}[1m]))`
 		templateQuery := `delta(max by (consumer_group) (kafka_consumer_current_offset{cluster="{{ variables.cluster }}", consumer_group="{{ variables.consumer_group }}"}[{{ interval }}]))`
@@ -105,9 +105,75 @@ func NewPrometheusProvider(provider flaggerv1.MetricTemplateProvider, credential
 // RunQuery executes the promQL query and returns the the first result as float64
 func (p *PrometheusProvider) RunQuery(query string) (float64, error) {
 	query = url.QueryEscape(p.trimQuery(query))
-	u, err := url.Parse(fmt.Sprintf("./api/v1/query?query=%s", query))
+	result, err := p.get(fmt.Sprintf("./api/v1/query?query=%s", query))
 	if err != nil {
-		return 0, fmt.Errorf("url.Parse failed: %w", err)
+		return 0, err
+	}
+
+	var value *float64
+	for _, v := range result.Data.Result {
+		if v.Values != nil {
+			return 0, fmt.Errorf("%w", ErrMultipleValuesReturned)
+		}
+		metricValue := v.Value[1]
+		switch metricValue.(type) {
+		case string:
+			f, err := strconv.ParseFloat(metricValue.(string), 64)
+			if err != nil {
+				return 0, err
+			}
+			value = &f
+		}
+	}
+	if value == nil || math.IsNaN(*value) {
+		return 0, fmt.Errorf("%w", ErrNoValuesFound)
+	}
+
+	return *value, nil
+}
+
+// RunRangeQuery executes the promQL query over the time window and returns the samples of the time series
+func (p *PrometheusProvider) RunRangeQuery(query string, start time.Time, end time.Time, step time.Duration) ([]float64, error) {
+	query = url.QueryEscape(p.trimQuery(query))
+	result, err := p.get(fmt.Sprintf("./api/v1/query_range?query=%s&start=%d&end=%d&step=%s",
+		query, start.Unix(), end.Unix(), step.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Data.Result) > 1 {
+		return nil, fmt.Errorf("%w", ErrMultipleValuesReturned)
+	}
+
+	var values []float64
+	for _, v := range result.Data.Result {
+		for _, sample := range v.Values {
+			pair, ok := sample.([]interface{})
+			if !ok || len(pair) != 2 {
+				continue
+			}
+			if s, ok := pair[1].(string); ok {
+				f, err := strconv.ParseFloat(s, 64)
+				if err != nil {
+					return nil, err
+				}
+				if !math.IsNaN(f) {
+					values = append(values, f)
+				}
+			}
+		}
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w", ErrNoValuesFound)
+	}
+
+	return values, nil
+}
+
+func (p *PrometheusProvider) get(apiPath string) (*prometheusResponse, error) {
+	u, err := url.Parse(apiPath)
+	if err != nil {
+		return nil, fmt.Errorf("url.Parse failed: %w", err)
 	}
 	u.Path = path.Join(p.url.Path, u.Path)
 
@@ -115,7 +181,7 @@ func (p *PrometheusProvider) RunQuery(query string) (float64, error) {
 
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
-		return 0, fmt.Errorf("http.NewRequest failed: %w", err)
+		return nil, fmt.Errorf("http.NewRequest failed: %w", err)
 	}
 
 	if p.headers != nil {
@@ -133,45 +199,26 @@ func (p *PrometheusProvider) RunQuery(query string) (float64, error) {
 
 	r, err := p.client.Do(req.WithContext(ctx))
 	if err != nil {
-		return 0, fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer r.Body.Close()
 
 	b, err := io.ReadAll(r.Body)
 	if err != nil {
-		return 0, fmt.Errorf("error reading body: %w", err)
+		return nil, fmt.Errorf("error reading body: %w", err)
 	}
 
 	if 400 <= r.StatusCode {
-		return 0, fmt.Errorf("error response: %s", string(b))
+		return nil, fmt.Errorf("error response: %s", string(b))
 	}
 
 	var result prometheusResponse
 	err = json.Unmarshal(b, &result)
 	if err != nil {
-		return 0, fmt.Errorf("error unmarshaling result: %w, '%s'", err, string(b))
-	}
-
-	var value *float64
-	for _, v := range result.Data.Result {
-		if v.Values != nil {
-			return 0, fmt.Errorf("%w", ErrMultipleValuesReturned)
-		}
-		metricValue := v.Value[1]
-		switch metricValue.(type) {
-		case string:
-			f, err := strconv.ParseFloat(metricValue.(string), 64)
-			if err != nil {
-				return 0, err
-			}
-			value = &f
-		}
-	}
-	if value == nil || math.IsNaN(*value) {
-		return 0, fmt.Errorf("%w", ErrNoValuesFound)
+		return nil, fmt.Errorf("error unmarshaling result: %w, '%s'", err, string(b))
 	}
 
-	return *value, nil
+	return &result, nil
 }
 
 // IsOnline run simple Prometheus query and returns an error if the API is unreachable
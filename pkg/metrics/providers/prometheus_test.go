@@ -23,6 +23,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -344,3 +345,66 @@ func TestPrometheusProvider_RunQueryWithProviderHeaders(t *testing.T) {
 		assert.Equal(t, float64(100), val)
 	})
 }
+
+func TestPrometheusProvider_RunRangeQuery(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		expected := `sum(envoy_cluster_upstream_rq)`
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.True(t, strings.HasSuffix(r.URL.Path, "/api/v1/query_range"))
+			assert.Equal(t, expected, r.URL.Query().Get("query"))
+			assert.Equal(t, "1545905200", r.URL.Query().Get("start"))
+			assert.Equal(t, "1545905260", r.URL.Query().Get("end"))
+			assert.Equal(t, "10s", r.URL.Query().Get("step"))
+
+			json := `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1545905200,"1"],[1545905210,"NaN"],[1545905220,"2.5"]]}]}}`
+			w.Write([]byte(json))
+		}))
+		defer ts.Close()
+
+		clients := prometheusFake()
+
+		template, err := clients.flaggerClient.FlaggerV1beta1().MetricTemplates("default").Get(context.TODO(), "prometheus", metav1.GetOptions{})
+		require.NoError(t, err)
+		template.Spec.Provider.Address = ts.URL
+
+		secret, err := clients.kubeClient.CoreV1().Secrets("default").Get(context.TODO(), "prometheus", metav1.GetOptions{})
+		require.NoError(t, err)
+
+		prom, err := NewPrometheusProvider(template.Spec.Provider, secret.Data)
+		require.NoError(t, err)
+
+		end := time.Unix(1545905260, 0)
+		values, err := prom.RunRangeQuery(template.Spec.Query, end.Add(-time.Minute), end, 10*time.Second)
+		require.NoError(t, err)
+
+		assert.Equal(t, []float64{1, 2.5}, values)
+	})
+
+	errorTests := []struct {
+		name        string
+		queryResult string
+		wantErr     error
+	}{
+		{name: "no values result", queryResult: `{"status":"success","data":{"resultType":"matrix","result":[]}}`, wantErr: ErrNoValuesFound},
+		{name: "multiple series result", queryResult: `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{},"values":[[1545905200,"1"]]},{"metric":{},"values":[[1545905200,"2"]]}]}}`, wantErr: ErrMultipleValuesReturned},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tt.queryResult))
+			}))
+			defer ts.Close()
+
+			prom, err := NewPrometheusProvider(flaggerv1.MetricTemplateProvider{
+				Type:    "prometheus",
+				Address: ts.URL,
+			}, nil)
+			require.NoError(t, err)
+
+			end := time.Now()
+			_, err = prom.RunRangeQuery("sum(up)", end.Add(-time.Minute), end, 10*time.Second)
+			require.True(t, errors.Is(err, tt.wantErr))
+		})
+	}
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package providers
 
+import "time"
+
 type Interface interface {
 	// RunQuery executes the query and converts the first result to float64
 	RunQuery(query string) (float64, error)
@@ -23,3 +25,9 @@ type Interface interface {
 	// IsOnline calls the provider endpoint and returns an error if the API is unreachable
 	IsOnline() (bool, error)
 }
+
+// RangeInterface is implemented by the providers that can return the samples of a time series
+type RangeInterface interface {
+	// RunRangeQuery executes the query over the time window and returns the samples as float64
+	RunRangeQuery(query string, start time.Time, end time.Time, step time.Duration) ([]float64, error)
+}
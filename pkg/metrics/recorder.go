@@ -26,12 +26,18 @@ import (
 
 // Recorder records the canary analysis as Prometheus metrics
 type Recorder struct {
-	info     *prometheus.GaugeVec
-	duration *prometheus.HistogramVec
-	total    *prometheus.GaugeVec
-	status   *prometheus.GaugeVec
-	weight   *prometheus.GaugeVec
-	analysis *prometheus.GaugeVec
+	info       *prometheus.GaugeVec
+	duration   *prometheus.HistogramVec
+	total      *prometheus.GaugeVec
+	status     *prometheus.GaugeVec
+	weight     *prometheus.GaugeVec
+	analysis   *prometheus.GaugeVec
+	comparison *prometheus.GaugeVec
+	score      *prometheus.GaugeVec
+	queueDepth prometheus.Gauge
+	queueLag   prometheus.Histogram
+	frozen     *prometheus.GaugeVec
+	warmup     *prometheus.GaugeVec
 }
 
 // NewRecorder creates a new recorder and registers the Prometheus metrics
@@ -39,8 +45,8 @@ func NewRecorder(controller string, register bool) Recorder {
 	info := prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: controller,
 		Name:      "info",
-		Help:      "Flagger version and mesh provider information",
-	}, []string{"version", "mesh_provider"})
+		Help:      "Flagger version, mesh provider and shard information",
+	}, []string{"version", "mesh_provider", "shard"})
 
 	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Subsystem: controller,
@@ -53,7 +59,7 @@ func NewRecorder(controller string, register bool) Recorder {
 		Subsystem: controller,
 		Name:      "canary_total",
 		Help:      "Total number of canary object",
-	}, []string{"namespace"})
+	}, []string{"namespace", "shard"})
 
 	// 0 - running, 1 - successful, 2 - failed
 	status := prometheus.NewGaugeVec(prometheus.GaugeOpts{
@@ -74,6 +80,44 @@ func NewRecorder(controller string, register bool) Recorder {
 		Help:      "Last canary analysis result per metric",
 	}, []string{"name", "namespace", "metric"})
 
+	// series: primary, canary or delta
+	comparison := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: controller,
+		Name:      "canary_metric_comparison",
+		Help:      "Last canary vs primary comparison result per metric",
+	}, []string{"name", "namespace", "metric", "series"})
+
+	score := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: controller,
+		Name:      "canary_score",
+		Help:      "Last canary analysis score in the range of [0, 100]",
+	}, []string{"name", "namespace"})
+
+	queueDepth := prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: controller,
+		Name:      "scheduler_queue_depth",
+		Help:      "Number of canaries due for analysis waiting for a scheduler worker",
+	})
+
+	queueLag := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Subsystem: controller,
+		Name:      "scheduler_lag_seconds",
+		Help:      "Seconds between the scheduled time of a canary analysis and its start.",
+		Buckets:   []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+	})
+
+	frozen := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: controller,
+		Name:      "release_frozen",
+		Help:      "Release policy freeze state, 1 when new canary runs are held, 0 otherwise",
+	}, []string{"policy"})
+
+	warmup := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: controller,
+		Name:      "canary_warmup",
+		Help:      "Canary warm-up state, 1 while the canary warms up, 0 otherwise",
+	}, []string{"name", "namespace"})
+
 	if register {
 		prometheus.MustRegister(info)
 		prometheus.MustRegister(duration)
@@ -81,21 +125,33 @@ func NewRecorder(controller string, register bool) Recorder {
 		prometheus.MustRegister(status)
 		prometheus.MustRegister(weight)
 		prometheus.MustRegister(analysis)
+		prometheus.MustRegister(comparison)
+		prometheus.MustRegister(score)
+		prometheus.MustRegister(queueDepth)
+		prometheus.MustRegister(queueLag)
+		prometheus.MustRegister(frozen)
+		prometheus.MustRegister(warmup)
 	}
 
 	return Recorder{
-		info:     info,
-		duration: duration,
-		total:    total,
-		status:   status,
-		weight:   weight,
-		analysis: analysis,
+		info:       info,
+		duration:   duration,
+		total:      total,
+		status:     status,
+		weight:     weight,
+		analysis:   analysis,
+		comparison: comparison,
+		score:      score,
+		queueDepth: queueDepth,
+		queueLag:   queueLag,
+		frozen:     frozen,
+		warmup:     warmup,
 	}
 }
 
-// SetInfo sets the version and mesh provider labels
-func (cr *Recorder) SetInfo(version string, meshProvider string) {
-	cr.info.WithLabelValues(version, meshProvider).Set(1)
+// SetInfo sets the version, mesh provider and shard labels
+func (cr *Recorder) SetInfo(version string, meshProvider string, shard string) {
+	cr.info.WithLabelValues(version, meshProvider, shard).Set(1)
 }
 
 // SetDuration sets the time spent in seconds performing canary analysis
@@ -103,22 +159,36 @@ func (cr *Recorder) SetDuration(cd *flaggerv1.Canary, duration time.Duration) {
 	cr.duration.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace).Observe(duration.Seconds())
 }
 
-// SetTotal sets the total number of canaries per namespace
-func (cr *Recorder) SetTotal(namespace string, total int) {
-	cr.total.WithLabelValues(namespace).Set(float64(total))
+// SetTotal sets the total number of canaries per namespace managed by the shard
+func (cr *Recorder) SetTotal(namespace string, shard string, total int) {
+	cr.total.WithLabelValues(namespace, shard).Set(float64(total))
 }
 
 func (cr *Recorder) SetAnalysis(cd *flaggerv1.Canary, metricTemplateName string, val float64) {
 	cr.analysis.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace, metricTemplateName).Set(val)
 }
 
+// SetComparison sets the primary value, the canary value and the regression delta of a metric
+func (cr *Recorder) SetComparison(cd *flaggerv1.Canary, metricTemplateName string, primary float64, canary float64, delta float64) {
+	cr.comparison.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace, metricTemplateName, "primary").Set(primary)
+	cr.comparison.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace, metricTemplateName, "canary").Set(canary)
+	cr.comparison.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace, metricTemplateName, "delta").Set(delta)
+}
+
+// SetScore sets the last canary analysis score
+func (cr *Recorder) SetScore(cd *flaggerv1.Canary, score float64) {
+	cr.score.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace).Set(score)
+}
+
 // SetStatus sets the last known canary analysis status
 func (cr *Recorder) SetStatus(cd *flaggerv1.Canary, phase flaggerv1.CanaryPhase) {
 	var status int
 	switch phase {
 	case flaggerv1.CanaryPhaseProgressing:
 		status = 0
-	case flaggerv1.CanaryPhaseFailed:
+	case flaggerv1.CanaryPhaseVerifying:
+		status = 0
+	case flaggerv1.CanaryPhaseFailed, flaggerv1.CanaryPhaseReverted, flaggerv1.CanaryPhaseRestoring:
 		status = 2
 	default:
 		status = 1
@@ -131,3 +201,34 @@ func (cr *Recorder) SetWeight(cd *flaggerv1.Canary, primary int, canary int) {
 	cr.weight.WithLabelValues(fmt.Sprintf("%s-primary", cd.Spec.TargetRef.Name), cd.Namespace).Set(float64(primary))
 	cr.weight.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace).Set(float64(canary))
 }
+
+// SetWarmup sets the warm-up state of the canary
+func (cr *Recorder) SetWarmup(cd *flaggerv1.Canary, warmingUp bool) {
+	value := 0.0
+	if warmingUp {
+		value = 1
+	}
+	cr.warmup.WithLabelValues(cd.Spec.TargetRef.Name, cd.Namespace).Set(value)
+}
+
+// SetQueueDepth sets the number of canaries waiting for a scheduler worker
+func (cr *Recorder) SetQueueDepth(depth int) {
+	cr.queueDepth.Set(float64(depth))
+}
+
+// SetQueueLag records the delay between the scheduled time of a canary analysis and its start
+func (cr *Recorder) SetQueueLag(lag time.Duration) {
+	cr.queueLag.Observe(lag.Seconds())
+}
+
+// SetReleaseFrozen sets the freeze state of each release policy
+func (cr *Recorder) SetReleaseFrozen(state map[string]bool) {
+	cr.frozen.Reset()
+	for policy, frozen := range state {
+		value := 0.0
+		if frozen {
+			value = 1
+		}
+		cr.frozen.WithLabelValues(policy).Set(value)
+	}
+}
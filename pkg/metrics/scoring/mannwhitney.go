@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scoring
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrNotEnoughSamples is returned when one of the samples is empty
+var ErrNotEnoughSamples = errors.New("not enough samples")
+
+// MannWhitneyU runs a two-sided Mann-Whitney U test on the control and experiment samples
+// and returns the U statistic of the control sample along with the p-value.
+// The p-value is computed using the normal approximation with tie and continuity correction.
+func MannWhitneyU(control []float64, experiment []float64) (float64, float64, error) {
+	n1, n2 := float64(len(control)), float64(len(experiment))
+	if n1 == 0 || n2 == 0 {
+		return 0, 1, ErrNotEnoughSamples
+	}
+
+	type sample struct {
+		value   float64
+		control bool
+	}
+	samples := make([]sample, 0, len(control)+len(experiment))
+	for _, v := range control {
+		samples = append(samples, sample{value: v, control: true})
+	}
+	for _, v := range experiment {
+		samples = append(samples, sample{value: v})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].value < samples[j].value
+	})
+
+	// assign average ranks to ties and accumulate the tie correction term
+	var rankSum, ties float64
+	for i := 0; i < len(samples); {
+		j := i
+		for j < len(samples) && samples[j].value == samples[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if samples[k].control {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n := n1 + n2
+	u := rankSum - n1*(n1+1)/2
+	mu := n1 * n2 / 2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 || math.IsNaN(sigma) {
+		return u, 1, nil
+	}
+
+	z := (math.Abs(u-mu) - 0.5) / sigma
+	if z < 0 {
+		z = 0
+	}
+	p := math.Erfc(z / math.Sqrt2)
+
+	return u, p, nil
+}
+
+// Median returns the median of the samples or NaN if there are none
+func Median(samples []float64) float64 {
+	if len(samples) == 0 {
+		return math.NaN()
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scoring
+
+// Classification is the outcome of comparing the canary samples of a metric against the primary ones
+type Classification string
+
+const (
+	// Pass means there is no statistically significant difference between primary and canary
+	Pass Classification = "Pass"
+	// High means the canary values are significantly higher than the primary ones
+	High Classification = "High"
+	// Low means the canary values are significantly lower than the primary ones
+	Low Classification = "Low"
+	// NoData means one of the time series returned no samples
+	NoData Classification = "NoData"
+)
+
+// Verdict is the outcome of scoring all the metrics of an analysis run
+type Verdict string
+
+const (
+	VerdictPass     Verdict = "Pass"
+	VerdictMarginal Verdict = "Marginal"
+	VerdictFail     Verdict = "Fail"
+)
+
+// Classify compares the control and experiment samples with a Mann-Whitney U test
+// at the given confidence level (e.g. 0.95). The direction of a significant difference
+// is given by the U statistic, the medians can be equal while the ranks are shifted.
+func Classify(control []float64, experiment []float64, confidenceLevel float64) (Classification, float64) {
+	u, p, err := MannWhitneyU(control, experiment)
+	if err != nil {
+		return NoData, p
+	}
+	if p >= 1-confidenceLevel {
+		return Pass, p
+	}
+	// the control U is below its mean when the control values rank lower than the experiment ones
+	if u < float64(len(control)*len(experiment))/2 {
+		return High, p
+	}
+	return Low, p
+}
+
+// Score returns the percentage of passed metrics
+func Score(passed int, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(passed) / float64(total) * 100
+}
+
+// Judge turns the score into a verdict based on the pass and marginal thresholds
+func Judge(score float64, pass float64, marginal float64) Verdict {
+	switch {
+	case score >= pass:
+		return VerdictPass
+	case score >= marginal:
+		return VerdictMarginal
+	default:
+		return VerdictFail
+	}
+}
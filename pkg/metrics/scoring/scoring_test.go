@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	u, p, err := MannWhitneyU([]float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10})
+	require.NoError(t, err)
+	assert.Equal(t, float64(0), u)
+	assert.InDelta(t, 0.0122, p, 0.0001)
+
+	u, p, err = MannWhitneyU([]float64{1, 2, 2, 3}, []float64{1, 2, 2, 3})
+	require.NoError(t, err)
+	assert.Equal(t, float64(8), u)
+	assert.Equal(t, float64(1), p)
+
+	_, p, err = MannWhitneyU([]float64{5, 5, 5}, []float64{5, 5})
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), p)
+
+	_, _, err = MannWhitneyU(nil, []float64{1})
+	require.ErrorIs(t, err, ErrNotEnoughSamples)
+}
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, float64(2), Median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, Median([]float64{4, 1, 3, 2}))
+	assert.True(t, Median(nil) != Median(nil))
+}
+
+func TestClassify(t *testing.T) {
+	control := []float64{1, 2, 3, 4, 5}
+
+	c, _ := Classify(control, []float64{6, 7, 8, 9, 10}, 0.95)
+	assert.Equal(t, High, c)
+
+	c, _ = Classify([]float64{6, 7, 8, 9, 10}, control, 0.95)
+	assert.Equal(t, Low, c)
+
+	c, _ = Classify(control, []float64{2, 3, 4, 5, 6}, 0.95)
+	assert.Equal(t, Pass, c)
+
+	c, _ = Classify(control, nil, 0.95)
+	assert.Equal(t, NoData, c)
+}
+
+func TestClassify_TiedMedians(t *testing.T) {
+	// both medians are 5 but the canary values rank higher
+	control := []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}
+	experiment := []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9}
+	require.Equal(t, Median(control), Median(experiment))
+
+	c, p := Classify(control, experiment, 0.95)
+	assert.Less(t, p, 0.05)
+	assert.Equal(t, High, c)
+
+	c, _ = Classify(experiment, control, 0.95)
+	assert.Equal(t, Low, c)
+
+	// identical samples are never significant
+	c, _ = Classify(control, control, 0.95)
+	assert.Equal(t, Pass, c)
+}
+
+func TestJudge(t *testing.T) {
+	assert.Equal(t, VerdictPass, Judge(Score(4, 4), 95, 75))
+	assert.Equal(t, VerdictMarginal, Judge(Score(4, 5), 95, 75))
+	assert.Equal(t, VerdictFail, Judge(Score(1, 2), 95, 75))
+	assert.Equal(t, VerdictPass, Judge(Score(0, 0), 95, 75))
+}
@@ -72,6 +72,24 @@ func (ir *IstioRouter) Reconcile(canary *flaggerv1.Canary) error {
 	return nil
 }
 
+// ReconcileBaseline creates or updates the baseline destination rule
+func (ir *IstioRouter) ReconcileBaseline(canary *flaggerv1.Canary) error {
+	if err := ir.reconcileDestinationRule(canary, canary.GetBaselineServiceName()); err != nil {
+		return fmt.Errorf("reconcileDestinationRule failed: %w", err)
+	}
+	return nil
+}
+
+// FinalizeBaseline deletes the baseline destination rule
+func (ir *IstioRouter) FinalizeBaseline(canary *flaggerv1.Canary) error {
+	name := canary.GetBaselineServiceName()
+	err := ir.istioClient.NetworkingV1beta1().DestinationRules(canary.Namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("DestinationRule %s.%s delete error: %w", name, canary.Namespace, err)
+	}
+	return nil
+}
+
 func (ir *IstioRouter) reconcileDestinationRule(canary *flaggerv1.Canary, name string) error {
 	newSpec := istiov1beta1.DestinationRuleSpec{
 		Host:          name,
@@ -303,6 +321,13 @@ func (ir *IstioRouter) reconcileVirtualService(canary *flaggerv1.Canary) error {
 		cmpopts.IgnoreFields(istiov1beta1.HTTPRouteDestination{}, "Weight"),
 		cmpopts.IgnoreFields(istiov1beta1.HTTPRoute{}, "Mirror", "MirrorPercentage"),
 	}
+	if canary.HasBaseline() {
+		// The baseline destination is added and removed by SetRoutes()
+		baselineName := canary.GetBaselineServiceName()
+		ignoreCmpOptions = append(ignoreCmpOptions, cmpopts.IgnoreSliceElements(func(d istiov1beta1.HTTPRouteDestination) bool {
+			return d.Destination.Host == baselineName
+		}))
+	}
 	if canary.Spec.Analysis.SessionAffinity != nil {
 		// We ignore this route as this does not do weighted routing and is handled exclusively
 		// by SetRoutes().
@@ -374,6 +399,7 @@ func (ir *IstioRouter) GetRoutes(canary *flaggerv1.Canary) (
 	err error,
 ) {
 	apexName, primaryName, canaryName := canary.GetServiceNames()
+	baselineName := canary.GetBaselineServiceName()
 	vs := &istiov1beta1.VirtualService{}
 	vs, err = ir.istioClient.NetworkingV1beta1().VirtualServices(canary.Namespace).Get(context.TODO(), apexName, metav1.GetOptions{})
 	if err != nil {
@@ -393,8 +419,8 @@ func (ir *IstioRouter) GetRoutes(canary *flaggerv1.Canary) (
 			}
 		}
 		for _, route := range tcpRoute.Route {
-			if route.Destination.Host == primaryName {
-				primaryWeight = route.Weight
+			if route.Destination.Host == primaryName || route.Destination.Host == baselineName {
+				primaryWeight += route.Weight
 			}
 			if route.Destination.Host == canaryName {
 				canaryWeight = route.Weight
@@ -424,8 +450,8 @@ func (ir *IstioRouter) GetRoutes(canary *flaggerv1.Canary) (
 	}
 
 	for _, route := range httpRoute.Route {
-		if route.Destination.Host == primaryName {
-			primaryWeight = route.Weight
+		if route.Destination.Host == primaryName || route.Destination.Host == baselineName {
+			primaryWeight += route.Weight
 		}
 		if route.Destination.Host == canaryName {
 			canaryWeight = route.Weight
@@ -480,10 +506,7 @@ func (ir *IstioRouter) SetRoutes(
 		// weighted routing (progressive canary)
 		weightedRoute := istiov1beta1.TCPRoute{
 			Match: canaryToL4Match(canary),
-			Route: []istiov1beta1.HTTPRouteDestination{
-				makeDestination(canary, primaryName, primaryWeight),
-				makeDestination(canary, canaryName, canaryWeight),
-			},
+			Route: makeWeightedDestinations(canary, primaryWeight, canaryWeight),
 		}
 		vsCopy.Spec.Tcp = []istiov1beta1.TCPRoute{
 			weightedRoute,
@@ -504,10 +527,7 @@ func (ir *IstioRouter) SetRoutes(
 		Retries:    canary.Spec.Service.Retries,
 		CorsPolicy: canary.Spec.Service.CorsPolicy,
 		Headers:    canary.Spec.Service.Headers,
-		Route: []istiov1beta1.HTTPRouteDestination{
-			makeDestination(canary, primaryName, primaryWeight),
-			makeDestination(canary, canaryName, canaryWeight),
-		},
+		Route:      makeWeightedDestinations(canary, primaryWeight, canaryWeight),
 	}
 	vsCopy.Spec.Http = []istiov1beta1.HTTPRoute{
 		weightedRoute,
@@ -706,6 +726,29 @@ func mergeMatchConditions(canary, defaults []istiov1beta1.HTTPMatchRequest) []is
 	return merged
 }
 
+// makeWeightedDestinations returns the primary and canary destinations, when the baseline is enabled
+// it receives the same weight as the canary taken out of the primary weight
+func makeWeightedDestinations(canary *flaggerv1.Canary, primaryWeight int, canaryWeight int) []istiov1beta1.HTTPRouteDestination {
+	_, primaryName, canaryName := canary.GetServiceNames()
+	if !canary.HasBaseline() || canaryWeight == 0 {
+		return []istiov1beta1.HTTPRouteDestination{
+			makeDestination(canary, primaryName, primaryWeight),
+			makeDestination(canary, canaryName, canaryWeight),
+		}
+	}
+
+	// above 50% the baseline can't match the canary weight
+	baselineWeight := canaryWeight
+	if baselineWeight > primaryWeight {
+		baselineWeight = primaryWeight
+	}
+	return []istiov1beta1.HTTPRouteDestination{
+		makeDestination(canary, primaryName, primaryWeight-baselineWeight),
+		makeDestination(canary, canary.GetBaselineServiceName(), baselineWeight),
+		makeDestination(canary, canaryName, canaryWeight),
+	}
+}
+
 // makeDestination returns a an destination weight for the specified host
 func makeDestination(canary *flaggerv1.Canary, host string, weight int) istiov1beta1.HTTPRouteDestination {
 	dest := istiov1beta1.HTTPRouteDestination{
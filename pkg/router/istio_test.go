@@ -26,6 +26,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/fluxcd/flagger/pkg/apis/flagger/v1beta1"
@@ -442,6 +443,69 @@ func TestIstioRouter_GetRoutes(t *testing.T) {
 	assert.True(t, m)
 }
 
+func TestIstioRouter_Baseline(t *testing.T) {
+	mocks := newFixture(nil)
+	mocks.canary.Spec.Analysis.Baseline = true
+	router := &IstioRouter{
+		logger:        mocks.logger,
+		flaggerClient: mocks.flaggerClient,
+		istioClient:   mocks.meshClient,
+		kubeClient:    mocks.kubeClient,
+	}
+
+	err := router.Reconcile(mocks.canary)
+	require.NoError(t, err)
+
+	// the baseline destination rule is created with the baseline workload
+	_, err = mocks.meshClient.NetworkingV1beta1().DestinationRules("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	err = router.ReconcileBaseline(mocks.canary)
+	require.NoError(t, err)
+
+	_, err = mocks.meshClient.NetworkingV1beta1().DestinationRules("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	err = router.SetRoutes(mocks.canary, 60, 40, false)
+	require.NoError(t, err)
+
+	vs, err := mocks.meshClient.NetworkingV1beta1().VirtualServices("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	weights := map[string]int{}
+	for _, route := range vs.Spec.Http[0].Route {
+		weights[route.Destination.Host] = route.Weight
+	}
+	assert.Equal(t, map[string]int{"podinfo-primary": 20, "podinfo-baseline": 40, "podinfo-canary": 40}, weights)
+
+	// reconcile keeps the baseline destination
+	err = router.Reconcile(mocks.canary)
+	require.NoError(t, err)
+
+	p, c, _, err := router.GetRoutes(mocks.canary)
+	require.NoError(t, err)
+	assert.Equal(t, 60, p)
+	assert.Equal(t, 40, c)
+
+	// the baseline destination is removed when the canary weight is zero
+	err = router.SetRoutes(mocks.canary, 100, 0, false)
+	require.NoError(t, err)
+
+	vs, err = mocks.meshClient.NetworkingV1beta1().VirtualServices("default").Get(context.TODO(), "podinfo", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Len(t, vs.Spec.Http[0].Route, 2)
+
+	err = router.FinalizeBaseline(mocks.canary)
+	require.NoError(t, err)
+
+	_, err = mocks.meshClient.NetworkingV1beta1().DestinationRules("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// deleting a missing destination rule is a no-op
+	err = router.FinalizeBaseline(mocks.canary)
+	require.NoError(t, err)
+}
+
 func TestIstioRouter_HTTPRequestHeaders(t *testing.T) {
 	mocks := newFixture(nil)
 	router := &IstioRouter{
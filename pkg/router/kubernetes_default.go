@@ -56,7 +56,7 @@ func (c *KubernetesDefaultRouter) Initialize(canary *flaggerv1.Canary) error {
 	}
 
 	// primary svc
-	err = c.reconcileService(canary, primaryName, fmt.Sprintf("%s-primary", c.labelValue), canary.Spec.Service.Primary)
+	err = c.reconcileService(canary, primaryName, c.primarySelector(canary), canary.Spec.Service.Primary)
 	if err != nil {
 		return fmt.Errorf("reconcileService failed: %w", err)
 	}
@@ -64,12 +64,31 @@ func (c *KubernetesDefaultRouter) Initialize(canary *flaggerv1.Canary) error {
 	return nil
 }
 
+// ReconcileBaseline creates or updates the baseline service
+func (c *KubernetesDefaultRouter) ReconcileBaseline(canary *flaggerv1.Canary) error {
+	err := c.reconcileService(canary, canary.GetBaselineServiceName(), fmt.Sprintf("%s-baseline", c.labelValue), nil)
+	if err != nil {
+		return fmt.Errorf("reconcileService failed: %w", err)
+	}
+	return nil
+}
+
+// FinalizeBaseline deletes the baseline service
+func (c *KubernetesDefaultRouter) FinalizeBaseline(canary *flaggerv1.Canary) error {
+	name := canary.GetBaselineServiceName()
+	err := c.kubeClient.CoreV1().Services(canary.Namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("service %s.%s delete error: %w", name, canary.Namespace, err)
+	}
+	return nil
+}
+
 // Reconcile creates or updates the main service
 func (c *KubernetesDefaultRouter) Reconcile(canary *flaggerv1.Canary) error {
 	apexName, _, _ := canary.GetServiceNames()
 
 	// main svc
-	err := c.reconcileService(canary, apexName, fmt.Sprintf("%s-primary", c.labelValue), canary.Spec.Service.Apex)
+	err := c.reconcileService(canary, apexName, c.primarySelector(canary), canary.Spec.Service.Apex)
 	if err != nil {
 		return fmt.Errorf("reconcileService failed: %w", err)
 	}
@@ -77,6 +96,15 @@ func (c *KubernetesDefaultRouter) Reconcile(canary *flaggerv1.Canary) error {
 	return nil
 }
 
+// primarySelector returns the pod selector of the apex and primary services,
+// the canary pods are selected while a stalled primary rollout is restored
+func (c *KubernetesDefaultRouter) primarySelector(canary *flaggerv1.Canary) string {
+	if canary.Status.SelectorSwapped {
+		return c.labelValue
+	}
+	return fmt.Sprintf("%s-primary", c.labelValue)
+}
+
 func (c *KubernetesDefaultRouter) SetRoutes(_ *flaggerv1.Canary, _ int, _ int) error {
 	return nil
 }
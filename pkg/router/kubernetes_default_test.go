@@ -64,6 +64,40 @@ func TestServiceRouter_Create(t *testing.T) {
 	assert.Equal(t, "None", primarySvc.Spec.ClusterIP)
 }
 
+func TestServiceRouter_CreateBaseline(t *testing.T) {
+	mocks := newFixture(nil)
+	mocks.canary.Spec.Analysis.Baseline = true
+
+	router := &KubernetesDefaultRouter{
+		kubeClient:    mocks.kubeClient,
+		flaggerClient: mocks.flaggerClient,
+		logger:        mocks.logger,
+		labelSelector: "app",
+		labelValue:    "podinfo",
+	}
+
+	err := router.Initialize(mocks.canary)
+	require.NoError(t, err)
+
+	// the baseline service is created with the baseline workload
+	_, err = mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	err = router.ReconcileBaseline(mocks.canary)
+	require.NoError(t, err)
+
+	baselineSvc, err := mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "podinfo-baseline", baselineSvc.Spec.Selector["app"])
+	assert.Equal(t, int32(9898), baselineSvc.Spec.Ports[0].Port)
+
+	err = router.FinalizeBaseline(mocks.canary)
+	require.NoError(t, err)
+
+	_, err = mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), "podinfo-baseline", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
 func TestServiceRouter_Update(t *testing.T) {
 	mocks := newFixture(nil)
 	router := &KubernetesDefaultRouter{
@@ -98,6 +132,43 @@ func TestServiceRouter_Update(t *testing.T) {
 	assert.Equal(t, "grpc", canarySvc.Spec.Ports[0].Name)
 }
 
+func TestServiceRouter_SelectorSwap(t *testing.T) {
+	mocks := newFixture(nil)
+	router := &KubernetesDefaultRouter{
+		kubeClient:    mocks.kubeClient,
+		flaggerClient: mocks.flaggerClient,
+		logger:        mocks.logger,
+		labelSelector: "app",
+		labelValue:    "podinfo",
+	}
+
+	// point the apex and primary services to the canary pods
+	mocks.canary.Status.SelectorSwapped = true
+	err := router.Initialize(mocks.canary)
+	require.NoError(t, err)
+	err = router.Reconcile(mocks.canary)
+	require.NoError(t, err)
+
+	for _, name := range []string{"podinfo", "podinfo-primary", "podinfo-canary"} {
+		svc, err := mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "podinfo", svc.Spec.Selector["app"], name)
+	}
+
+	// point them back to the primary pods
+	mocks.canary.Status.SelectorSwapped = false
+	err = router.Initialize(mocks.canary)
+	require.NoError(t, err)
+	err = router.Reconcile(mocks.canary)
+	require.NoError(t, err)
+
+	for _, name := range []string{"podinfo", "podinfo-primary"} {
+		svc, err := mocks.kubeClient.CoreV1().Services("default").Get(context.TODO(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "podinfo-primary", svc.Spec.Selector["app"], name)
+	}
+}
+
 func TestServiceRouter_Undo(t *testing.T) {
 	mocks := newFixture(nil)
 	router := &KubernetesDefaultRouter{
@@ -27,3 +27,12 @@ type Interface interface {
 	GetRoutes(canary *flaggerv1.Canary) (primaryWeight int, canaryWeight int, mirrored bool, err error)
 	Finalize(canary *flaggerv1.Canary) error
 }
+
+// BaselineRouter is implemented by the routers that manage
+// a service or a destination rule for the baseline workload
+type BaselineRouter interface {
+	// ReconcileBaseline creates or updates the baseline routing objects
+	ReconcileBaseline(canary *flaggerv1.Canary) error
+	// FinalizeBaseline deletes the baseline routing objects
+	FinalizeBaseline(canary *flaggerv1.Canary) error
+}